@@ -0,0 +1,142 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/blobarchive"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/internal/flags"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+var (
+	blobsCommand = &cli.Command{
+		Name:  "blobs",
+		Usage: "A set of commands for the blob sidecar archives",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "export",
+				Usage:     "Export blob sidecars into archives",
+				ArgsUsage: "<dir> <first> <last>",
+				Action:    exportBlobs,
+				Flags:     flags.Merge(utils.DatabaseFlags),
+				Description: `
+geth blobs export <dir> <first> <last>
+
+Exports the blob sidecars of the blocks from first to last into archives of
+the directory. An archive holds the sidecars of an epoch of 8192 blocks, the
+range is extended to the enclosing epochs. The sidecars of the exported blocks
+must still be in the database.`,
+			},
+			{
+				Name:      "import",
+				Usage:     "Verify blob sidecar archives and install them in the node archive",
+				ArgsUsage: "<dir>",
+				Action:    importBlobs,
+				Flags:     flags.Merge([]cli.Flag{utils.BlobArchiveFlag}, utils.DatabaseFlags),
+				Description: `
+geth blobs import <dir>
+
+Verifies the archives of the directory against the canonical chain, checking
+the sidecars against the blob transactions and their KZG proofs, and copies
+them into the directory given by --blob.archive. Served through the RPC with
+--blob.archive.rpc once the sidecars are pruned from the database.`,
+			},
+		},
+	}
+)
+
+// blobsNetwork returns the network name used in the archive file names.
+func blobsNetwork(db ethdb.Database) (string, error) {
+	config := rawdb.ReadChainConfig(db, rawdb.ReadCanonicalHash(db, 0))
+	if config == nil {
+		return "", errors.New("chain config not found")
+	}
+	return blobarchive.NetworkName(config), nil
+}
+
+func exportBlobs(ctx *cli.Context) error {
+	if ctx.Args().Len() != 3 {
+		utils.Fatalf("usage: %s", ctx.Command.ArgsUsage)
+	}
+	first, ferr := strconv.ParseUint(ctx.Args().Get(1), 10, 64)
+	last, lerr := strconv.ParseUint(ctx.Args().Get(2), 10, 64)
+	if ferr != nil || lerr != nil || first > last {
+		utils.Fatalf("Export error: invalid block range %s-%s", ctx.Args().Get(1), ctx.Args().Get(2))
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, true, false)
+	defer db.Close()
+
+	network, err := blobsNetwork(db)
+	if err != nil {
+		return err
+	}
+	dir := ctx.Args().First()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("error creating output directory: %w", err)
+	}
+	start := time.Now()
+	for epoch := first / blobarchive.EpochSize; epoch <= last/blobarchive.EpochSize; epoch++ {
+		path, err := blobarchive.ExportEpoch(db, dir, network, epoch)
+		if err != nil {
+			return fmt.Errorf("export failed on epoch %d: %w", epoch, err)
+		}
+		log.Info("Exported blob sidecars", "epoch", epoch, "path", path, "elapsed", common.PrettyDuration(time.Since(start)))
+	}
+	fmt.Printf("Export done in %v\n", time.Since(start))
+	return nil
+}
+
+func importBlobs(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		utils.Fatalf("usage: %s", ctx.Command.ArgsUsage)
+	}
+	stack, config := makeConfigNode(ctx)
+	defer stack.Close()
+
+	if config.Eth.BlobArchiveDir == "" {
+		utils.Fatalf("Import error: --%s must be set", utils.BlobArchiveFlag.Name)
+	}
+	archiveDir := stack.ResolvePath(config.Eth.BlobArchiveDir)
+	if err := os.MkdirAll(archiveDir, 0755); err != nil {
+		return err
+	}
+	db := utils.MakeChainDatabase(ctx, stack, true, false)
+	defer db.Close()
+
+	network, err := blobsNetwork(db)
+	if err != nil {
+		return err
+	}
+	dir := ctx.Args().First()
+	epochs, err := blobarchive.ReadDir(dir, network)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", dir, err)
+	}
+	if len(epochs) == 0 {
+		return fmt.Errorf("no %s blob archive found in %s", network, dir)
+	}
+	start := time.Now()
+	for _, epoch := range epochs {
+		path, err := blobarchive.ImportArchive(db, filepath.Join(dir, blobarchive.Filename(network, epoch)), archiveDir, network)
+		if err != nil {
+			return fmt.Errorf("import failed on epoch %d: %w", epoch, err)
+		}
+		log.Info("Imported blob sidecars", "epoch", epoch, "path", path, "elapsed", common.PrettyDuration(time.Since(start)))
+	}
+	fmt.Printf("Import done in %v\n", time.Since(start))
+	return nil
+}
@@ -641,12 +641,24 @@ func importHistory(ctx *cli.Context) error {
 	defer db.Close()
 
 	var (
-		start   = time.Now()
-		dir     = ctx.Args().Get(0)
-		network string
+		start = time.Now()
+		dir   = ctx.Args().Get(0)
 	)
+	network, err := eraNetwork(ctx, dir)
+	if err != nil {
+		return err
+	}
+	if err := utils.ImportHistory(chain, db, dir, network); err != nil {
+		return err
+	}
+	fmt.Printf("Import done in %v\n", time.Since(start))
+	return nil
+}
 
-	// Determine network.
+// eraNetwork returns the network of the Era archives in the directory, either
+// from the network flags or from the archives present.
+func eraNetwork(ctx *cli.Context, dir string) (string, error) {
+	var network string
 	if utils.IsNetworkPreset(ctx) {
 		switch {
 		case ctx.Bool(utils.BSCMainnetFlag.Name):
@@ -661,26 +673,21 @@ func importHistory(ctx *cli.Context) error {
 		for _, n := range params.NetworkNames {
 			entries, err := era.ReadDir(dir, n)
 			if err != nil {
-				return fmt.Errorf("error reading %s: %w", dir, err)
+				return "", fmt.Errorf("error reading %s: %w", dir, err)
 			}
 			if len(entries) > 0 {
 				networks = append(networks, n)
 			}
 		}
 		if len(networks) == 0 {
-			return fmt.Errorf("no era1 files found in %s", dir)
+			return "", fmt.Errorf("no era1 files found in %s", dir)
 		}
 		if len(networks) > 1 {
-			return fmt.Errorf("multiple networks found, use a network flag to specify desired network")
+			return "", fmt.Errorf("multiple networks found, use a network flag to specify desired network")
 		}
 		network = networks[0]
 	}
-
-	if err := utils.ImportHistory(chain, db, dir, network); err != nil {
-		return err
-	}
-	fmt.Printf("Import done in %v\n", time.Since(start))
-	return nil
+	return network, nil
 }
 
 // exportHistory exports chain history in Era archives at a specified
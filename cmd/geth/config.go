@@ -37,6 +37,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/eth/tracers/native"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/internal/flags"
 	"github.com/ethereum/go-ethereum/internal/version"
@@ -206,6 +207,14 @@ func makeFullNode(ctx *cli.Context) (*node.Node, ethapi.Backend) {
 	if ctx.IsSet(utils.OverrideBreatheBlockInterval.Name) {
 		params.BreatheBlockInterval = ctx.Uint64(utils.OverrideBreatheBlockInterval.Name)
 	}
+	if ctx.IsSet(utils.VMStorageLayoutsFlag.Name) {
+		dir := stack.ResolvePath(ctx.String(utils.VMStorageLayoutsFlag.Name))
+		n, err := native.LoadStorageLayouts(dir)
+		if err != nil {
+			utils.Fatalf("Failed to load storage layouts: %v", err)
+		}
+		log.Info("Loaded storage layouts", "dir", dir, "count", n)
+	}
 
 	backend, eth := utils.RegisterEthService(stack, &cfg.Eth)
 
@@ -230,6 +239,10 @@ func makeFullNode(ctx *cli.Context) (*node.Node, ethapi.Backend) {
 	if ctx.IsSet(utils.GraphQLEnabledFlag.Name) {
 		utils.RegisterGraphQLService(stack, backend, filterSystem, &cfg.Node)
 	}
+	// Add the slashing evidence service if any monitor is enabled.
+	if eth != nil {
+		utils.RegisterEvidenceService(stack, eth, &cfg.Node)
+	}
 	// Add the Ethereum Stats daemon if requested.
 	if cfg.Ethstats.URL != "" {
 		utils.RegisterEthStatsService(stack, backend, cfg.Ethstats.URL)
@@ -91,6 +91,8 @@ var (
 		utils.TxPoolGlobalQueueFlag,
 		utils.TxPoolLifetimeFlag,
 		utils.TxPoolReannounceTimeFlag,
+		utils.TxPoolBlacklistFlag,
+		utils.TxPoolSnapshotFlag,
 		utils.BlobPoolDataDirFlag,
 		utils.BlobPoolDataCapFlag,
 		utils.BlobPoolPriceBumpFlag,
@@ -144,6 +146,10 @@ var (
 		utils.MinerExtraDataFlag,
 		utils.MinerRecommitIntervalFlag,
 		utils.MinerDelayLeftoverFlag,
+		utils.MinerSkipBlacklistedFlag,
+		utils.MevBidJournalFlag,
+		utils.MevBidJournalRetentionFlag,
+		utils.MevForwardPrivateTxsFlag,
 		// utils.MinerNewPayloadTimeout,
 		utils.NATFlag,
 		utils.NoDiscoverFlag,
@@ -160,6 +166,10 @@ var (
 		utils.DeveloperGasLimitFlag,
 		utils.DeveloperPeriodFlag,
 		utils.VMEnableDebugFlag,
+		utils.VMStorageLayoutsFlag,
+		utils.TraceCacheFlag,
+		utils.TraceCacheTracersFlag,
+		utils.TraceFilterRangeFlag,
 		utils.NetworkIdFlag,
 		utils.EthStatsURLFlag,
 		utils.NoCompactionFlag,
@@ -171,15 +181,24 @@ var (
 		utils.BlockAmountReserved,
 		utils.CheckSnapshotWithMPT,
 		utils.EnableDoubleSignMonitorFlag,
+		utils.EvidenceReporterFlag,
+		utils.EvidenceGasLimitFlag,
+		utils.EvidenceGasPriceFlag,
+		utils.EvidenceDryRunFlag,
 		utils.VotingEnabledFlag,
 		utils.DisableVoteAttestationFlag,
 		utils.EnableMaliciousVoteMonitorFlag,
 		utils.BLSPasswordFileFlag,
 		utils.BLSWalletDirFlag,
+		utils.BLSRemoteSignerFlag,
+		utils.BLSRemoteSignerKeyFlag,
 		utils.VoteJournalDirFlag,
 		utils.LogDebugFlag,
 		utils.LogBacktraceAtFlag,
 		utils.BlobExtraReserveFlag,
+		utils.BlobArchiveFlag,
+		utils.BlobArchiveRPCFlag,
+		utils.BlobArchiveRetentionFlag,
 	}, utils.NetworkFlags, utils.DatabaseFlags)
 
 	rpcFlags = []cli.Flag{
@@ -209,6 +228,7 @@ var (
 		utils.RPCGlobalGasCapFlag,
 		utils.RPCGlobalEVMTimeoutFlag,
 		utils.RPCGlobalTxFeeCapFlag,
+		utils.RPCPrivateTxLifetimeFlag,
 		utils.AllowUnprotectedTxs,
 		utils.BatchRequestLimit,
 		utils.BatchResponseMaxSize,
@@ -270,6 +290,12 @@ func init() {
 		// See snapshot.go
 		snapshotCommand,
 		blsCommand,
+		// See mevcmd.go
+		mevCommand,
+		// See votecmd.go
+		voteCommand,
+		// See blobscmd.go
+		blobsCommand,
 		// See verkle.go
 		verkleCommand,
 	}
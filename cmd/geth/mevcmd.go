@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/internal/flags"
+	"github.com/ethereum/go-ethereum/miner"
+)
+
+var (
+	bidJournalDirFlag = &cli.StringFlag{
+		Name:     "journal",
+		Usage:    "Directory of the bid journal (default = --mev.bidjournal, or 'bidjournal' inside the datadir)",
+		Category: flags.MinerCategory,
+	}
+)
+
+var (
+	mevCommand = &cli.Command{
+		Name:  "mev",
+		Usage: "A set of commands for inspecting the bids received by the validator",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "replay",
+				Usage:     "Re-simulate the journaled bids of a past block",
+				ArgsUsage: "<blockNumber>",
+				Action:    mevReplay,
+				Flags: flags.Merge([]cli.Flag{
+					bidJournalDirFlag,
+				}, utils.NetworkFlags, utils.DatabaseFlags),
+				Description: `
+geth mev replay <blockNumber>
+
+Reads the bid journal of the given block, re-simulates every arrived bid on top
+of the parent state and compares the outcome with the journaled simulation and
+the final choice. The parent state must still be available in the database.
+Transactions greedily merged from the mempool are not replayed.`,
+			},
+		},
+	}
+)
+
+func mevReplay(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		utils.Fatalf("This command requires a block number argument.")
+	}
+	number, err := strconv.ParseUint(ctx.Args().First(), 10, 64)
+	if err != nil {
+		utils.Fatalf("Invalid block number: %v", err)
+	}
+
+	stack, cfg := makeConfigNode(ctx)
+	defer stack.Close()
+
+	dir := ctx.String(bidJournalDirFlag.Name)
+	if dir == "" {
+		dir = cfg.Eth.Miner.Mev.BidJournalDir
+	}
+	if dir == "" {
+		dir = "bidjournal"
+	}
+	dir = stack.ResolvePath(dir)
+
+	entries, err := miner.ReadBidJournal(dir, number)
+	if err != nil {
+		utils.Fatalf("Failed to read bid journal: %v", err)
+	}
+
+	chain, db := utils.MakeChain(ctx, stack, true)
+	defer db.Close()
+
+	results := miner.ReplayBids(chain, cfg.Eth.Miner.Mev.ValidatorCommission, entries)
+	fmt.Printf("Replayed %d bids of block %d from %s\n", len(results), number, miner.BidJournalPath(dir, number))
+
+	for _, result := range results {
+		fmt.Printf("\nbid %s from builder %s\n", result.BidHash, result.Builder)
+		if recorded := result.Recorded; recorded != nil {
+			fmt.Printf("  journaled: txs=%d blockReward=%v validatorReward=%v best=%v", recorded.TxCount,
+				recorded.PackedBlockReward, recorded.PackedValidatorReward, recorded.Best)
+			if recorded.Error != "" {
+				fmt.Printf(" error=%q", recorded.Error)
+			}
+			fmt.Println()
+		} else if result.Rejected != "" {
+			fmt.Printf("  journaled: rejected on arrival, error=%q\n", result.Rejected)
+		} else {
+			fmt.Println("  journaled: never simulated")
+		}
+		fmt.Printf("  replayed:  txs=%d gasUsed=%d blockReward=%v validatorReward=%v", result.TxCount,
+			result.GasUsed, result.PackedBlockReward, result.PackedValidatorReward)
+		if result.Err != nil {
+			fmt.Printf(" error=%q", result.Err)
+		}
+		fmt.Println()
+	}
+	for _, entry := range entries {
+		if entry.Kind != miner.BidJournalChoice {
+			continue
+		}
+		fmt.Printf("\nchoice at %s: best bid %s from builder %s blockReward=%v, local blockReward=%v, bid chosen=%v\n",
+			entry.Time.Format("15:04:05.000"), entry.BidHash, entry.Builder, entry.PackedBlockReward,
+			entry.LocalBlockReward, entry.BidChosen)
+	}
+	return nil
+}
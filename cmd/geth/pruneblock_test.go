@@ -39,6 +39,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/ethdb/leveldb"
+	"github.com/ethereum/go-ethereum/internal/era"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -144,6 +145,94 @@ func testOfflineBlockPruneWithAmountReserved(t *testing.T, amountReserved uint64
 	}
 }
 
+func TestOfflineBlockBackfill(t *testing.T) {
+	datadir := t.TempDir()
+
+	var (
+		chaindbPath    = filepath.Join(datadir, "chaindata")
+		oldAncientPath = filepath.Join(chaindbPath, "ancient")
+		eraDir         = filepath.Join(datadir, "era")
+		network        = "testnet"
+	)
+	db, blocks, _, _, _, startBlockNumber, _ := BlockchainCreator(t, chaindbPath, oldAncientPath, 100)
+	node, _ := startEthService(t, gspec, blocks, chaindbPath)
+	defer node.Close()
+
+	// Export the blocks about to be pruned into Era1 archives
+	db, err := NewLevelDBDatabaseWithFreezer(chaindbPath, 0, 0, oldAncientPath, "", false, true, false, false)
+	if err != nil {
+		t.Fatalf("failed to create database with ancient backend")
+	}
+	hashes := make([]common.Hash, startBlockNumber)
+	if err := os.MkdirAll(eraDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for first := uint64(0); first < startBlockNumber; first += 128 {
+		tmp := filepath.Join(eraDir, "tmp")
+		f, err := os.Create(tmp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		builder := era.NewBuilder(f)
+		for number := first; number < first+128 && number < startBlockNumber; number++ {
+			hashes[number] = rawdb.ReadCanonicalHash(db, number)
+			block := rawdb.ReadBlock(db, hashes[number], number)
+			receipts := rawdb.ReadRawReceipts(db, hashes[number], number)
+			for _, receipt := range receipts {
+				receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+			}
+			if err := builder.Add(block, receipts, rawdb.ReadTd(db, hashes[number], number)); err != nil {
+				t.Fatalf("failed to add block %d to the archive: %v", number, err)
+			}
+		}
+		root, err := builder.Finalize()
+		if err != nil {
+			t.Fatalf("failed to finalize archive: %v", err)
+		}
+		f.Close()
+		if err := os.Rename(tmp, filepath.Join(eraDir, era.Filename(network, int(first/128), root))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	db.Close()
+
+	// Prune the exported blocks, then restore them
+	newAncientPath := filepath.Join(chaindbPath, "ancient_back")
+	blockPruner := pruner.NewBlockPruner(db, node, oldAncientPath, newAncientPath, 100)
+	if err := blockPruner.BlockPruneBackUp(chaindbPath, 512, utils.MakeDatabaseHandles(0), "", false, false); err != nil {
+		t.Fatalf("Failed to back up block: %v", err)
+	}
+	if err := blockPruner.AncientDbReplacer(); err != nil {
+		t.Fatalf("Failed to replace ancientDb: %v", err)
+	}
+	backfiller := pruner.NewBlockBackfiller(node, oldAncientPath, filepath.Join(chaindbPath, "ancient_backfill"), eraDir, network, nil)
+	if err := backfiller.Backfill(chaindbPath, 512, utils.MakeDatabaseHandles(0), "", 0); err != nil {
+		t.Fatalf("Failed to backfill blocks: %v", err)
+	}
+
+	db, err = NewLevelDBDatabaseWithFreezer(chaindbPath, 0, 0, oldAncientPath, "", false, true, false, false)
+	if err != nil {
+		t.Fatalf("failed to create database with ancient backend")
+	}
+	defer db.Close()
+
+	if offset := rawdb.ReadOffSetOfCurrentAncientFreezer(db); offset != 0 {
+		t.Fatalf("ancientDb offset mismatch: have %d, want 0", offset)
+	}
+	for number := uint64(0); number < startBlockNumber+100; number++ {
+		hash := rawdb.ReadCanonicalHash(db, number)
+		if number < startBlockNumber && hash != hashes[number] {
+			t.Fatalf("block %d hash mismatch: have %x, want %x", number, hash, hashes[number])
+		}
+		if block := rawdb.ReadBlock(db, hash, number); block == nil {
+			t.Fatalf("block %d missing after backfill", number)
+		}
+		if td := rawdb.ReadTd(db, hash, number); td == nil {
+			t.Fatalf("total difficulty of block %d missing after backfill", number)
+		}
+	}
+}
+
 func BlockchainCreator(t *testing.T, chaindbPath, AncientPath string, blockRemain uint64) (ethdb.Database, []*types.Block, []*types.Block, []types.Receipts, []*big.Int, uint64, *core.BlockChain) {
 	//create a database with ancient freezer
 	db, err := NewLevelDBDatabaseWithFreezer(chaindbPath, 0, 0, AncientPath, "", false, false, false, false)
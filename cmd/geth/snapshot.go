@@ -51,6 +51,17 @@ import (
 	cli "github.com/urfave/cli/v2"
 )
 
+var (
+	backfillFromFlag = &cli.Uint64Flag{
+		Name:  "from",
+		Usage: "First block to restore (default = first block of the archives)",
+	}
+	backfillAccumulatorsFlag = &cli.StringFlag{
+		Name:  "accumulators",
+		Usage: "File of the newline-delimited expected accumulator roots, one per epoch",
+	}
+)
+
 var (
 	snapshotCommand = &cli.Command{
 		Name:        "snapshot",
@@ -100,6 +111,31 @@ finally assemble the statedb and new ancientDb together.
 The purpose of doing it is because the block data will be moved into the ancient store when it
 becomes old enough(exceed the Threshold 90000), the disk usage will be very large over time, and is occupied mainly by ancientDb,
 so it's very necessary to do block data prune, this feature will handle it.
+`,
+			},
+			{
+				Name:      "backfill-block",
+				Usage:     "Restore pruned block data offline from Era1 archives",
+				ArgsUsage: "<dir>",
+				Action:    backfillBlock,
+				Category:  "MISCELLANEOUS COMMANDS",
+				Flags: flags.Merge([]cli.Flag{
+					utils.DataDirFlag,
+					utils.AncientFlag,
+					backfillFromFlag,
+					backfillAccumulatorsFlag,
+				}, utils.NetworkFlags),
+				Description: `
+geth snapshot backfill-block <dir>
+imports the blocks dropped by prune-block back into the ancientdb from the Era1
+archives of the directory, extending the pruned range downward.
+The blocks from --from (or the first archived block) up to the first block left
+in the ancientdb are imported. Each archive is checked against its accumulator,
+and against the expected roots of --accumulators if given, and the imported
+blocks must link up with the canonical chain still in the database.
+Like prune-block, a new ancientdb is built next to the old one and replaces it
+once complete. If interrupted, run the command again to finish the replacement.
+Era1 archives carry no blob sidecars, the imported blocks won't have any.
 `,
 			},
 			{
@@ -352,56 +388,14 @@ func pruneBlock(ctx *cli.Context) error {
 		return err
 	}
 
-	// Most of the problems reported by users when first using the prune-block
-	// tool are due to incorrect directory settings.Here, the default directory
-	// and relative directory are canceled, and the user is forced to formulate
-	// an absolute path to guide users to run the prune-block command correctly.
-	if !ctx.IsSet(utils.DataDirFlag.Name) {
-		return errors.New("datadir must be set")
-	} else {
-		datadir := ctx.String(utils.DataDirFlag.Name)
-		if !filepath.IsAbs(datadir) {
-			// force absolute paths, which often fail due to the splicing of relative paths
-			return errors.New("datadir not abs path")
-		}
-	}
-
-	if !ctx.IsSet(utils.AncientFlag.Name) {
-		return errors.New("datadir.ancient must be set")
-	} else {
-		if stack.CheckIfMultiDataBase() {
-			ancientPath := ctx.String(utils.AncientFlag.Name)
-			index := strings.LastIndex(ancientPath, "/ancient/chain")
-			if index != -1 {
-				oldAncientPath = ancientPath[:index] + "/block/ancient/chain"
-			}
-		} else {
-			oldAncientPath = ctx.String(utils.AncientFlag.Name)
-		}
-		if !filepath.IsAbs(oldAncientPath) {
-			// force absolute paths, which often fail due to the splicing of relative paths
-			return errors.New("datadir.ancient not abs path")
-		}
+	oldAncientPath, err = blockAncientPath(ctx, stack)
+	if err != nil {
+		return err
 	}
-
 	path, _ := filepath.Split(oldAncientPath)
 	if path == "" {
 		return errors.New("prune failed, did not specify the AncientPath")
 	}
-	newVersionPath := false
-	files, err := os.ReadDir(oldAncientPath)
-	if err != nil {
-		return err
-	}
-	for _, file := range files {
-		if file.IsDir() && file.Name() == "chain" {
-			newVersionPath = true
-		}
-	}
-	if newVersionPath && !strings.HasSuffix(oldAncientPath, "geth/chaindata/ancient/chain") {
-		log.Error("datadir.ancient subdirectory incorrect", "got path", oldAncientPath, "want subdirectory", "geth/chaindata/ancient/chain/")
-		return errors.New("datadir.ancient subdirectory incorrect")
-	}
 	newAncientPath = filepath.Join(path, "chain_back")
 
 	blockpruner = pruner.NewBlockPruner(chaindb, stack, oldAncientPath, newAncientPath, blockAmountReserved)
@@ -450,6 +444,117 @@ func pruneBlock(ctx *cli.Context) error {
 	return nil
 }
 
+func backfillBlock(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		utils.Fatalf("usage: %s", ctx.Command.ArgsUsage)
+	}
+	stack, config := makeConfigNode(ctx)
+	defer stack.Close()
+
+	dir := ctx.Args().First()
+	network, err := eraNetwork(ctx, dir)
+	if err != nil {
+		return err
+	}
+	var roots []common.Hash
+	if ctx.IsSet(backfillAccumulatorsFlag.Name) {
+		if roots, err = readAccumulators(ctx.String(backfillAccumulatorsFlag.Name)); err != nil {
+			return err
+		}
+	}
+	oldAncientPath, err := blockAncientPath(ctx, stack)
+	if err != nil {
+		return err
+	}
+	path, _ := filepath.Split(oldAncientPath)
+	if path == "" {
+		return errors.New("backfill failed, did not specify the AncientPath")
+	}
+	newAncientPath := filepath.Join(path, "chain_backfill")
+
+	backfiller := pruner.NewBlockBackfiller(stack, oldAncientPath, newAncientPath, dir, network, roots)
+	if err := backfiller.Backfill("chaindata", config.Eth.DatabaseCache, utils.MakeDatabaseHandles(0), "", ctx.Uint64(backfillFromFlag.Name)); err != nil {
+		log.Error("Block backfill failed", "err", err)
+		return err
+	}
+	log.Info("Block backfill successfully")
+	return nil
+}
+
+// readAccumulators reads a file of newline-delimited accumulator roots.
+func readAccumulators(file string) ([]common.Hash, error) {
+	blob, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open accumulators file: %w", err)
+	}
+	var roots []common.Hash
+	for _, line := range strings.Split(string(blob), "\n") {
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		root := common.HexToHash(line)
+		if len(common.FromHex(line)) != common.HashLength {
+			return nil, fmt.Errorf("invalid accumulator root %q", line)
+		}
+		roots = append(roots, root)
+	}
+	return roots, nil
+}
+
+// blockAncientPath returns the path of the chain freezer, refusing the default
+// and relative directories.
+func blockAncientPath(ctx *cli.Context, stack *node.Node) (string, error) {
+	var oldAncientPath string
+
+	// Most of the problems reported by users when first using the prune-block
+	// tool are due to incorrect directory settings.Here, the default directory
+	// and relative directory are canceled, and the user is forced to formulate
+	// an absolute path to guide users to run the prune-block command correctly.
+	if !ctx.IsSet(utils.DataDirFlag.Name) {
+		return "", errors.New("datadir must be set")
+	} else {
+		datadir := ctx.String(utils.DataDirFlag.Name)
+		if !filepath.IsAbs(datadir) {
+			// force absolute paths, which often fail due to the splicing of relative paths
+			return "", errors.New("datadir not abs path")
+		}
+	}
+
+	if !ctx.IsSet(utils.AncientFlag.Name) {
+		return "", errors.New("datadir.ancient must be set")
+	} else {
+		if stack.CheckIfMultiDataBase() {
+			ancientPath := ctx.String(utils.AncientFlag.Name)
+			index := strings.LastIndex(ancientPath, "/ancient/chain")
+			if index != -1 {
+				oldAncientPath = ancientPath[:index] + "/block/ancient/chain"
+			}
+		} else {
+			oldAncientPath = ctx.String(utils.AncientFlag.Name)
+		}
+		if !filepath.IsAbs(oldAncientPath) {
+			// force absolute paths, which often fail due to the splicing of relative paths
+			return "", errors.New("datadir.ancient not abs path")
+		}
+	}
+
+	newVersionPath := false
+	files, err := os.ReadDir(oldAncientPath)
+	if err != nil {
+		return "", err
+	}
+	for _, file := range files {
+		if file.IsDir() && file.Name() == "chain" {
+			newVersionPath = true
+		}
+	}
+	if newVersionPath && !strings.HasSuffix(oldAncientPath, "geth/chaindata/ancient/chain") {
+		log.Error("datadir.ancient subdirectory incorrect", "got path", oldAncientPath, "want subdirectory", "geth/chaindata/ancient/chain/")
+		return "", errors.New("datadir.ancient subdirectory incorrect")
+	}
+	return oldAncientPath, nil
+}
+
 // Deprecation: this command should be deprecated once the hash-based
 // scheme is deprecated.
 func pruneState(ctx *cli.Context) error {
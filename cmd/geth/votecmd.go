@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/console/prompt"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vote"
+)
+
+var (
+	voteJournalFlags = []cli.Flag{
+		utils.DataDirFlag,
+		utils.VoteJournalDirFlag,
+		utils.BLSPasswordFileFlag,
+		utils.BLSWalletDirFlag,
+		utils.BLSRemoteSignerFlag,
+		utils.BLSRemoteSignerKeyFlag,
+	}
+	voteCommand = &cli.Command{
+		Name:  "vote",
+		Usage: "A set of commands for the fast finality votes",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "journal",
+				Usage: "Inspect and recover the journal of the local votes",
+				Subcommands: []*cli.Command{
+					{
+						Name:   "inspect",
+						Usage:  "Print the votes of the journal",
+						Action: voteJournalInspect,
+						Flags:  voteJournalFlags,
+						Description: `
+geth vote journal inspect
+
+Prints every entry of the vote journal, including the ones which can't be
+decoded. The node must be stopped.`,
+					},
+					{
+						Name:   "verify",
+						Usage:  "Check the integrity of the journal",
+						Action: voteJournalVerify,
+						Flags:  voteJournalFlags,
+						Description: `
+geth vote journal verify
+
+Checks the signature of every vote of the journal, against the configured BLS
+key if --blspassword or --blsremotesigner is given, and looks for missing
+entries and for votes which are slashable together. The command fails if the journal isn't safe to use.
+The highest source and target reported must be respected by the next vote.`,
+					},
+					{
+						Name:   "truncate",
+						Usage:  "Drop the unusable entries of the journal",
+						Action: voteJournalTruncate,
+						Flags:  voteJournalFlags,
+						Description: `
+geth vote journal truncate
+
+Moves the journal to a backup and rewrites it with the valid votes only, so the
+node can start voting again. Valid votes are always kept, even conflicting ones,
+as dropping them could allow the node to vote differently for the same target.
+The highest source and target of the journal, broken entries included, are
+persisted as a vote floor, and the node refuses to vote at or below it. Votes
+lost with unreadable entries are not covered, so make sure none was signed
+above the floor before running it.`,
+					},
+				},
+			},
+		},
+	}
+)
+
+// readVoteJournal reads the entries of the configured vote journal, checking the
+// signer of the votes if the BLS wallet or remote signer is configured.
+func readVoteJournal(ctx *cli.Context) (string, []*vote.JournalEntry) {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	cfg := stack.Config()
+	path := stack.ResolvePath(cfg.VoteJournalDir)
+
+	var (
+		key    *types.BLSPublicKey
+		signer *vote.VoteSigner
+		err    error
+	)
+	switch {
+	case cfg.BLSRemoteSigner != "":
+		signer, err = vote.NewRemoteVoteSigner(cfg.BLSRemoteSigner, cfg.BLSRemoteSignerKey, stack.ResolvePath(vote.SlashingProtectionDir))
+	case cfg.BLSPasswordFile != "":
+		signer, err = vote.NewVoteSigner(stack.ResolvePath(cfg.BLSPasswordFile), stack.ResolvePath(cfg.BLSWalletDir))
+	}
+	if err != nil {
+		utils.Fatalf("Failed to open BLS signer: %v", err)
+	}
+	if signer != nil {
+		key = (*types.BLSPublicKey)(&signer.PubKey)
+	}
+	entries, err := vote.ReadJournalEntries(path, key)
+	if err != nil {
+		utils.Fatalf("Failed to read vote journal: %v", err)
+	}
+	return path, entries
+}
+
+func voteJournalInspect(ctx *cli.Context) error {
+	path, entries := readVoteJournal(ctx)
+	fmt.Printf("Vote journal %s, %d entries\n", path, len(entries))
+
+	for _, entry := range entries {
+		if entry.Vote == nil || entry.Vote.Data == nil {
+			fmt.Printf("%6d  invalid: %v\n", entry.Index, entry.Err)
+			continue
+		}
+		fmt.Printf("%6d  source=%d (%x) target=%d (%x) key=%x", entry.Index,
+			entry.Vote.Data.SourceNumber, entry.Vote.Data.SourceHash[:4],
+			entry.Vote.Data.TargetNumber, entry.Vote.Data.TargetHash[:4], entry.Vote.VoteAddress[:8])
+		if entry.Err != nil {
+			fmt.Printf(" invalid: %v", entry.Err)
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+func voteJournalVerify(ctx *cli.Context) error {
+	path, entries := readVoteJournal(ctx)
+	report := vote.VerifyJournal(entries)
+	printJournalReport(path, report)
+
+	if !report.Healthy() {
+		utils.Fatalf("Vote journal is unsafe to use, check the entries above before running `geth vote journal truncate`")
+	}
+	return nil
+}
+
+func voteJournalTruncate(ctx *cli.Context) error {
+	path, entries := readVoteJournal(ctx)
+	report := vote.VerifyJournal(entries)
+	printJournalReport(path, report)
+
+	if len(report.Invalid) == 0 && len(report.Gaps) == 0 {
+		fmt.Println("Nothing to truncate")
+		return nil
+	}
+	confirm, err := prompt.Stdin.PromptConfirm(fmt.Sprintf("Drop %d unusable entries?", len(report.Invalid)))
+	if err != nil {
+		utils.Fatalf("%v", err)
+	}
+	if !confirm {
+		return nil
+	}
+	backup, err := vote.RepairJournal(path, entries)
+	if err != nil {
+		utils.Fatalf("Failed to repair vote journal: %v", err)
+	}
+	fmt.Printf("Vote journal rewritten with %d votes, backup moved to %s\n", len(entries)-len(report.Invalid), backup)
+	fmt.Printf("Votes are bounded to a source of at least %d and a target above %d\n", report.HighestSource, report.HighestTarget)
+	return nil
+}
+
+func printJournalReport(path string, report *vote.JournalReport) {
+	fmt.Printf("Vote journal %s, %d entries\n", path, report.Entries)
+	for _, entry := range report.Invalid {
+		fmt.Printf("Invalid entry %d: %v\n", entry.Index, entry.Err)
+	}
+	for _, gap := range report.Gaps {
+		fmt.Printf("Missing entries %d-%d\n", gap[0], gap[1])
+	}
+	for _, conflict := range report.Conflicts {
+		fmt.Printf("%s: source=%d target=%d and source=%d target=%d\n", conflict.Reason,
+			conflict.VoteA.Data.SourceNumber, conflict.VoteA.Data.TargetNumber,
+			conflict.VoteB.Data.SourceNumber, conflict.VoteB.Data.TargetNumber)
+	}
+	fmt.Printf("Highest source %d, highest target %d\n", report.HighestSource, report.HighestTarget)
+}
@@ -48,6 +48,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth"
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/eth/evidence"
 	"github.com/ethereum/go-ethereum/eth/filters"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
 	"github.com/ethereum/go-ethereum/eth/tracers"
@@ -451,6 +452,18 @@ var (
 		Value:    ethconfig.Defaults.TxPool.ReannounceTime,
 		Category: flags.TxPoolCategory,
 	}
+	TxPoolBlacklistFlag = &cli.StringFlag{
+		Name:     "txpool.blacklist",
+		Usage:    "File of addresses (one per line) whose transactions are rejected, reloaded once modified",
+		Value:    ethconfig.Defaults.TxPool.Blacklist,
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolSnapshotFlag = &cli.StringFlag{
+		Name:     "txpool.snapshot",
+		Usage:    "File the pending and queued transactions are dumped to on shutdown, for admin_importTxPool (disabled if empty)",
+		Value:    ethconfig.Defaults.TxPool.Snapshot,
+		Category: flags.TxPoolCategory,
+	}
 	// Blob transaction pool settings
 	BlobPoolDataDirFlag = &cli.StringFlag{
 		Name:     "blobpool.datadir",
@@ -585,12 +598,33 @@ var (
 		Value:    ethconfig.Defaults.Miner.DelayLeftOver,
 		Category: flags.MinerCategory,
 	}
+	MinerSkipBlacklistedFlag = &cli.BoolFlag{
+		Name:     "miner.skipblacklisted",
+		Usage:    "Leave the transactions sent from or to the txpool blacklist out of the mined blocks",
+		Category: flags.MinerCategory,
+	}
 	MinerNewPayloadTimeout = &cli.DurationFlag{
 		Name:     "miner.newpayload-timeout",
 		Usage:    "Specify the maximum time allowance for creating a new payload",
 		Value:    ethconfig.Defaults.Miner.NewPayloadTimeout,
 		Category: flags.MinerCategory,
 	}
+	MevBidJournalFlag = &cli.StringFlag{
+		Name:     "mev.bidjournal",
+		Usage:    "Directory of the journal of the received bids, inside the datadir if relative (journal disabled if empty)",
+		Category: flags.MinerCategory,
+	}
+	MevBidJournalRetentionFlag = &cli.Uint64Flag{
+		Name:     "mev.bidjournal.retention",
+		Usage:    "Number of recent blocks whose bid journal is kept (0 = keep all)",
+		Value:    ethconfig.Defaults.Miner.Mev.BidJournalRetention,
+		Category: flags.MinerCategory,
+	}
+	MevForwardPrivateTxsFlag = &cli.BoolFlag{
+		Name:     "mev.forwardprivatetxs",
+		Usage:    "Forward the transactions sent by eth_sendPrivateTransaction to the builders through their private endpoint",
+		Category: flags.MinerCategory,
+	}
 
 	// Account settings
 	UnlockedAccountFlag = &cli.StringFlag{
@@ -623,6 +657,27 @@ var (
 		Usage:    "Record information useful for VM and contract debugging",
 		Category: flags.VMCategory,
 	}
+	VMStorageLayoutsFlag = &flags.DirectoryFlag{
+		Name:     "vmdebug.storagelayouts",
+		Usage:    "Directory of solc storage layouts, named <address>.json, used to label the storage slots in traces",
+		Category: flags.VMCategory,
+	}
+	TraceCacheFlag = &cli.Uint64Flag{
+		Name:     "tracecache.size",
+		Usage:    "Disk space allowance of the cache of the block traces in megabytes (0 = disabled)",
+		Category: flags.VMCategory,
+	}
+	TraceCacheTracersFlag = &cli.StringFlag{
+		Name:     "tracecache.tracers",
+		Usage:    "Comma separated list of tracers to trace the new blocks with in the background (requires --tracecache.size)",
+		Category: flags.VMCategory,
+	}
+	TraceFilterRangeFlag = &cli.Uint64Flag{
+		Name:     "trace.filterrange",
+		Usage:    "Maximum number of blocks traced by a trace_filter request (0 = unlimited)",
+		Value:    ethconfig.Defaults.TraceFilterRange,
+		Category: flags.VMCategory,
+	}
 
 	// API options.
 	RPCGlobalGasCapFlag = &cli.Uint64Flag{
@@ -643,6 +698,12 @@ var (
 		Value:    ethconfig.Defaults.RPCTxFeeCap,
 		Category: flags.APICategory,
 	}
+	RPCPrivateTxLifetimeFlag = &cli.Uint64Flag{
+		Name:     "rpc.privatetxlifetime",
+		Usage:    "Number of blocks a private transaction stays includable in when sent without a max block number",
+		Value:    ethconfig.Defaults.RPCPrivateTxLifetime,
+		Category: flags.APICategory,
+	}
 	// Authenticated RPC HTTP settings
 	AuthListenFlag = &cli.StringFlag{
 		Name:  "authrpc.addr",
@@ -1092,6 +1153,27 @@ Please note that --` + MetricsHTTPFlag.Name + ` must be set to start the server.
 		Category: flags.MinerCategory,
 	}
 
+	EvidenceReporterFlag = &cli.StringFlag{
+		Name:     "monitor.reporter",
+		Usage:    "Unlocked account which submits the slashing evidences found by the monitors to the SlashIndicator contract",
+		Category: flags.MinerCategory,
+	}
+	EvidenceGasLimitFlag = &cli.Uint64Flag{
+		Name:     "monitor.reporter.gaslimit",
+		Usage:    "Gas limit of the evidence submission transactions (0 = default)",
+		Category: flags.MinerCategory,
+	}
+	EvidenceGasPriceFlag = &flags.BigFlag{
+		Name:     "monitor.reporter.gasprice",
+		Usage:    "Gas price of the evidence submission transactions (default = the txpool gas tip)",
+		Category: flags.MinerCategory,
+	}
+	EvidenceDryRunFlag = &cli.BoolFlag{
+		Name:     "monitor.reporter.dryrun",
+		Usage:    "Sign the evidence submission transactions without sending them",
+		Category: flags.MinerCategory,
+	}
+
 	VotingEnabledFlag = &cli.BoolFlag{
 		Name:     "vote",
 		Usage:    "Enable voting when mining",
@@ -1122,6 +1204,18 @@ Please note that --` + MetricsHTTPFlag.Name + ` must be set to start the server.
 		Category: flags.AccountCategory,
 	}
 
+	BLSRemoteSignerFlag = &cli.StringFlag{
+		Name:     "blsremotesigner",
+		Usage:    "URL of a Web3Signer compatible remote signer holding the BLS key, used instead of the BLS wallet",
+		Category: flags.AccountCategory,
+	}
+
+	BLSRemoteSignerKeyFlag = &cli.StringFlag{
+		Name:     "blsremotesigner.key",
+		Usage:    "BLS public key to vote with, required if the remote signer holds more than one key",
+		Category: flags.AccountCategory,
+	}
+
 	VoteJournalDirFlag = &flags.DirectoryFlag{
 		Name:     "vote-journal-path",
 		Usage:    "Path for the voteJournal dir in fast finality feature (default = inside the datadir)",
@@ -1135,6 +1229,21 @@ Please note that --` + MetricsHTTPFlag.Name + ` must be set to start the server.
 		Value:    params.DefaultExtraReserveForBlobRequests,
 		Category: flags.MiscCategory,
 	}
+	BlobArchiveFlag = &flags.DirectoryFlag{
+		Name:     "blob.archive",
+		Usage:    "Directory to archive the blob sidecars to before they expire",
+		Category: flags.MiscCategory,
+	}
+	BlobArchiveRPCFlag = &cli.BoolFlag{
+		Name:     "blob.archive.rpc",
+		Usage:    "Serve the archived blob sidecars through the RPC once expired (requires --blob.archive)",
+		Category: flags.MiscCategory,
+	}
+	BlobArchiveRetentionFlag = &cli.Uint64Flag{
+		Name:     "blob.archive.retention",
+		Usage:    "Number of most recent epochs whose blob archives are kept, older ones are deleted (0 = keep all)",
+		Category: flags.MiscCategory,
+	}
 )
 
 var (
@@ -1430,6 +1539,22 @@ func setMonitors(ctx *cli.Context, cfg *node.Config) {
 	if ctx.Bool(EnableMaliciousVoteMonitorFlag.Name) {
 		cfg.EnableMaliciousVoteMonitor = true
 	}
+	if ctx.IsSet(EvidenceReporterFlag.Name) {
+		reporter := ctx.String(EvidenceReporterFlag.Name)
+		if !common.IsHexAddress(reporter) {
+			Fatalf("Invalid evidence reporter: %s", reporter)
+		}
+		cfg.EvidenceReporter = common.HexToAddress(reporter)
+	}
+	if ctx.IsSet(EvidenceGasLimitFlag.Name) {
+		cfg.EvidenceGasLimit = ctx.Uint64(EvidenceGasLimitFlag.Name)
+	}
+	if ctx.IsSet(EvidenceGasPriceFlag.Name) {
+		cfg.EvidenceGasPrice = flags.GlobalBig(ctx, EvidenceGasPriceFlag.Name)
+	}
+	if ctx.IsSet(EvidenceDryRunFlag.Name) {
+		cfg.EvidenceDryRun = ctx.Bool(EvidenceDryRunFlag.Name)
+	}
 }
 
 // MakeDatabaseHandles raises out the number of allowed file handles per process
@@ -1634,6 +1759,12 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	if ctx.IsSet(BLSPasswordFileFlag.Name) {
 		cfg.BLSPasswordFile = ctx.String(BLSPasswordFileFlag.Name)
 	}
+	if ctx.IsSet(BLSRemoteSignerFlag.Name) {
+		cfg.BLSRemoteSigner = ctx.String(BLSRemoteSignerFlag.Name)
+	}
+	if ctx.IsSet(BLSRemoteSignerKeyFlag.Name) {
+		cfg.BLSRemoteSignerKey = ctx.String(BLSRemoteSignerKeyFlag.Name)
+	}
 	if ctx.IsSet(DBEngineFlag.Name) {
 		dbEngine := ctx.String(DBEngineFlag.Name)
 		if dbEngine != "leveldb" && dbEngine != "pebble" {
@@ -1754,6 +1885,12 @@ func setTxPool(ctx *cli.Context, cfg *legacypool.Config) {
 	if ctx.IsSet(TxPoolLifetimeFlag.Name) {
 		cfg.Lifetime = ctx.Duration(TxPoolLifetimeFlag.Name)
 	}
+	if ctx.IsSet(TxPoolBlacklistFlag.Name) {
+		cfg.Blacklist = ctx.String(TxPoolBlacklistFlag.Name)
+	}
+	if ctx.IsSet(TxPoolSnapshotFlag.Name) {
+		cfg.Snapshot = ctx.String(TxPoolSnapshotFlag.Name)
+	}
 	if ctx.IsSet(TxPoolReannounceTimeFlag.Name) {
 		cfg.ReannounceTime = ctx.Duration(TxPoolReannounceTimeFlag.Name)
 	}
@@ -1784,6 +1921,18 @@ func setMiner(ctx *cli.Context, cfg *miner.Config) {
 	if ctx.Bool(DisableVoteAttestationFlag.Name) {
 		cfg.DisableVoteAttestation = true
 	}
+	if ctx.Bool(MinerSkipBlacklistedFlag.Name) {
+		cfg.SkipBlacklisted = true
+	}
+	if ctx.IsSet(MevBidJournalFlag.Name) {
+		cfg.Mev.BidJournalDir = ctx.String(MevBidJournalFlag.Name)
+	}
+	if ctx.IsSet(MevBidJournalRetentionFlag.Name) {
+		cfg.Mev.BidJournalRetention = ctx.Uint64(MevBidJournalRetentionFlag.Name)
+	}
+	if ctx.Bool(MevForwardPrivateTxsFlag.Name) {
+		cfg.Mev.ForwardPrivateTxs = true
+	}
 }
 
 func setRequiredBlocks(ctx *cli.Context, cfg *ethconfig.Config) {
@@ -2054,6 +2203,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(RPCGlobalTxFeeCapFlag.Name) {
 		cfg.RPCTxFeeCap = ctx.Float64(RPCGlobalTxFeeCapFlag.Name)
 	}
+	if ctx.IsSet(RPCPrivateTxLifetimeFlag.Name) {
+		cfg.RPCPrivateTxLifetime = ctx.Uint64(RPCPrivateTxLifetimeFlag.Name)
+	}
 	if ctx.IsSet(NoDiscoverFlag.Name) {
 		cfg.EthDiscoveryURLs, cfg.SnapDiscoveryURLs, cfg.TrustDiscoveryURLs, cfg.BscDiscoveryURLs = []string{}, []string{}, []string{}, []string{}
 	} else if ctx.IsSet(DNSDiscoveryFlag.Name) {
@@ -2186,6 +2338,30 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 		}
 		cfg.BlobExtraReserve = extraReserve
 	}
+	if ctx.IsSet(BlobArchiveFlag.Name) {
+		cfg.BlobArchiveDir = ctx.String(BlobArchiveFlag.Name)
+	}
+	if ctx.IsSet(BlobArchiveRPCFlag.Name) {
+		cfg.BlobArchiveRPC = ctx.Bool(BlobArchiveRPCFlag.Name)
+	}
+	if ctx.IsSet(BlobArchiveRetentionFlag.Name) {
+		cfg.BlobArchiveRetention = ctx.Uint64(BlobArchiveRetentionFlag.Name)
+	}
+	if cfg.BlobArchiveRPC && cfg.BlobArchiveDir == "" {
+		Fatalf("--%s requires --%s", BlobArchiveRPCFlag.Name, BlobArchiveFlag.Name)
+	}
+	if ctx.IsSet(TraceCacheFlag.Name) {
+		cfg.TraceCacheSize = ctx.Uint64(TraceCacheFlag.Name)
+	}
+	if ctx.IsSet(TraceCacheTracersFlag.Name) {
+		cfg.TraceCacheTracers = SplitAndTrim(ctx.String(TraceCacheTracersFlag.Name))
+	}
+	if ctx.IsSet(TraceFilterRangeFlag.Name) {
+		cfg.TraceFilterRange = ctx.Uint64(TraceFilterRangeFlag.Name)
+	}
+	if len(cfg.TraceCacheTracers) > 0 && cfg.TraceCacheSize == 0 {
+		Fatalf("--%s requires --%s", TraceCacheTracersFlag.Name, TraceCacheFlag.Name)
+	}
 }
 
 // SetDNSDiscoveryDefaults configures DNS discovery with the given URL if
@@ -2210,7 +2386,19 @@ func RegisterEthService(stack *node.Node, cfg *ethconfig.Config) (ethapi.Backend
 	if err != nil {
 		Fatalf("Failed to register the Ethereum service: %v", err)
 	}
-	stack.RegisterAPIs(tracers.APIs(backend.APIBackend))
+	var cache *tracers.TraceCache
+	if cfg.TraceCacheSize > 0 {
+		cache, err = tracers.NewTraceCache(backend.APIBackend, tracers.TraceCacheConfig{
+			Path:    stack.ResolvePath("tracecache"),
+			Size:    cfg.TraceCacheSize * 1024 * 1024,
+			Tracers: cfg.TraceCacheTracers,
+		})
+		if err != nil {
+			Fatalf("Failed to open the trace cache: %v", err)
+		}
+		stack.RegisterLifecycle(cache)
+	}
+	stack.RegisterAPIs(tracers.APIs(backend.APIBackend, cache, cfg.TraceFilterRange))
 	return backend.APIBackend, backend
 }
 
@@ -2221,6 +2409,24 @@ func RegisterEthStatsService(stack *node.Node, backend ethapi.Backend, url strin
 	}
 }
 
+// RegisterEvidenceService adds the slashing evidence service to the node if any monitor is enabled.
+func RegisterEvidenceService(stack *node.Node, backend *eth.Ethereum, cfg *node.Config) {
+	if !cfg.EnableDoubleSignMonitor && !cfg.EnableMaliciousVoteMonitor {
+		return
+	}
+	_, err := evidence.New(stack, backend, evidence.Config{
+		DoubleSign:    cfg.EnableDoubleSignMonitor,
+		MaliciousVote: cfg.EnableMaliciousVoteMonitor,
+		Reporter:      cfg.EvidenceReporter,
+		GasLimit:      cfg.EvidenceGasLimit,
+		GasPrice:      cfg.EvidenceGasPrice,
+		DryRun:        cfg.EvidenceDryRun,
+	})
+	if err != nil {
+		Fatalf("Failed to register the slashing evidence service: %v", err)
+	}
+}
+
 // RegisterGraphQLService adds the GraphQL API to the node.
 func RegisterGraphQLService(stack *node.Node, backend ethapi.Backend, filterSystem *filters.FilterSystem, cfg *node.Config) {
 	err := graphql.New(stack, backend, filterSystem, cfg.GraphQLCors, cfg.GraphQLVirtualHosts)
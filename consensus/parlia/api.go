@@ -17,6 +17,8 @@
 package parlia
 
 import (
+	"fmt"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -86,3 +88,32 @@ func (api *API) GetValidatorsAtHash(hash common.Hash) ([]common.Address, error)
 	}
 	return snap.validators(), nil
 }
+
+// GetValidatorStats retrieves the performance of the validators over the given number
+// of most recent blocks.
+func (api *API) GetValidatorStats(blockRange uint64) (*ValidatorStats, error) {
+	if blockRange == 0 || blockRange > maxValidatorStatsRange {
+		return nil, fmt.Errorf("block range should be within [1, %d]", maxValidatorStatsRange)
+	}
+	head := api.chain.CurrentHeader()
+	if head == nil {
+		return nil, errUnknownBlock
+	}
+	to := head.Number.Uint64()
+	if to == 0 {
+		return &ValidatorStats{Validators: make(map[common.Address]*ValidatorStat)}, nil
+	}
+	from := uint64(1)
+	if to > blockRange {
+		from = to - blockRange + 1
+	}
+	records, err := api.parlia.blockRecords(api.chain, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &ValidatorStats{
+		FromBlock:  from,
+		ToBlock:    to,
+		Validators: aggregateValidatorStats(records),
+	}, nil
+}
@@ -224,6 +224,9 @@ type Parlia struct {
 	// Recent headers to check for double signing: key includes block number and miner. value is the block header
 	// If same key's value already exists for different block header roots then double sign is detected
 
+	// Performance records of recently imported blocks, aggregated by parlia_getValidatorStats
+	tracker *validatorTracker
+
 	signer types.Signer
 
 	val      common.Address // Ethereum address of the signing key
@@ -297,6 +300,7 @@ func New(
 		recentSnaps:                recentSnaps,
 		recentHeaders:              recentHeaders,
 		signatures:                 signatures,
+		tracker:                    newValidatorTracker(),
 		validatorSetABIBeforeLuban: vABIBeforeLuban,
 		validatorSetABI:            vABI,
 		slashABI:                   sABI,
@@ -1236,6 +1240,7 @@ func (p *Parlia) Finalize(chain consensus.ChainHeaderReader, header *types.Heade
 	if len(*systemTxs) > 0 {
 		return errors.New("the length of systemTxs do not match")
 	}
+	p.trackBlock(chain, header, parent, snap)
 	return nil
 }
 
@@ -1901,6 +1906,11 @@ func (p *Parlia) GetFinalizedHeader(chain consensus.ChainHeaderReader, header *t
 	return chain.GetHeader(snap.Attestation.SourceHash, snap.Attestation.SourceNumber)
 }
 
+// GetVoteAttestation returns the vote attestation included in the header, nil if there is none.
+func (p *Parlia) GetVoteAttestation(header *types.Header) (*types.VoteAttestation, error) {
+	return getVoteAttestationFromHeader(header, p.chainConfig, p.config)
+}
+
 // ===========================     utility function        ==========================
 func (p *Parlia) backOffTime(snap *Snapshot, header *types.Header, val common.Address) uint64 {
 	if snap.inturn(val) {
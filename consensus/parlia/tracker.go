@@ -0,0 +1,213 @@
+package parlia
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const maxValidatorStatsRange = 28800 // Maximum number of blocks the validator statistics can be aggregated over
+
+// blockRecord is the performance record of a single block.
+type blockRecord struct {
+	proposer common.Address
+	inturn   common.Address // validator in turn at the height of the block
+	delay    uint64         // seconds the block was sealed later than a period after its parent
+
+	voters   []common.Address // validators expected to vote for the parent, nil before Plato
+	attested uint64           // bitset of the voters whose votes were attested in the block
+}
+
+// validatorTracker keeps the records of recently imported blocks, which are aggregated
+// into per validator statistics on demand.
+type validatorTracker struct {
+	records *lru.Cache[common.Hash, *blockRecord]
+
+	voters []common.Address // last seen voters, shared by the records to save memory
+	lock   sync.Mutex       // Protects the voters
+}
+
+func newValidatorTracker() *validatorTracker {
+	return &validatorTracker{
+		records: lru.NewCache[common.Hash, *blockRecord](maxValidatorStatsRange),
+	}
+}
+
+// record derives the performance record of the header from the snapshots of its parent
+// and grandparent, the latter is only needed once the votes are attested.
+func (t *validatorTracker) record(p *Parlia, header, parent *types.Header, snap, voteSnap *Snapshot) *blockRecord {
+	record := &blockRecord{
+		proposer: header.Coinbase,
+		inturn:   snap.inturnValidator(),
+	}
+	if expected := parent.Time + p.config.Period; header.Time > expected {
+		record.delay = header.Time - expected
+	}
+	if voteSnap != nil {
+		// The voters are shared between records until the validator set changes
+		voters := voteSnap.validators()
+		t.lock.Lock()
+		if slices.Equal(t.voters, voters) {
+			voters = t.voters
+		} else {
+			t.voters = voters
+		}
+		t.lock.Unlock()
+		record.voters = voters
+
+		attestation, err := getVoteAttestationFromHeader(header, p.chainConfig, p.config)
+		if err != nil {
+			log.Debug("Failed to get vote attestation for tracking", "number", header.Number, "hash", header.Hash(), "err", err)
+		} else if attestation != nil {
+			record.attested = uint64(attestation.VoteAddressSet)
+		}
+	}
+	t.records.Add(header.Hash(), record)
+	return record
+}
+
+// trackBlock records the performance of a block being imported, the snapshot is the
+// one of its parent. It's called once the block is finalized rather than when its
+// header is verified, to keep the tracking off the header verification path.
+//
+// The grandparent snapshot needed for the voters was already resolved when the vote
+// attestation of the header was verified, so the lookup is a recentSnaps hit. The
+// attestation is decoded once more from the extra data, which is cheap next to the
+// block processing.
+func (p *Parlia) trackBlock(chain consensus.ChainHeaderReader, header, parent *types.Header, snap *Snapshot) {
+	var voteSnap *Snapshot
+	if number := header.Number.Uint64(); number >= 2 && p.chainConfig.IsPlato(header.Number) {
+		// The attestation of the block is verified against the snapshot of the grandparent
+		var err error
+		if voteSnap, err = p.snapshot(chain, number-2, parent.ParentHash, nil); err != nil {
+			log.Debug("Failed to get snapshot for tracking", "number", number-2, "hash", parent.ParentHash, "err", err)
+			return
+		}
+	}
+	p.tracker.record(p, header, parent, snap, voteSnap)
+}
+
+// blockRecords collects the records of the canonical blocks within the range, the
+// missing ones are derived from the snapshots.
+func (p *Parlia) blockRecords(chain consensus.ChainHeaderReader, from, to uint64) ([]*blockRecord, error) {
+	parent := chain.GetHeaderByNumber(from - 1)
+	if parent == nil {
+		return nil, errUnknownBlock
+	}
+	var (
+		records = make([]*blockRecord, 0, to-from+1)
+		err     error
+
+		snap, voteSnap *Snapshot // snapshots of the parent and grandparent of the current header
+	)
+	for number := from; number <= to; number++ {
+		header := chain.GetHeaderByNumber(number)
+		if header == nil {
+			return nil, errUnknownBlock
+		}
+		if header.ParentHash != parent.Hash() {
+			return nil, errBlockHashInconsistent
+		}
+		if record, ok := p.tracker.records.Get(header.Hash()); ok {
+			records = append(records, record)
+			snap, voteSnap = nil, nil
+			parent = header
+			continue
+		}
+		if snap == nil {
+			if snap, err = p.snapshot(chain, number-1, header.ParentHash, nil); err != nil {
+				return nil, err
+			}
+		}
+		var votes *Snapshot
+		if number >= 2 && p.chainConfig.IsPlato(header.Number) {
+			if voteSnap == nil {
+				if voteSnap, err = p.snapshot(chain, number-2, parent.ParentHash, nil); err != nil {
+					return nil, err
+				}
+			}
+			votes = voteSnap
+		}
+		records = append(records, p.tracker.record(p, header, parent, snap, votes))
+
+		// Advance the snapshots, so the following missing records needn't rebuild them
+		voteSnap = snap
+		if snap, err = snap.apply([]*types.Header{header}, chain, nil, p.chainConfig); err != nil {
+			snap, voteSnap = nil, nil
+		}
+		parent = header
+	}
+	return records, nil
+}
+
+// ValidatorStats is the performance of the validators over a range of blocks.
+type ValidatorStats struct {
+	FromBlock  uint64                            `json:"fromBlock"`
+	ToBlock    uint64                            `json:"toBlock"`
+	Validators map[common.Address]*ValidatorStat `json:"validators"`
+}
+
+// ValidatorStat is the performance of a single validator.
+type ValidatorStat struct {
+	BlocksProduced    uint64 `json:"blocksProduced"`
+	InTurnBlocks      uint64 `json:"inTurnBlocks"`
+	OutOfTurnBlocks   uint64 `json:"outOfTurnBlocks"`
+	InTurnSlots       uint64 `json:"inTurnSlots"`
+	MissedInTurnSlots uint64 `json:"missedInTurnSlots"`
+
+	VotesExpected            uint64  `json:"votesExpected"`
+	VotesIncluded            uint64  `json:"votesIncluded"`
+	AttestationInclusionRate float64 `json:"attestationInclusionRate"`
+
+	AverageDelay float64 `json:"averageDelay"` // seconds beyond the period after the parent
+
+	totalDelay uint64
+}
+
+// aggregateValidatorStats sums up the block records into per validator statistics.
+func aggregateValidatorStats(records []*blockRecord) map[common.Address]*ValidatorStat {
+	stats := make(map[common.Address]*ValidatorStat)
+	get := func(val common.Address) *ValidatorStat {
+		stat, ok := stats[val]
+		if !ok {
+			stat = new(ValidatorStat)
+			stats[val] = stat
+		}
+		return stat
+	}
+	for _, record := range records {
+		proposer := get(record.proposer)
+		proposer.BlocksProduced++
+		proposer.totalDelay += record.delay
+
+		get(record.inturn).InTurnSlots++
+		if record.proposer == record.inturn {
+			proposer.InTurnBlocks++
+		} else {
+			proposer.OutOfTurnBlocks++
+			get(record.inturn).MissedInTurnSlots++
+		}
+
+		for index, voter := range record.voters {
+			stat := get(voter)
+			stat.VotesExpected++
+			if index < 64 && record.attested&(1<<uint(index)) != 0 {
+				stat.VotesIncluded++
+			}
+		}
+	}
+	for _, stat := range stats {
+		if stat.BlocksProduced > 0 {
+			stat.AverageDelay = float64(stat.totalDelay) / float64(stat.BlocksProduced)
+		}
+		if stat.VotesExpected > 0 {
+			stat.AttestationInclusionRate = float64(stat.VotesIncluded) / float64(stat.VotesExpected)
+		}
+	}
+	return stats
+}
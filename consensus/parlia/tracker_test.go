@@ -0,0 +1,43 @@
+package parlia
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestAggregateValidatorStats(t *testing.T) {
+	var (
+		val1   = common.Address{0x01}
+		val2   = common.Address{0x02}
+		val3   = common.Address{0x03}
+		voters = []common.Address{val1, val2, val3}
+	)
+	records := []*blockRecord{
+		{proposer: val1, inturn: val1, voters: voters, attested: 0b111},
+		{proposer: val3, inturn: val2, delay: 2, voters: voters, attested: 0b101},
+		{proposer: val3, inturn: val3, voters: voters, attested: 0b001},
+		{proposer: val1, inturn: val1, delay: 1}, // before the votes are attested
+	}
+	stats := aggregateValidatorStats(records)
+
+	assert.Equal(t, uint64(2), stats[val1].BlocksProduced)
+	assert.Equal(t, uint64(2), stats[val1].InTurnBlocks)
+	assert.Equal(t, 0.5, stats[val1].AverageDelay)
+	assert.Equal(t, 1.0, stats[val1].AttestationInclusionRate)
+
+	assert.Equal(t, uint64(0), stats[val2].BlocksProduced)
+	assert.Equal(t, uint64(1), stats[val2].InTurnSlots)
+	assert.Equal(t, uint64(1), stats[val2].MissedInTurnSlots)
+	assert.Equal(t, uint64(3), stats[val2].VotesExpected)
+	assert.Equal(t, uint64(1), stats[val2].VotesIncluded)
+
+	assert.Equal(t, uint64(2), stats[val3].BlocksProduced)
+	assert.Equal(t, uint64(1), stats[val3].InTurnBlocks)
+	assert.Equal(t, uint64(1), stats[val3].OutOfTurnBlocks)
+	assert.Equal(t, uint64(0), stats[val3].MissedInTurnSlots)
+	assert.Equal(t, 1.0, stats[val3].AverageDelay)
+	assert.Equal(t, uint64(2), stats[val3].VotesIncluded)
+}
@@ -0,0 +1,175 @@
+package blobarchive
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// errSidecarsUnavailable is returned when exporting a block whose sidecars were
+// already pruned.
+var errSidecarsUnavailable = errors.New("blob sidecars no longer available")
+
+// Archive serves the sidecars of the blob archives of a directory.
+type Archive struct {
+	dir     string
+	network string
+}
+
+// NewArchive creates an archive reading the blob archives of the network from
+// the given directory.
+func NewArchive(dir, network string) *Archive {
+	return &Archive{dir: dir, network: network}
+}
+
+// Sidecars returns the archived sidecars of the block, or nil if the block isn't
+// archived or has no sidecars.
+func (a *Archive) Sidecars(number uint64, hash common.Hash) (types.BlobSidecars, error) {
+	r, err := Open(filepath.Join(a.dir, Filename(a.network, number/EpochSize)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	archived, sidecars, err := r.Sidecars(number)
+	if err != nil || archived != hash {
+		return nil, err
+	}
+	return sidecars, nil
+}
+
+// ExportEpoch writes the sidecars of the canonical blocks of the epoch into an
+// archive in the directory, returning its path. The whole epoch must be in the
+// database, along with the sidecars of all the blocks with blob transactions.
+func ExportEpoch(db ethdb.Reader, dir, network string, epoch uint64) (string, error) {
+	path := filepath.Join(dir, Filename(network, epoch))
+	return path, writeFile(path, func(w io.Writer) error {
+		start := epoch * EpochSize
+		writer, err := NewWriter(w, start)
+		if err != nil {
+			return err
+		}
+		for number := start; number < start+EpochSize; number++ {
+			hash := rawdb.ReadCanonicalHash(db, number)
+			if hash == (common.Hash{}) {
+				return fmt.Errorf("block %d not found", number)
+			}
+			body := rawdb.ReadBody(db, hash, number)
+			if body == nil {
+				return fmt.Errorf("body of block %d not found", number)
+			}
+			blobTxs := 0
+			for _, tx := range body.Transactions {
+				if tx.Type() == types.BlobTxType {
+					blobTxs++
+				}
+			}
+			if blobTxs == 0 {
+				continue
+			}
+			sidecars := rawdb.ReadBlobSidecars(db, hash, number)
+			if len(sidecars) != blobTxs {
+				return fmt.Errorf("%w: block %d", errSidecarsUnavailable, number)
+			}
+			if err := writer.Add(number, hash, sidecars); err != nil {
+				return err
+			}
+		}
+		return writer.Finalize()
+	})
+}
+
+// VerifyArchive checks the archive against the canonical chain of the database.
+// The sidecars must belong to the canonical blocks, match their blob transactions
+// and carry valid KZG proofs, and no block with blob transactions may be missing.
+// The epoch of the archive is returned.
+func VerifyArchive(db ethdb.Reader, path string) (uint64, error) {
+	r, err := Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+
+	start := r.Start()
+	if start%EpochSize != 0 {
+		return 0, fmt.Errorf("archive starts at block %d, not at an epoch boundary", start)
+	}
+	for number := start; number < start+EpochSize; number++ {
+		hash := rawdb.ReadCanonicalHash(db, number)
+		if hash == (common.Hash{}) {
+			return 0, fmt.Errorf("block %d unknown, can't verify its sidecars", number)
+		}
+		header, body := rawdb.ReadHeader(db, hash, number), rawdb.ReadBody(db, hash, number)
+		if header == nil || body == nil {
+			return 0, fmt.Errorf("block %d unknown, can't verify its sidecars", number)
+		}
+		archived, sidecars, err := r.Sidecars(number)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read sidecars of block %d: %w", number, err)
+		}
+		if sidecars != nil && archived != hash {
+			return 0, fmt.Errorf("sidecars of block %d belong to %s, canonical block is %s", number, archived, hash)
+		}
+		block := types.NewBlockWithHeader(header).WithBody(body.Transactions, body.Uncles).WithSidecars(sidecars)
+		if err := core.ValidateBlockSidecars(block); err != nil {
+			return 0, fmt.Errorf("invalid sidecars of block %d: %w", number, err)
+		}
+	}
+	return start / EpochSize, nil
+}
+
+// ImportArchive verifies the archive and copies it into the directory, returning
+// the path of the copy.
+func ImportArchive(db ethdb.Reader, path, dir, network string) (string, error) {
+	epoch, err := VerifyArchive(db, path)
+	if err != nil {
+		return "", err
+	}
+	src, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	dst := filepath.Join(dir, Filename(network, epoch))
+	return dst, writeFile(dst, func(w io.Writer) error {
+		_, err := io.Copy(w, src)
+		return err
+	})
+}
+
+// writeFile writes a file atomically, the content is written to a temporary file
+// renamed once complete.
+func writeFile(path string, write func(io.Writer) error) error {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,214 @@
+package blobarchive
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// newTestSidecar creates a valid sidecar holding a single blob.
+func newTestSidecar(t *testing.T, fill byte) *types.BlobTxSidecar {
+	var blob kzg4844.Blob
+	for i := 0; i < len(blob); i += 32 {
+		blob[i+31] = fill + byte(i/32) // keep every field element canonical
+	}
+	commitment, err := kzg4844.BlobToCommitment(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := kzg4844.ComputeBlobProof(blob, commitment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &types.BlobTxSidecar{
+		Blobs:       []kzg4844.Blob{blob},
+		Commitments: []kzg4844.Commitment{commitment},
+		Proofs:      []kzg4844.Proof{proof},
+	}
+}
+
+// newTestChain writes a chain of the given length into the database, with a
+// blob transaction in the blocks of the given numbers.
+func newTestChain(t *testing.T, db ethdb.Database, length uint64, blobBlocks map[uint64]byte) map[uint64]types.BlobSidecars {
+	var (
+		parent   common.Hash
+		sidecars = make(map[uint64]types.BlobSidecars)
+	)
+	for number := uint64(0); number < length; number++ {
+		header := &types.Header{Number: new(big.Int).SetUint64(number), ParentHash: parent, Difficulty: common.Big1}
+		body := &types.Body{}
+		var sidecar *types.BlobTxSidecar
+		if fill, ok := blobBlocks[number]; ok {
+			sidecar = newTestSidecar(t, fill)
+			body.Transactions = append(body.Transactions, types.NewTx(&types.BlobTx{
+				Nonce:      number,
+				GasFeeCap:  uint256.NewInt(1),
+				BlobFeeCap: uint256.NewInt(1),
+				BlobHashes: sidecar.BlobHashes(),
+			}))
+		}
+		hash := header.Hash()
+		rawdb.WriteHeader(db, header)
+		rawdb.WriteBody(db, hash, number, body)
+		rawdb.WriteCanonicalHash(db, hash, number)
+		if sidecar != nil {
+			sidecars[number] = types.BlobSidecars{{
+				BlobTxSidecar: *sidecar,
+				BlockNumber:   header.Number,
+				BlockHash:     hash,
+				TxHash:        body.Transactions[0].Hash(),
+			}}
+			rawdb.WriteBlobSidecars(db, hash, number, sidecars[number])
+		}
+		parent = hash
+	}
+	return sidecars
+}
+
+func TestExportImport(t *testing.T) {
+	defer func(size uint64) { EpochSize = size }(EpochSize)
+	EpochSize = 4
+
+	var (
+		db       = rawdb.NewMemoryDatabase()
+		sidecars = newTestChain(t, db, 8, map[uint64]byte{1: 1, 6: 2})
+		dir      = t.TempDir()
+		archive  = t.TempDir()
+	)
+	for epoch := uint64(0); epoch < 2; epoch++ {
+		path, err := ExportEpoch(db, dir, "test", epoch)
+		if err != nil {
+			t.Fatalf("failed to export epoch %d: %v", epoch, err)
+		}
+		if _, err := ImportArchive(db, path, archive, "test"); err != nil {
+			t.Fatalf("failed to import epoch %d: %v", epoch, err)
+		}
+	}
+	if epochs, _ := ReadDir(archive, "test"); len(epochs) != 2 {
+		t.Fatalf("imported archives mismatch: have %v, want 2", epochs)
+	}
+	served := NewArchive(archive, "test")
+	for number := uint64(0); number < 8; number++ {
+		hash := rawdb.ReadCanonicalHash(db, number)
+		have, err := served.Sidecars(number, hash)
+		if err != nil {
+			t.Fatalf("failed to read sidecars of block %d: %v", number, err)
+		}
+		if want := sidecars[number]; len(have) != len(want) || (len(want) > 0 && have[0].Blobs[0] != want[0].Blobs[0]) {
+			t.Fatalf("sidecars of block %d mismatch", number)
+		}
+		if have, _ := served.Sidecars(number, common.Hash{0xff}); have != nil {
+			t.Fatalf("sidecars of block %d served for another hash", number)
+		}
+	}
+	if have, _ := served.Sidecars(100, common.Hash{}); have != nil {
+		t.Fatal("sidecars served for a block not archived")
+	}
+
+	// Pruned sidecars can't be exported
+	rawdb.DeleteBlobSidecars(db, rawdb.ReadCanonicalHash(db, 6), 6)
+	if _, err := ExportEpoch(db, dir, "test", 1); !errors.Is(err, errSidecarsUnavailable) {
+		t.Fatalf("export of pruned sidecars: have %v, want %v", err, errSidecarsUnavailable)
+	}
+}
+
+func TestVerifyArchive(t *testing.T) {
+	defer func(size uint64) { EpochSize = size }(EpochSize)
+	EpochSize = 4
+
+	var (
+		db       = rawdb.NewMemoryDatabase()
+		sidecars = newTestChain(t, db, 4, map[uint64]byte{1: 1, 2: 2})
+		dir      = t.TempDir()
+	)
+	write := func(name string, entries map[uint64]types.BlobSidecars) string {
+		path := filepath.Join(dir, name)
+		err := writeFile(path, func(w io.Writer) error {
+			writer, err := NewWriter(w, 0)
+			if err != nil {
+				return err
+			}
+			for number := uint64(0); number < 4; number++ {
+				if entry, ok := entries[number]; ok {
+					if err := writer.Add(number, rawdb.ReadCanonicalHash(db, number), entry); err != nil {
+						return err
+					}
+				}
+			}
+			return writer.Finalize()
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	if _, err := VerifyArchive(db, write("valid", sidecars)); err != nil {
+		t.Fatalf("valid archive rejected: %v", err)
+	}
+	// Missing sidecars
+	if _, err := VerifyArchive(db, write("missing", map[uint64]types.BlobSidecars{1: sidecars[1]})); err == nil {
+		t.Fatal("archive missing sidecars accepted")
+	}
+	// Sidecars of another block
+	swapped := map[uint64]types.BlobSidecars{1: sidecars[2], 2: sidecars[1]}
+	if _, err := VerifyArchive(db, write("swapped", swapped)); err == nil {
+		t.Fatal("archive with swapped sidecars accepted")
+	}
+	// Invalid KZG proof
+	forged := *sidecars[2][0]
+	forged.BlobTxSidecar.Proofs = []kzg4844.Proof{sidecars[1][0].Proofs[0]}
+	if _, err := VerifyArchive(db, write("forged", map[uint64]types.BlobSidecars{1: sidecars[1], 2: {&forged}})); err == nil {
+		t.Fatal("archive with invalid proof accepted")
+	}
+}
+
+func TestOpenMalformed(t *testing.T) {
+	// An empty index entry, followed by the tail of an index of a single block
+	blob := make([]byte, 8+16+16)
+	binary.LittleEndian.PutUint16(blob, TypeSidecarIndex)
+	binary.LittleEndian.PutUint64(blob[len(blob)-8:], 1)
+
+	path := filepath.Join(t.TempDir(), "malformed")
+	if err := os.WriteFile(path, blob, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if r, err := Open(path); err == nil {
+		r.Close()
+		t.Fatal("archive with truncated index accepted")
+	}
+}
+
+func TestArchiverPrune(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{Filename("test", 1), Filename("test", 2), Filename("test", 4), Filename("other", 0)} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	a := &Archiver{dir: dir, network: "test", retention: 2}
+	a.prune()
+	if epochs, _ := ReadDir(dir, "test"); len(epochs) != 2 || epochs[0] != 2 || epochs[1] != 4 {
+		t.Fatalf("retained archives mismatch: have %v, want [2 4]", epochs)
+	}
+	if epochs, _ := ReadDir(dir, "other"); len(epochs) != 1 {
+		t.Fatalf("archives of another network pruned: %v", epochs)
+	}
+	// Without retention, all the archives are kept
+	a.retention = 0
+	a.prune()
+	if epochs, _ := ReadDir(dir, "test"); len(epochs) != 2 {
+		t.Fatalf("archives pruned without retention: %v", epochs)
+	}
+}
@@ -0,0 +1,146 @@
+package blobarchive
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// archiveRecheckInterval is the frequency to check for epochs to archive.
+const archiveRecheckInterval = time.Minute
+
+// Archiver writes the sidecars of the immutable epochs into blob archives in the
+// background, before they get pruned at the end of the data availability window.
+// The archives of the epochs older than the retention are deleted.
+type Archiver struct {
+	db        ethdb.Database
+	chain     *core.BlockChain
+	dir       string
+	network   string
+	retention uint64 // number of most recent epochs whose archives are kept, 0 keeps all
+	next      uint64 // next epoch to archive, zero until initialized
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewArchiver creates an archiver writing the archives into the given directory,
+// keeping those of the last retention epochs.
+func NewArchiver(db ethdb.Database, chain *core.BlockChain, dir string, retention uint64) (*Archiver, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	a := &Archiver{
+		db:        db,
+		chain:     chain,
+		dir:       dir,
+		network:   NetworkName(chain.Config()),
+		retention: retention,
+		quit:      make(chan struct{}),
+	}
+	epochs, err := ReadDir(dir, a.network)
+	if err != nil {
+		return nil, err
+	}
+	if len(epochs) > 0 {
+		a.next = epochs[len(epochs)-1] + 1
+	}
+	return a, nil
+}
+
+// Start starts archiving in the background.
+func (a *Archiver) Start() {
+	a.wg.Add(1)
+	go a.loop()
+}
+
+// Stop stops the archiver, waiting for the archive being written.
+func (a *Archiver) Stop() {
+	close(a.quit)
+	a.wg.Wait()
+}
+
+func (a *Archiver) loop() {
+	defer a.wg.Done()
+
+	ticker := time.NewTicker(archiveRecheckInterval)
+	defer ticker.Stop()
+
+	for {
+		a.archive()
+		a.prune()
+
+		select {
+		case <-ticker.C:
+		case <-a.quit:
+			return
+		}
+	}
+}
+
+// archive writes the archives of the epochs which became immutable.
+func (a *Archiver) archive() {
+	head := a.chain.CurrentBlock().Number.Uint64()
+	if head < params.FullImmutabilityThreshold {
+		return
+	}
+	limit := head - params.FullImmutabilityThreshold
+
+	// Without any archive, start with the oldest epoch still available
+	if a.next == 0 && head > params.MinBlocksForBlobRequests {
+		a.next = (head - params.MinBlocksForBlobRequests + EpochSize - 1) / EpochSize
+	}
+	for ; (a.next+1)*EpochSize-1 <= limit; a.next++ {
+		select {
+		case <-a.quit:
+			return
+		default:
+		}
+		last := a.chain.GetHeaderByNumber((a.next+1)*EpochSize - 1)
+		if last == nil || !a.chain.Config().IsCancun(last.Number, last.Time) {
+			continue
+		}
+		start := time.Now()
+		path, err := ExportEpoch(a.db, a.dir, a.network, a.next)
+		if errors.Is(err, errSidecarsUnavailable) {
+			log.Warn("Blob sidecars pruned before being archived", "epoch", a.next, "err", err)
+			continue
+		}
+		if err != nil {
+			log.Error("Failed to archive blob sidecars", "epoch", a.next, "err", err)
+			return
+		}
+		log.Info("Archived blob sidecars", "epoch", a.next, "path", path, "elapsed", common.PrettyDuration(time.Since(start)))
+	}
+}
+
+// prune deletes the archives of the epochs older than the retention.
+func (a *Archiver) prune() {
+	if a.retention == 0 {
+		return
+	}
+	epochs, err := ReadDir(a.dir, a.network)
+	if err != nil {
+		log.Error("Failed to list blob archives", "dir", a.dir, "err", err)
+		return
+	}
+	if uint64(len(epochs)) <= a.retention {
+		return
+	}
+	for _, epoch := range epochs[:uint64(len(epochs))-a.retention] {
+		path := filepath.Join(a.dir, Filename(a.network, epoch))
+		if err := os.Remove(path); err != nil {
+			log.Error("Failed to delete blob archive", "path", path, "err", err)
+			continue
+		}
+		log.Info("Deleted expired blob archive", "epoch", epoch, "path", path)
+	}
+}
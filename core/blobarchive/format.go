@@ -0,0 +1,247 @@
+package blobarchive
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/golang/snappy"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/internal/era/e2store"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// A blob archive is an e2store file holding the sidecars of the blocks of an
+// epoch, only the blocks with sidecars have an entry:
+//
+//	blob1 := Version | sidecars* | index
+//	index := number0 | offset0 | ... | numberN | offsetN | start | count
+//
+// The offsets are the positions of the sidecars entries in the file, the numbers,
+// offsets, start and count are 8 byte little-endian integers.
+var (
+	TypeVersion            uint16 = 0x3267
+	TypeCompressedSidecars uint16 = 0x08
+	TypeSidecarIndex       uint16 = 0x3268
+
+	// EpochSize is the number of blocks covered by an archive.
+	EpochSize uint64 = 8192
+)
+
+const extension = ".blob1"
+
+// blockSidecars is the content of a sidecars entry.
+type blockSidecars struct {
+	Number   uint64
+	Hash     common.Hash
+	Sidecars types.BlobSidecars
+}
+
+// Filename returns the name of the archive of the epoch for the given network.
+func Filename(network string, epoch uint64) string {
+	return fmt.Sprintf("%s-%05d%s", network, epoch, extension)
+}
+
+// NetworkName returns the name of the network used in the archive file names.
+func NetworkName(config *params.ChainConfig) string {
+	if name, ok := params.NetworkNames[config.ChainID.String()]; ok {
+		return name
+	}
+	return config.ChainID.String()
+}
+
+// ReadDir returns the epochs of the archives of the network in the directory, in
+// ascending order.
+func ReadDir(dir, network string) ([]uint64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var epochs []uint64
+	for _, entry := range entries {
+		name := entry.Name()
+		if filepath.Ext(name) != extension || !strings.HasPrefix(name, network+"-") {
+			continue
+		}
+		epoch, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(name, network+"-"), extension), 10, 64)
+		if err != nil {
+			continue
+		}
+		epochs = append(epochs, epoch)
+	}
+	sort.Slice(epochs, func(i, j int) bool { return epochs[i] < epochs[j] })
+	return epochs, nil
+}
+
+// Writer writes the sidecars of an epoch into an archive.
+type Writer struct {
+	w       *e2store.Writer
+	start   uint64
+	written uint64
+	numbers []uint64
+	offsets []uint64
+}
+
+// NewWriter creates an archive writer for the epoch starting at the given block.
+func NewWriter(w io.Writer, start uint64) (*Writer, error) {
+	writer := &Writer{w: e2store.NewWriter(w), start: start}
+	n, err := writer.w.Write(TypeVersion, nil)
+	if err != nil {
+		return nil, err
+	}
+	writer.written = uint64(n)
+	return writer, nil
+}
+
+// Add appends the sidecars of a block, blocks must be added in ascending order.
+func (w *Writer) Add(number uint64, hash common.Hash, sidecars types.BlobSidecars) error {
+	if number < w.start || number >= w.start+EpochSize {
+		return fmt.Errorf("block %d out of the epoch starting at %d", number, w.start)
+	}
+	if len(w.numbers) > 0 && number <= w.numbers[len(w.numbers)-1] {
+		return fmt.Errorf("block %d added out of order", number)
+	}
+	blob, err := rlp.EncodeToBytes(&blockSidecars{Number: number, Hash: hash, Sidecars: sidecars})
+	if err != nil {
+		return err
+	}
+	n, err := w.w.Write(TypeCompressedSidecars, snappy.Encode(nil, blob))
+	if err != nil {
+		return err
+	}
+	w.numbers = append(w.numbers, number)
+	w.offsets = append(w.offsets, w.written)
+	w.written += uint64(n)
+	return nil
+}
+
+// Finalize writes the index of the archive.
+func (w *Writer) Finalize() error {
+	index := make([]byte, 16*len(w.numbers)+16)
+	for i, number := range w.numbers {
+		binary.LittleEndian.PutUint64(index[16*i:], number)
+		binary.LittleEndian.PutUint64(index[16*i+8:], w.offsets[i])
+	}
+	binary.LittleEndian.PutUint64(index[len(index)-16:], w.start)
+	binary.LittleEndian.PutUint64(index[len(index)-8:], uint64(len(w.numbers)))
+	_, err := w.w.Write(TypeSidecarIndex, index)
+	return err
+}
+
+// Reader reads the sidecars of an archive.
+type Reader struct {
+	f       *os.File
+	s       *e2store.Reader
+	start   uint64
+	numbers []uint64
+	offsets []uint64
+}
+
+// Open opens the archive at the given path.
+func Open(path string) (*Reader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	r, err := newReader(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("invalid blob archive %s: %w", path, err)
+	}
+	return r, nil
+}
+
+func newReader(f *os.File) (*Reader, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+	if size < 8+16 {
+		return nil, errors.New("archive too short")
+	}
+	var tail [16]byte
+	if _, err := f.ReadAt(tail[:], size-16); err != nil {
+		return nil, err
+	}
+	var (
+		start = binary.LittleEndian.Uint64(tail[:])
+		count = binary.LittleEndian.Uint64(tail[8:])
+		s     = e2store.NewReader(f)
+	)
+	if count > EpochSize || int64(8+16*count+16) > size {
+		return nil, errors.New("invalid index length")
+	}
+	entry := new(e2store.Entry)
+	if _, err := s.ReadAt(entry, size-int64(8+16*count+16)); err != nil {
+		return nil, err
+	}
+	if entry.Type != TypeSidecarIndex {
+		return nil, fmt.Errorf("invalid index type %x", entry.Type)
+	}
+	if uint64(len(entry.Value)) != 16*count+16 {
+		return nil, fmt.Errorf("invalid index size %d, want %d", len(entry.Value), 16*count+16)
+	}
+	r := &Reader{f: f, s: s, start: start}
+	for i := uint64(0); i < count; i++ {
+		number := binary.LittleEndian.Uint64(entry.Value[16*i:])
+		if number < start || number >= start+EpochSize || (i > 0 && number <= r.numbers[i-1]) {
+			return nil, fmt.Errorf("invalid index entry for block %d", number)
+		}
+		r.numbers = append(r.numbers, number)
+		r.offsets = append(r.offsets, binary.LittleEndian.Uint64(entry.Value[16*i+8:]))
+	}
+	return r, nil
+}
+
+// Close closes the archive.
+func (r *Reader) Close() error {
+	return r.f.Close()
+}
+
+// Start returns the first block of the epoch of the archive.
+func (r *Reader) Start() uint64 {
+	return r.start
+}
+
+// Numbers returns the numbers of the blocks with sidecars in the archive.
+func (r *Reader) Numbers() []uint64 {
+	return r.numbers
+}
+
+// Sidecars returns the block hash and the sidecars of the given block. Nil is
+// returned if the block has no sidecars in the archive.
+func (r *Reader) Sidecars(number uint64) (common.Hash, types.BlobSidecars, error) {
+	i := sort.Search(len(r.numbers), func(i int) bool { return r.numbers[i] >= number })
+	if i == len(r.numbers) || r.numbers[i] != number {
+		return common.Hash{}, nil, nil
+	}
+	entry := new(e2store.Entry)
+	if _, err := r.s.ReadAt(entry, int64(r.offsets[i])); err != nil {
+		return common.Hash{}, nil, err
+	}
+	if entry.Type != TypeCompressedSidecars {
+		return common.Hash{}, nil, fmt.Errorf("invalid entry type %x for block %d", entry.Type, number)
+	}
+	blob, err := snappy.Decode(nil, entry.Value)
+	if err != nil {
+		return common.Hash{}, nil, err
+	}
+	var content blockSidecars
+	if err := rlp.DecodeBytes(blob, &content); err != nil {
+		return common.Hash{}, nil, err
+	}
+	if content.Number != number {
+		return common.Hash{}, nil, fmt.Errorf("entry of block %d indexed as block %d", content.Number, number)
+	}
+	return content.Hash, content.Sidecars, nil
+}
@@ -43,7 +43,6 @@ import (
 	"github.com/ethereum/go-ethereum/common/prque"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
-	"github.com/ethereum/go-ethereum/core/monitor"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/state/snapshot"
@@ -319,9 +318,6 @@ type BlockChain struct {
 	forker     *ForkChoice
 	vmConfig   vm.Config
 	pipeCommit bool
-
-	// monitor
-	doubleSignMonitor *monitor.DoubleSignMonitor
 }
 
 // NewBlockChain returns a fully initialised block chain using information
@@ -566,11 +562,6 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, genesis *Genesis
 		go bc.rewindInvalidHeaderBlockLoop()
 	}
 
-	if bc.doubleSignMonitor != nil {
-		bc.wg.Add(1)
-		go bc.startDoubleSignMonitor()
-	}
-
 	// Rewind the chain in case of an incompatible config upgrade.
 	if compat, ok := genesisErr.(*params.ConfigCompatError); ok {
 		log.Warn("Rewinding chain to upgrade configuration", "err", compat)
@@ -3053,27 +3044,6 @@ func (bc *BlockChain) trustedDiffLayerLoop() {
 	}
 }
 
-func (bc *BlockChain) startDoubleSignMonitor() {
-	eventChan := make(chan ChainHeadEvent, monitor.MaxCacheHeader)
-	sub := bc.SubscribeChainHeadEvent(eventChan)
-	defer func() {
-		sub.Unsubscribe()
-		close(eventChan)
-		bc.wg.Done()
-	}()
-
-	for {
-		select {
-		case event := <-eventChan:
-			if bc.doubleSignMonitor != nil {
-				bc.doubleSignMonitor.Verify(event.Block.Header())
-			}
-		case <-bc.quit:
-			return
-		}
-	}
-}
-
 // skipBlock returns 'true', if the block being imported can be skipped over, meaning
 // that the block does not need to be processed but can be considered already fully 'done'.
 func (bc *BlockChain) skipBlock(err error, it *insertIterator) bool {
@@ -3213,11 +3183,6 @@ func EnableBlockValidator(chainConfig *params.ChainConfig, engine consensus.Engi
 	}
 }
 
-func EnableDoubleSignChecker(bc *BlockChain) (*BlockChain, error) {
-	bc.doubleSignMonitor = monitor.NewDoubleSignMonitor()
-	return bc, nil
-}
-
 func (bc *BlockChain) GetVerifyResult(blockNumber uint64, blockHash common.Hash, diffHash common.Hash) *VerifyResult {
 	var res VerifyResult
 	res.BlockNumber = blockNumber
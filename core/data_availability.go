@@ -81,6 +81,12 @@ func IsDataAvailable(chain consensus.ChainHeaderReader, block *types.Block) (err
 	if block.Sidecars() == nil {
 		block.CleanSidecars()
 	}
+	return ValidateBlockSidecars(block)
+}
+
+// ValidateBlockSidecars checks the sidecars of the block against its blob
+// transactions, including the KZG proofs of the blobs.
+func ValidateBlockSidecars(block *types.Block) error {
 	sidecars := block.Sidecars()
 	for _, s := range sidecars {
 		if err := s.SanityCheck(block.Number(), block.Hash()); err != nil {
@@ -27,6 +27,29 @@ type NewTxsEvent struct{ Txs []*types.Transaction }
 // ReannoTxsEvent is posted when a batch of local pending transactions exceed a specified duration.
 type ReannoTxsEvent struct{ Txs []*types.Transaction }
 
+// TxPoolEventReason is the reason of a transaction leaving, or being refused by,
+// the transaction pool.
+type TxPoolEventReason string
+
+const (
+	TxReplaced          TxPoolEventReason = "replaced"          // replaced by a transaction with the same nonce
+	TxUnderpriced       TxPoolEventReason = "underpriced"       // dropped for paying less than the pool requires
+	TxNonceTooLow       TxPoolEventReason = "nonceTooLow"       // nonce spent by another transaction
+	TxInsufficientFunds TxPoolEventReason = "insufficientFunds" // sender can't pay for it anymore
+	TxEvicted           TxPoolEventReason = "evicted"           // evicted on pool overflow or expiry
+	TxMined             TxPoolEventReason = "mined"             // included in the chain
+	TxBlacklisted       TxPoolEventReason = "blacklisted"       // sent from or to a blacklisted account
+	TxExpired           TxPoolEventReason = "expired"           // private transaction past its last includable block
+)
+
+// TxPoolEvent is posted, in batches, when a transaction leaves or is refused by
+// the transaction pool.
+type TxPoolEvent struct {
+	Hash       common.Hash
+	Reason     TxPoolEventReason
+	ReplacedBy *common.Hash // Hash of the replacing transaction, if replaced
+}
+
 // NewMinedBlockEvent is posted when a block has been imported.
 type NewMinedBlockEvent struct{ Block *types.Block }
 
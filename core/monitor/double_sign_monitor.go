@@ -2,6 +2,7 @@ package monitor
 
 import (
 	"bytes"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/prque"
@@ -76,27 +77,32 @@ func (m *DoubleSignMonitor) checkHeader(h *types.Header) (bool, *types.Header, e
 	return false, nil, nil
 }
 
-func (m *DoubleSignMonitor) Verify(h *types.Header) {
+// Verify checks the header against the cached header of the same height, and returns
+// the evidence if both are signed by the same validator, nil otherwise.
+func (m *DoubleSignMonitor) Verify(h *types.Header) *types.DoubleSignEvidence {
 	isDoubleSign, h2, err := m.checkHeader(h)
 	if err != nil {
 		log.Error("check double sign header error", "err", err)
-		return
+		return nil
 	}
-	if isDoubleSign {
-		// found a double sign header
-		log.Warn("found a double sign header", "number", h.Number.Uint64(),
-			"first_hash", h.Hash(), "first_miner", h.Coinbase,
-			"second_hash", h2.Hash(), "second_miner", h2.Coinbase)
-		h1Bytes, err := rlp.EncodeToBytes(h)
-		if err != nil {
-			log.Error("encode header error", "err", err, "hash", h.Hash())
-		}
-		h2Bytes, err := rlp.EncodeToBytes(h2)
-		if err != nil {
-			log.Error("encode header error", "err", err, "hash", h.Hash())
-		}
-		log.Warn("double sign header content",
-			"header1", hexutil.Encode(h1Bytes),
-			"header2", hexutil.Encode(h2Bytes))
+	if !isDoubleSign {
+		return nil
+	}
+	// found a double sign header
+	log.Warn("found a double sign header", "number", h.Number.Uint64(),
+		"first_hash", h.Hash(), "first_miner", h.Coinbase,
+		"second_hash", h2.Hash(), "second_miner", h2.Coinbase)
+	h1Bytes, err := rlp.EncodeToBytes(h)
+	if err != nil {
+		log.Error("encode header error", "err", err, "hash", h.Hash())
+	}
+	h2Bytes, err := rlp.EncodeToBytes(h2)
+	if err != nil {
+		log.Error("encode header error", "err", err, "hash", h.Hash())
 	}
+	log.Warn("double sign header content",
+		"header1", hexutil.Encode(h1Bytes),
+		"header2", hexutil.Encode(h2Bytes))
+
+	return types.NewDoubleSignEvidence(h, h2, uint64(time.Now().Unix()))
 }
@@ -0,0 +1,35 @@
+package monitor
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestDoubleSignMonitor(t *testing.T) {
+	var (
+		monitor   = NewDoubleSignMonitor()
+		parent    = common.Hash{0x01}
+		validator = common.Address{0x02}
+	)
+	h1 := &types.Header{ParentHash: parent, Number: big.NewInt(10), Coinbase: validator, Difficulty: big.NewInt(2), Time: 1}
+	h2 := &types.Header{ParentHash: parent, Number: big.NewInt(10), Coinbase: validator, Difficulty: big.NewInt(2), Time: 2}
+	other := &types.Header{ParentHash: parent, Number: big.NewInt(10), Coinbase: common.Address{0x03}, Difficulty: big.NewInt(2), Time: 3}
+
+	assert.Nil(t, monitor.Verify(h1))
+	assert.Nil(t, monitor.Verify(h1))    // the same header again
+	assert.Nil(t, monitor.Verify(other)) // signed by another validator
+
+	evidence := monitor.Verify(h2)
+	assert.NotNil(t, evidence)
+	assert.Equal(t, validator, evidence.Validator())
+	assert.Equal(t, uint64(10), evidence.Number())
+	assert.False(t, evidence.Submitted())
+
+	// the evidence of the same pair is identical whatever the order
+	assert.Equal(t, evidence.Hash(), types.NewDoubleSignEvidence(h2, h1, 0).Hash())
+}
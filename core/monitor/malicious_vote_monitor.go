@@ -35,12 +35,17 @@ func NewMaliciousVoteMonitor() *MaliciousVoteMonitor {
 }
 
 func (m *MaliciousVoteMonitor) ConflictDetect(newVote *types.VoteEnvelope, pendingBlockNumber uint64) bool {
+	return m.Detect(newVote, pendingBlockNumber) != nil
+}
+
+// Detect returns the previous vote of the same validator conflicting with the new vote, nil if none.
+func (m *MaliciousVoteMonitor) Detect(newVote *types.VoteEnvelope, pendingBlockNumber uint64) *types.VoteEnvelope {
 	// get votes for specified VoteAddress
 	if _, ok := m.curVotes[newVote.VoteAddress]; !ok {
 		voteDataBuffer, err := lru.New(maxSizeOfRecentEntry)
 		if err != nil {
 			log.Error("MaliciousVoteMonitor new lru failed", "err", err)
-			return false
+			return nil
 		}
 		m.curVotes[newVote.VoteAddress] = voteDataBuffer
 	}
@@ -50,7 +55,7 @@ func (m *MaliciousVoteMonitor) ConflictDetect(newVote *types.VoteEnvelope, pendi
 	//Basic check
 	// refer to https://github.com/bnb-chain/bsc-genesis-contract/blob/master/contracts/SlashIndicator.sol#LL207C4-L207C4
 	if !(targetNumber+maliciousVoteSlashScope > pendingBlockNumber) {
-		return false
+		return nil
 	}
 
 	// UnderRules check
@@ -86,12 +91,12 @@ func (m *MaliciousVoteMonitor) ConflictDetect(newVote *types.VoteEnvelope, pendi
 				} else {
 					log.Warn("MaliciousVote, construct evidence failed")
 				}
-				return true
+				return voteEnvelope.(*types.VoteEnvelope)
 			}
 		}
 	}
 
 	// for simplicity, Just override even if the targetNumber has existed.
 	voteDataBuffer.Add(newVote.Data.TargetNumber, newVote)
-	return false
+	return nil
 }
@@ -0,0 +1,115 @@
+package rawdb
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// ReadDoubleSignEvidence retrieves the double sign evidence of the given hash.
+func ReadDoubleSignEvidence(db ethdb.KeyValueReader, hash common.Hash) *types.DoubleSignEvidence {
+	data, _ := db.Get(doubleSignEvidenceKey(hash))
+	if len(data) == 0 {
+		return nil
+	}
+	evidence := new(types.DoubleSignEvidence)
+	if err := rlp.DecodeBytes(data, evidence); err != nil {
+		log.Error("Invalid double sign evidence RLP", "hash", hash, "err", err)
+		return nil
+	}
+	return evidence
+}
+
+// ReadAllDoubleSignEvidences retrieves all the stored double sign evidences.
+func ReadAllDoubleSignEvidences(db ethdb.Iteratee) []*types.DoubleSignEvidence {
+	var evidences []*types.DoubleSignEvidence
+
+	it := db.NewIterator(DoubleSignEvidencePrefix, nil)
+	defer it.Release()
+
+	for it.Next() {
+		if len(it.Key()) != len(DoubleSignEvidencePrefix)+common.HashLength {
+			continue
+		}
+		evidence := new(types.DoubleSignEvidence)
+		if err := rlp.DecodeBytes(it.Value(), evidence); err != nil {
+			log.Error("Invalid double sign evidence RLP", "key", it.Key(), "err", err)
+			continue
+		}
+		evidences = append(evidences, evidence)
+	}
+	return evidences
+}
+
+// WriteDoubleSignEvidence stores the double sign evidence, overwriting the previous one of the same hash.
+func WriteDoubleSignEvidence(db ethdb.KeyValueWriter, evidence *types.DoubleSignEvidence) {
+	data, err := rlp.EncodeToBytes(evidence)
+	if err != nil {
+		log.Crit("Failed to encode double sign evidence", "err", err)
+	}
+	if err := db.Put(doubleSignEvidenceKey(evidence.Hash()), data); err != nil {
+		log.Crit("Failed to store double sign evidence", "err", err)
+	}
+}
+
+// DeleteDoubleSignEvidence removes the double sign evidence of the given hash.
+func DeleteDoubleSignEvidence(db ethdb.KeyValueWriter, hash common.Hash) {
+	if err := db.Delete(doubleSignEvidenceKey(hash)); err != nil {
+		log.Crit("Failed to delete double sign evidence", "err", err)
+	}
+}
+
+// ReadFinalityViolationEvidence retrieves the finality violation evidence of the given hash.
+func ReadFinalityViolationEvidence(db ethdb.KeyValueReader, hash common.Hash) *types.FinalityViolationEvidence {
+	data, _ := db.Get(finalityViolationEvidenceKey(hash))
+	if len(data) == 0 {
+		return nil
+	}
+	evidence := new(types.FinalityViolationEvidence)
+	if err := rlp.DecodeBytes(data, evidence); err != nil {
+		log.Error("Invalid finality violation evidence RLP", "hash", hash, "err", err)
+		return nil
+	}
+	return evidence
+}
+
+// ReadAllFinalityViolationEvidences retrieves all the stored finality violation evidences.
+func ReadAllFinalityViolationEvidences(db ethdb.Iteratee) []*types.FinalityViolationEvidence {
+	var evidences []*types.FinalityViolationEvidence
+
+	it := db.NewIterator(FinalityViolationEvidencePrefix, nil)
+	defer it.Release()
+
+	for it.Next() {
+		if len(it.Key()) != len(FinalityViolationEvidencePrefix)+common.HashLength {
+			continue
+		}
+		evidence := new(types.FinalityViolationEvidence)
+		if err := rlp.DecodeBytes(it.Value(), evidence); err != nil {
+			log.Error("Invalid finality violation evidence RLP", "key", it.Key(), "err", err)
+			continue
+		}
+		evidences = append(evidences, evidence)
+	}
+	return evidences
+}
+
+// WriteFinalityViolationEvidence stores the finality violation evidence, overwriting the previous one of the same hash.
+func WriteFinalityViolationEvidence(db ethdb.KeyValueWriter, evidence *types.FinalityViolationEvidence) {
+	data, err := rlp.EncodeToBytes(evidence)
+	if err != nil {
+		log.Crit("Failed to encode finality violation evidence", "err", err)
+	}
+	if err := db.Put(finalityViolationEvidenceKey(evidence.Hash()), data); err != nil {
+		log.Crit("Failed to store finality violation evidence", "err", err)
+	}
+}
+
+// DeleteFinalityViolationEvidence removes the finality violation evidence of the given hash.
+func DeleteFinalityViolationEvidence(db ethdb.KeyValueWriter, hash common.Hash) {
+	if err := db.Delete(finalityViolationEvidenceKey(hash)); err != nil {
+		log.Crit("Failed to delete finality violation evidence", "err", err)
+	}
+}
@@ -0,0 +1,37 @@
+package rawdb
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestDoubleSignEvidenceStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	h1 := &types.Header{Number: big.NewInt(1), Coinbase: common.Address{0x01}, Difficulty: big.NewInt(2), Time: 1}
+	h2 := &types.Header{Number: big.NewInt(1), Coinbase: common.Address{0x01}, Difficulty: big.NewInt(2), Time: 2}
+	evidence := types.NewDoubleSignEvidence(h1, h2, 100)
+	hash := evidence.Hash()
+
+	if entry := ReadDoubleSignEvidence(db, hash); entry != nil {
+		t.Fatalf("Non existent evidence returned: %v", entry)
+	}
+	WriteDoubleSignEvidence(db, evidence)
+	entry := ReadDoubleSignEvidence(db, hash)
+	if entry == nil || entry.Hash() != hash || entry.DetectedAt != 100 || entry.Submitted() {
+		t.Fatalf("Stored evidence mismatch: have %v", entry)
+	}
+
+	evidence.TxHash = common.Hash{0x02}
+	WriteDoubleSignEvidence(db, evidence)
+	if all := ReadAllDoubleSignEvidences(db); len(all) != 1 || all[0].TxHash != evidence.TxHash {
+		t.Fatalf("Updated evidence mismatch: have %v", all)
+	}
+	DeleteDoubleSignEvidence(db, hash)
+	if entry := ReadDoubleSignEvidence(db, hash); entry != nil {
+		t.Fatalf("Deleted evidence returned: %v", entry)
+	}
+}
@@ -154,6 +154,9 @@ var (
 
 	BlockBlobSidecarsPrefix = []byte("blobs")
 
+	DoubleSignEvidencePrefix        = []byte("evidence-doublesign-") // DoubleSignEvidencePrefix + evidence hash -> double sign evidence
+	FinalityViolationEvidencePrefix = []byte("evidence-finality-")   // FinalityViolationEvidencePrefix + evidence hash -> finality violation evidence
+
 	preimageCounter    = metrics.NewRegisteredCounter("db/preimage/total", nil)
 	preimageHitCounter = metrics.NewRegisteredCounter("db/preimage/hits", nil)
 )
@@ -213,6 +216,16 @@ func blockBlobSidecarsKey(number uint64, hash common.Hash) []byte {
 	return append(append(BlockBlobSidecarsPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
 }
 
+// doubleSignEvidenceKey = DoubleSignEvidencePrefix + evidence hash
+func doubleSignEvidenceKey(hash common.Hash) []byte {
+	return append(DoubleSignEvidencePrefix, hash.Bytes()...)
+}
+
+// finalityViolationEvidenceKey = FinalityViolationEvidencePrefix + evidence hash
+func finalityViolationEvidenceKey(hash common.Hash) []byte {
+	return append(FinalityViolationEvidencePrefix, hash.Bytes()...)
+}
+
 // diffLayerKey = diffLayerKeyPrefix + hash
 func diffLayerKey(hash common.Hash) []byte {
 	return append(diffLayerPrefix, hash.Bytes()...)
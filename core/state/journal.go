@@ -37,6 +37,8 @@ type journalEntry interface {
 type journal struct {
 	entries []journalEntry         // Current changes tracked by the journal
 	dirties map[common.Address]int // Dirty accounts and the number of changes
+
+	onDirty func(common.Address) // Called before an account is first dirtied, if set
 }
 
 // newJournal creates a new initialized journal.
@@ -51,6 +53,9 @@ func newJournal() *journal {
 func (j *journal) append(entry journalEntry) {
 	j.entries = append(j.entries, entry)
 	if addr := entry.dirtied(); addr != nil {
+		if j.onDirty != nil && j.dirties[*addr] == 0 {
+			j.onDirty(*addr)
+		}
 		j.dirties[*addr]++
 	}
 }
@@ -76,6 +81,9 @@ func (j *journal) revert(statedb *StateDB, snapshot int) {
 // otherwise suggest it as clean. This method is an ugly hack to handle the RIPEMD
 // precompile consensus exception.
 func (j *journal) dirty(addr common.Address) {
+	if j.onDirty != nil && j.dirties[addr] == 0 {
+		j.onDirty(addr)
+	}
 	j.dirties[addr]++
 }
 
@@ -0,0 +1,176 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// multiTxSnapshot holds the accounts as they were before being first modified
+// since the snapshot was taken.
+type multiTxSnapshot struct {
+	accounts map[common.Address]*accountSnapshot
+	logSize  uint
+}
+
+// accountSnapshot is the state of an account held by the StateDB maps.
+type accountSnapshot struct {
+	addrHash common.Hash
+
+	object  *stateObject // nil if the object was not live
+	pending bool
+	dirty   bool
+
+	destruct      *types.StateAccount
+	destructExist bool
+
+	account            []byte
+	accountExist       bool
+	storage            map[common.Hash][]byte
+	storageExist       bool
+	accountOrigin      []byte
+	accountOriginExist bool
+	storageOrigin      map[common.Hash][]byte
+	storageOriginExist bool
+}
+
+// MultiTxSnapshot takes a snapshot of the state which, unlike Snapshot, can be
+// reverted to after the following transactions were finalised. Only the accounts
+// modified after the snapshot are copied, when they are first modified. It must
+// be taken and reverted between transactions, and the state root must not be
+// computed while the snapshot is active. Snapshots can be nested, each one must
+// be either reverted or discarded, the innermost first.
+func (s *StateDB) MultiTxSnapshot() {
+	s.multiTxSnapshots = append(s.multiTxSnapshots, &multiTxSnapshot{
+		accounts: make(map[common.Address]*accountSnapshot),
+		logSize:  s.logSize,
+	})
+	s.journal.onDirty = s.captureAccount
+}
+
+// RevertMultiTxSnapshot reverts the state to the last multi transaction snapshot
+// and removes it.
+func (s *StateDB) RevertMultiTxSnapshot() {
+	snap := s.popMultiTxSnapshot()
+	for addr, acc := range snap.accounts {
+		if acc.object == nil {
+			delete(s.stateObjects, addr)
+		} else {
+			// The captured object may be shared with an outer snapshot
+			s.stateObjects[addr] = acc.object.multiTxCopy()
+		}
+		setMember(s.stateObjectsPending, addr, acc.pending)
+		setMember(s.stateObjectsDirty, addr, acc.dirty)
+		setValue(s.stateObjectsDestruct, addr, acc.destruct, acc.destructExist)
+
+		setValue(s.accounts, acc.addrHash, acc.account, acc.accountExist)
+		setValue(s.storages, acc.addrHash, acc.storage, acc.storageExist)
+		setValue(s.accountsOrigin, addr, acc.accountOrigin, acc.accountOriginExist)
+		setValue(s.storagesOrigin, addr, acc.storageOrigin, acc.storageOriginExist)
+	}
+	for hash, logs := range s.logs {
+		kept := logs[:0]
+		for _, l := range logs {
+			if l.Index < snap.logSize {
+				kept = append(kept, l)
+			}
+		}
+		if len(kept) == 0 {
+			delete(s.logs, hash)
+		} else {
+			s.logs[hash] = kept
+		}
+	}
+	s.logSize = snap.logSize
+	s.clearJournalAndRefund()
+}
+
+// DiscardMultiTxSnapshot removes the last multi transaction snapshot, keeping
+// the changes made since.
+func (s *StateDB) DiscardMultiTxSnapshot() {
+	s.popMultiTxSnapshot()
+}
+
+func (s *StateDB) popMultiTxSnapshot() *multiTxSnapshot {
+	n := len(s.multiTxSnapshots)
+	if n == 0 {
+		panic("no multi transaction snapshot to remove")
+	}
+	snap := s.multiTxSnapshots[n-1]
+	s.multiTxSnapshots = s.multiTxSnapshots[:n-1]
+	if n == 1 {
+		s.journal.onDirty = nil
+	}
+	return snap
+}
+
+// captureAccount copies the account into the active multi transaction snapshots
+// which didn't see it modified yet. It's called before the account is dirtied.
+func (s *StateDB) captureAccount(addr common.Address) {
+	var acc *accountSnapshot
+	for _, snap := range s.multiTxSnapshots {
+		if _, ok := snap.accounts[addr]; ok {
+			continue
+		}
+		if acc == nil {
+			acc = s.snapshotAccount(addr)
+		}
+		snap.accounts[addr] = acc
+	}
+}
+
+func (s *StateDB) snapshotAccount(addr common.Address) *accountSnapshot {
+	acc := &accountSnapshot{addrHash: crypto.Keccak256Hash(addr.Bytes())}
+	if obj := s.stateObjects[addr]; obj != nil {
+		acc.object = obj.multiTxCopy()
+	}
+	_, acc.pending = s.stateObjectsPending[addr]
+	_, acc.dirty = s.stateObjectsDirty[addr]
+	acc.destruct, acc.destructExist = s.stateObjectsDestruct[addr]
+
+	acc.account, acc.accountExist = s.accounts[acc.addrHash]
+	acc.storage, acc.storageExist = s.storages[acc.addrHash]
+	acc.accountOrigin, acc.accountOriginExist = s.accountsOrigin[addr]
+	acc.storageOrigin, acc.storageOriginExist = s.storagesOrigin[addr]
+	return acc
+}
+
+// multiTxCopy copies the object with the flags deepCopy leaves out.
+func (s *stateObject) multiTxCopy() *stateObject {
+	obj := s.deepCopy(s.db)
+	obj.sharedOriginStorage = s.sharedOriginStorage
+	obj.created = s.created
+	return obj
+}
+
+func setMember(set map[common.Address]struct{}, addr common.Address, member bool) {
+	if member {
+		set[addr] = struct{}{}
+	} else {
+		delete(set, addr)
+	}
+}
+
+func setValue[K comparable, V any](m map[K]V, key K, value V, exist bool) {
+	if exist {
+		m[key] = value
+	} else {
+		delete(m, key)
+	}
+}
@@ -0,0 +1,374 @@
+package pruner
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/internal/era"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// backfillFlock marks a complete backfilled ancientDB, it contains the new offset.
+const backfillFlock = "BACKFILLFLOCK"
+
+// BlockBackfiller restores the block history dropped by the BlockPruner from Era1
+// archives, extending the offset of the ancientDB downward.
+//
+// The workflow mirrors the one of the BlockPruner: the imported blocks and the ones
+// still in the ancientDB are written into a new ancientDB, which replaces the old
+// one once complete. Nothing is written before all the archives are verified.
+type BlockBackfiller struct {
+	node           *node.Node
+	oldAncientPath string
+	newAncientPath string
+	eraDir         string
+	network        string
+	roots          []common.Hash // expected accumulators by epoch, optional
+}
+
+// NewBlockBackfiller creates a backfiller importing the Era1 archives of the
+// network from the given directory. If roots are given, the accumulator of each
+// archive must match the root of its epoch.
+func NewBlockBackfiller(n *node.Node, oldAncientPath, newAncientPath, eraDir, network string, roots []common.Hash) *BlockBackfiller {
+	return &BlockBackfiller{
+		node:           n,
+		oldAncientPath: oldAncientPath,
+		newAncientPath: newAncientPath,
+		eraDir:         eraDir,
+		network:        network,
+		roots:          roots,
+	}
+}
+
+// eraArchive is an Era1 archive covering a part of the backfilled range.
+type eraArchive struct {
+	path  string
+	epoch uint64
+	start uint64
+	count uint64
+}
+
+// Backfill imports the blocks from the given number up to the current offset of
+// the ancientDB. If from is zero, the lowest block available in the archives is
+// used instead.
+func (b *BlockBackfiller) Backfill(name string, cache, handles int, namespace string, from uint64) error {
+	if _, err := os.Stat(filepath.Join(b.newAncientPath, backfillFlock)); err == nil {
+		log.Info("Complete backfilled ancientDB found, resuming the replacement")
+		return b.replace(name, cache, handles, namespace)
+	}
+	if _, err := os.Stat(filepath.Join(b.oldAncientPath, backfillFlock)); err == nil {
+		log.Info("Backfilled ancientDB already in place")
+		return os.Remove(filepath.Join(b.oldAncientPath, backfillFlock))
+	}
+	if err := os.RemoveAll(b.newAncientPath); err != nil {
+		return err
+	}
+	chainDb, err := b.node.OpenDatabaseWithFreezer(name, cache, handles, b.oldAncientPath, namespace, false, true, false, false)
+	if err != nil {
+		return err
+	}
+	defer chainDb.Close()
+
+	offset := rawdb.ReadOffSetOfCurrentAncientFreezer(chainDb)
+	items, err := chainDb.BlockStore().ItemAmountInAncient()
+	if err != nil || items == 0 {
+		return errors.New("can't access the freezer or it's empty, abort")
+	}
+	if offset == 0 {
+		return errors.New("no block pruned from the ancientDB, nothing to backfill")
+	}
+	archives, err := b.archives(from, offset)
+	if err != nil {
+		return err
+	}
+	if from == 0 {
+		from = archives[0].start
+	}
+	if from >= offset {
+		return fmt.Errorf("backfill start %d not below the ancientDB offset %d", from, offset)
+	}
+	log.Info("Verifying Era1 archives", "from", from, "to", offset-1, "archives", len(archives))
+	if err := b.verify(chainDb, archives, from, offset); err != nil {
+		return err
+	}
+	log.Info("Era1 archives verified, backfilling the ancientDB", "from", from, "offset", offset, "items", items)
+	if err := b.write(chainDb, archives, from, offset, items); err != nil {
+		return err
+	}
+	chainDb.Close()
+
+	return b.replace(name, cache, handles, namespace)
+}
+
+// archives returns the archives covering the blocks in the [from, offset) range
+// in ascending order.
+func (b *BlockBackfiller) archives(from, offset uint64) ([]*eraArchive, error) {
+	entries, err := os.ReadDir(b.eraDir)
+	if err != nil {
+		return nil, err
+	}
+	var archives []*eraArchive
+	for _, entry := range entries {
+		parts := strings.Split(entry.Name(), "-")
+		if filepath.Ext(entry.Name()) != ".era1" || len(parts) != 3 || parts[0] != b.network {
+			continue
+		}
+		epoch, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("malformed era1 filename: %s", entry.Name())
+		}
+		e, err := era.Open(filepath.Join(b.eraDir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("error opening era1 file %s: %w", entry.Name(), err)
+		}
+		archive := &eraArchive{path: filepath.Join(b.eraDir, entry.Name()), epoch: epoch, start: e.Start(), count: e.Count()}
+		e.Close()
+
+		if archive.start < offset && archive.start+archive.count > from {
+			archives = append(archives, archive)
+		}
+	}
+	sort.Slice(archives, func(i, j int) bool { return archives[i].start < archives[j].start })
+
+	// The archives must cover the whole range without holes
+	if len(archives) == 0 {
+		return nil, fmt.Errorf("no %s era1 file below block %d found in %s", b.network, offset, b.eraDir)
+	}
+	if from != 0 && archives[0].start > from {
+		return nil, fmt.Errorf("missing blocks %d-%d in the era1 files", from, archives[0].start-1)
+	}
+	next := archives[0].start
+	for _, archive := range archives {
+		if archive.start > next {
+			return nil, fmt.Errorf("missing blocks %d-%d in the era1 files", next, archive.start-1)
+		}
+		next = max(next, archive.start+archive.count)
+	}
+	if next < offset {
+		return nil, fmt.Errorf("missing blocks %d-%d in the era1 files", next, offset-1)
+	}
+	return archives, nil
+}
+
+// verify checks the archives against their accumulators, and the backfilled range
+// against the canonical chain still in the database.
+func (b *BlockBackfiller) verify(db ethdb.Database, archives []*eraArchive, from, offset uint64) error {
+	var (
+		prevHash common.Hash
+		prevTd   *big.Int
+		start    = time.Now()
+		reported = time.Now()
+	)
+	for _, archive := range archives {
+		err := b.iterate(archive, func(block *types.Block, receipts types.Receipts, td *big.Int) error {
+			number := block.NumberU64()
+			if number < from || number >= offset {
+				return nil
+			}
+			if number > from && block.ParentHash() != prevHash {
+				return fmt.Errorf("block %d not linked to its parent, want parent %s, have %s", number, prevHash, block.ParentHash())
+			}
+			if hash := rawdb.ReadCanonicalHash(db, number); hash != (common.Hash{}) && hash != block.Hash() {
+				return fmt.Errorf("block %d mismatches the canonical chain, want %s, have %s", number, hash, block.Hash())
+			}
+			prevHash, prevTd = block.Hash(), td
+
+			if time.Since(reported) >= 8*time.Second {
+				log.Info("Verifying Era1 archives", "number", number, "elapsed", common.PrettyDuration(time.Since(start)))
+				reported = time.Now()
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	// The last backfilled block must be the parent of the first block still present
+	hash := rawdb.ReadCanonicalHash(db, offset)
+	header := rawdb.ReadHeader(db, hash, offset)
+	if header == nil {
+		return fmt.Errorf("block %d missing in the ancientDB", offset)
+	}
+	if header.ParentHash != prevHash {
+		return fmt.Errorf("era1 files not linked to block %d, want parent %s, have %s", offset, header.ParentHash, prevHash)
+	}
+	if td := rawdb.ReadTd(db, hash, offset); td == nil || new(big.Int).Add(prevTd, header.Difficulty).Cmp(td) != 0 {
+		return fmt.Errorf("total difficulty of the era1 files mismatches block %d", offset)
+	}
+	return nil
+}
+
+// iterate verifies the accumulator of the archive and calls fn for every block.
+// The callback is invoked before the whole archive is verified, any action must
+// be postponed until iterate returns.
+func (b *BlockBackfiller) iterate(archive *eraArchive, fn func(*types.Block, types.Receipts, *big.Int) error) error {
+	e, err := era.Open(archive.path)
+	if err != nil {
+		return fmt.Errorf("error opening era1 file %s: %w", archive.path, err)
+	}
+	defer e.Close()
+
+	want, err := e.Accumulator()
+	if err != nil {
+		return fmt.Errorf("error reading accumulator of %s: %w", archive.path, err)
+	}
+	if b.roots != nil {
+		if archive.epoch >= uint64(len(b.roots)) || b.roots[archive.epoch] != want {
+			return fmt.Errorf("unexpected accumulator %s of %s", want, archive.path)
+		}
+	}
+	td, err := e.InitialTD()
+	if err != nil {
+		return fmt.Errorf("error reading total difficulty of %s: %w", archive.path, err)
+	}
+	it, err := era.NewIterator(e)
+	if err != nil {
+		return fmt.Errorf("error making era iterator: %w", err)
+	}
+	var (
+		hashes = make([]common.Hash, 0, archive.count)
+		tds    = make([]*big.Int, 0, archive.count)
+	)
+	for it.Next() {
+		block, receipts, err := it.BlockAndReceipts()
+		if err != nil {
+			return fmt.Errorf("error reading block %d: %w", it.Number(), err)
+		}
+		if root := types.DeriveSha(block.Transactions(), trie.NewStackTrie(nil)); root != block.TxHash() {
+			return fmt.Errorf("tx root in block %d mismatch: want %s, got %s", block.NumberU64(), block.TxHash(), root)
+		}
+		if root := types.DeriveSha(receipts, trie.NewStackTrie(nil)); root != block.ReceiptHash() {
+			return fmt.Errorf("receipt root in block %d mismatch: want %s, got %s", block.NumberU64(), block.ReceiptHash(), root)
+		}
+		td.Add(td, block.Difficulty())
+		hashes = append(hashes, block.Hash())
+		tds = append(tds, new(big.Int).Set(td))
+
+		if err := fn(block, receipts, tds[len(tds)-1]); err != nil {
+			return err
+		}
+	}
+	if it.Error() != nil {
+		return fmt.Errorf("error reading %s: %w", archive.path, it.Error())
+	}
+	got, err := era.ComputeAccumulator(hashes, tds)
+	if err != nil {
+		return fmt.Errorf("error computing accumulator of %s: %w", archive.path, err)
+	}
+	if got != want {
+		return fmt.Errorf("accumulator of %s mismatch: want %s, got %s", archive.path, want, got)
+	}
+	return nil
+}
+
+// write creates the new ancientDB with the backfilled blocks followed by the
+// ones of the old ancientDB.
+func (b *BlockBackfiller) write(db ethdb.Database, archives []*eraArchive, from, offset, items uint64) error {
+	frdb, err := rawdb.NewFreezerDb(db, b.newAncientPath, "", false, from)
+	if err != nil {
+		return err
+	}
+	defer frdb.Close()
+
+	var (
+		start    = time.Now()
+		reported = time.Now()
+	)
+	for _, archive := range archives {
+		err := b.iterate(archive, func(block *types.Block, receipts types.Receipts, td *big.Int) error {
+			if number := block.NumberU64(); number < from || number >= offset {
+				return nil
+			}
+			if _, err := rawdb.WriteAncientBlocks(frdb, []*types.Block{block}, []types.Receipts{receipts}, td); err != nil {
+				return err
+			}
+			if time.Since(reported) >= 8*time.Second {
+				log.Info("Backfilling ancientDB", "number", block.NumberU64(), "elapsed", common.PrettyDuration(time.Since(start)))
+				reported = time.Now()
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	// Copy the blocks still present, along with their blobs
+	for number := offset; number < offset+items; number++ {
+		hash := rawdb.ReadCanonicalHash(db, number)
+		block := rawdb.ReadBlock(db, hash, number)
+		if block == nil {
+			return fmt.Errorf("block %d missing in the ancientDB", number)
+		}
+		receipts := rawdb.ReadRawReceipts(db, hash, number)
+		td := rawdb.ReadTd(db, hash, number)
+		if td == nil {
+			return fmt.Errorf("total difficulty of block %d missing in the ancientDB", number)
+		}
+		block = block.WithSidecars(rawdb.ReadBlobSidecars(db, hash, number))
+		if _, err := rawdb.WriteAncientBlocksWithBlobs(frdb, []*types.Block{block}, []types.Receipts{receipts}, td); err != nil {
+			return err
+		}
+		if time.Since(reported) >= 8*time.Second {
+			log.Info("Copying ancientDB", "number", number, "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+	}
+	if err := frdb.Sync(); err != nil {
+		return err
+	}
+	// Mark the new ancientDB as complete, recording its offset for the replacement
+	return os.WriteFile(filepath.Join(b.newAncientPath, backfillFlock), []byte(strconv.FormatUint(from, 10)), 0644)
+}
+
+// replace records the new offset and swaps the ancientDBs.
+func (b *BlockBackfiller) replace(name string, cache, handles int, namespace string) error {
+	blob, err := os.ReadFile(filepath.Join(b.newAncientPath, backfillFlock))
+	if err != nil {
+		return err
+	}
+	from, err := strconv.ParseUint(string(blob), 10, 64)
+	if err != nil {
+		return fmt.Errorf("malformed %s: %w", backfillFlock, err)
+	}
+	// Only the key-value store is needed, the old ancientDB may not match the new offset any more
+	db, err := b.node.OpenDatabase(name, cache, handles, namespace, false)
+	if err != nil {
+		return err
+	}
+	oldOffset := rawdb.ReadOffSetOfCurrentAncientFreezer(db)
+	if oldOffset != from {
+		batch := db.NewBatch()
+		rawdb.WriteOffSetOfCurrentAncientFreezer(batch, from)
+		rawdb.WriteOffSetOfLastAncientFreezer(batch, oldOffset)
+		if err := batch.Write(); err != nil {
+			db.Close()
+			return err
+		}
+	}
+	db.Close()
+
+	if err := os.RemoveAll(b.oldAncientPath); err != nil {
+		return err
+	}
+	if err := os.Rename(b.newAncientPath, b.oldAncientPath); err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(b.oldAncientPath, backfillFlock)); err != nil {
+		return err
+	}
+	log.Info("Block history backfilled", "offset", from, "previous", oldOffset)
+	return nil
+}
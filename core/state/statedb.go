@@ -135,6 +135,9 @@ type StateDB struct {
 	validRevisions []revision
 	nextRevisionId int
 
+	// Snapshots spanning multiple transactions, see MultiTxSnapshot.
+	multiTxSnapshots []*multiTxSnapshot
+
 	// Measurements gathered during execution for debugging purposes
 	// MetricsMux should be used in more places, but will affect on performance, so following meteration is not accruate
 	MetricsMux           sync.Mutex
@@ -1296,7 +1299,9 @@ func (s *StateDB) SetTxContext(thash common.Hash, ti int) {
 
 func (s *StateDB) clearJournalAndRefund() {
 	if len(s.journal.entries) > 0 {
+		onDirty := s.journal.onDirty
 		s.journal = newJournal()
+		s.journal.onDirty = onDirty
 		s.refund = 0
 	}
 	s.validRevisions = s.validRevisions[:0] // Snapshots can be created without journal entries
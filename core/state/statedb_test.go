@@ -1206,3 +1206,73 @@ func TestDeleteStorage(t *testing.T) {
 		t.Fatalf("difference found:\nfast: %v\nslow: %v\n", fastRes, slowRes)
 	}
 }
+
+func TestMultiTxSnapshot(t *testing.T) {
+	var (
+		state, _ = New(types.EmptyRootHash, NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		addrA    = common.HexToAddress("0xaaaa")
+		addrB    = common.HexToAddress("0xbbbb")
+		addrC    = common.HexToAddress("0xcccc")
+		key      = common.HexToHash("0x01")
+	)
+	state.SetBalance(addrA, uint256.NewInt(1))
+	state.SetState(addrA, key, common.HexToHash("0x01"))
+	state.SetBalance(addrB, uint256.NewInt(2))
+	state.Finalise(true)
+	before := state.Copy().IntermediateRoot(true)
+
+	state.MultiTxSnapshot()
+
+	// First tx: modify an existing account and create a new one with a log
+	state.SetTxContext(common.HexToHash("0x1"), 0)
+	state.AddBalance(addrA, uint256.NewInt(10))
+	state.SetState(addrA, key, common.HexToHash("0x02"))
+	state.SetBalance(addrC, uint256.NewInt(3))
+	state.SetState(addrC, key, common.HexToHash("0x03"))
+	state.AddLog(&types.Log{Address: addrC})
+	state.Finalise(true)
+	middle := state.Copy().IntermediateRoot(true)
+
+	// Second tx in a nested snapshot: destruct an account and modify the new one
+	state.MultiTxSnapshot()
+	state.SetTxContext(common.HexToHash("0x2"), 1)
+	state.SelfDestruct(addrB)
+	state.SetState(addrC, key, common.HexToHash("0x04"))
+	state.AddLog(&types.Log{Address: addrC})
+	state.Finalise(true)
+	if root := state.Copy().IntermediateRoot(true); root == middle {
+		t.Fatal("second tx didn't change the state")
+	}
+
+	state.RevertMultiTxSnapshot()
+	if root := state.Copy().IntermediateRoot(true); root != middle {
+		t.Fatalf("nested revert root mismatch: have %x, want %x", root, middle)
+	}
+	if !state.Exist(addrB) {
+		t.Fatal("destructed account not restored")
+	}
+	if logs := state.Logs(); len(logs) != 1 {
+		t.Fatalf("logs mismatch: have %d, want 1", len(logs))
+	}
+
+	// Redo the second tx, then revert both
+	state.MultiTxSnapshot()
+	state.SetTxContext(common.HexToHash("0x2"), 1)
+	state.SelfDestruct(addrB)
+	state.Finalise(true)
+	state.DiscardMultiTxSnapshot()
+
+	state.RevertMultiTxSnapshot()
+	if root := state.Copy().IntermediateRoot(true); root != before {
+		t.Fatalf("revert root mismatch: have %x, want %x", root, before)
+	}
+	if state.Exist(addrC) {
+		t.Fatal("created account not removed")
+	}
+	if logs := state.Logs(); len(logs) != 0 {
+		t.Fatalf("logs mismatch: have %d, want 0", len(logs))
+	}
+	if root := state.IntermediateRoot(true); root != before {
+		t.Fatalf("root mismatch: have %x, want %x", root, before)
+	}
+}
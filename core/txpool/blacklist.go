@@ -0,0 +1,327 @@
+package txpool
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// blacklistReloadInterval is the time interval to check the blacklist file for changes.
+const blacklistReloadInterval = 5 * time.Second
+
+// errBuiltinBlacklist is returned when removing an address of types.NanoBlackList.
+var errBuiltinBlacklist = errors.New("builtin blacklist address can't be removed")
+
+// Blacklist is a set of banned addresses, transactions sent from or to them are
+// rejected by the pool and optionally left out of the locally built blocks.
+//
+// It's a local policy which never affects the validation of blocks, the addresses
+// in types.NanoBlackList are always included. If a file is configured, it is kept
+// in sync with the changes made through the API and reloaded once modified.
+type Blacklist struct {
+	file    string
+	listed  map[common.Address]struct{} // Configured addresses, without the builtin ones
+	modTime time.Time                   // Modification time of the file when last loaded
+	lock    sync.Mutex                  // Protects the fields above and the file
+
+	addrs    atomic.Pointer[map[common.Address]struct{}] // Full set for the lock free lookups
+	onUpdate atomic.Pointer[func()]                      // Callback run once the set changed, outside the lock
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewBlacklist creates a blacklist loaded from the given file, which is watched
+// for changes. The file is created on the first update if it doesn't exist yet.
+func NewBlacklist(file string) (*Blacklist, error) {
+	b := &Blacklist{
+		file:   file,
+		listed: make(map[common.Address]struct{}),
+		quit:   make(chan struct{}),
+	}
+	if file == "" {
+		b.publish()
+		return b, nil
+	}
+	if err := b.Reload(); err != nil {
+		return nil, err
+	}
+	b.wg.Add(1)
+	go b.loop()
+	return b, nil
+}
+
+// Close stops watching the blacklist file.
+func (b *Blacklist) Close() {
+	if b == nil {
+		return
+	}
+	close(b.quit)
+	b.wg.Wait()
+}
+
+// loop reloads the blacklist file once it's modified.
+func (b *Blacklist) loop() {
+	defer b.wg.Done()
+
+	ticker := time.NewTicker(blacklistReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			info, err := os.Stat(b.file)
+			if err != nil {
+				continue
+			}
+			b.lock.Lock()
+			modified := !info.ModTime().Equal(b.modTime)
+			b.lock.Unlock()
+
+			if modified {
+				if err := b.Reload(); err != nil {
+					log.Error("Failed to reload blacklist", "file", b.file, "err", err)
+				}
+			}
+		case <-b.quit:
+			return
+		}
+	}
+}
+
+// Reload replaces the configured addresses with the content of the file.
+func (b *Blacklist) Reload() error {
+	if b.file == "" {
+		return errors.New("no blacklist file configured")
+	}
+	if err := b.reload(); err != nil {
+		return err
+	}
+	b.updated()
+	return nil
+}
+
+func (b *Blacklist) reload() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	info, err := os.Stat(b.file)
+	if errors.Is(err, os.ErrNotExist) {
+		b.listed = make(map[common.Address]struct{})
+		b.modTime = time.Time{}
+		b.publish()
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	listed, err := loadBlacklist(b.file)
+	if err != nil {
+		return err
+	}
+	b.listed, b.modTime = listed, info.ModTime()
+	b.publish()
+
+	log.Info("Loaded transaction blacklist", "file", b.file, "addresses", len(listed))
+	return nil
+}
+
+// Add inserts the addresses into the blacklist.
+func (b *Blacklist) Add(addrs []common.Address) error {
+	b.lock.Lock()
+	listed := make(map[common.Address]struct{}, len(b.listed)+len(addrs))
+	for addr := range b.listed {
+		listed[addr] = struct{}{}
+	}
+	for _, addr := range addrs {
+		listed[addr] = struct{}{}
+	}
+	err := b.update(listed)
+	b.lock.Unlock()
+
+	if err != nil {
+		return err
+	}
+	b.updated()
+	return nil
+}
+
+// Remove deletes the addresses from the blacklist.
+func (b *Blacklist) Remove(addrs []common.Address) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	// Nothing pooled can start matching, so the update callback isn't run
+	listed := make(map[common.Address]struct{}, len(b.listed))
+	for addr := range b.listed {
+		listed[addr] = struct{}{}
+	}
+	for _, addr := range addrs {
+		for _, builtin := range types.NanoBlackList {
+			if addr == builtin {
+				return fmt.Errorf("%w: %v", errBuiltinBlacklist, addr)
+			}
+		}
+		delete(listed, addr)
+	}
+	return b.update(listed)
+}
+
+// update persists and publishes the new configured addresses, the lock is
+// assumed to be held.
+func (b *Blacklist) update(listed map[common.Address]struct{}) error {
+	if b.file != "" {
+		if err := storeBlacklist(b.file, listed); err != nil {
+			return err
+		}
+		if info, err := os.Stat(b.file); err == nil {
+			b.modTime = info.ModTime()
+		}
+	}
+	b.listed = listed
+	b.publish()
+	return nil
+}
+
+// publish rebuilds the lookup set from the configured and builtin addresses, the
+// lock is assumed to be held.
+func (b *Blacklist) publish() {
+	addrs := make(map[common.Address]struct{}, len(b.listed)+len(types.NanoBlackList))
+	for addr := range b.listed {
+		addrs[addr] = struct{}{}
+	}
+	for _, addr := range types.NanoBlackList {
+		addrs[addr] = struct{}{}
+	}
+	b.addrs.Store(&addrs)
+}
+
+// setUpdateHook sets the callback run after the blacklist was reloaded or extended.
+// It's invoked without holding the blacklist lock.
+func (b *Blacklist) setUpdateHook(hook func()) {
+	b.onUpdate.Store(&hook)
+}
+
+// updated runs the update callback, if any.
+func (b *Blacklist) updated() {
+	if hook := b.onUpdate.Load(); hook != nil {
+		(*hook)()
+	}
+}
+
+// Contains reports whether the address is blacklisted.
+func (b *Blacklist) Contains(addr common.Address) bool {
+	if b == nil {
+		return false
+	}
+	_, ok := (*b.addrs.Load())[addr]
+	return ok
+}
+
+// Check returns ErrInBlackList if either the sender or the recipient of a
+// transaction is blacklisted.
+func (b *Blacklist) Check(from common.Address, to *common.Address) error {
+	if b.Contains(from) {
+		return ErrInBlackList
+	}
+	if to != nil && b.Contains(*to) {
+		return ErrInBlackList
+	}
+	return nil
+}
+
+// checkTx is the equivalent of Check for a transaction, recovering its sender.
+func (b *Blacklist) checkTx(tx *types.Transaction) error {
+	if b == nil {
+		return nil
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil // Leave the invalid signatures to the subpools
+	}
+	return b.Check(from, tx.To())
+}
+
+// List returns the blacklisted addresses in ascending order.
+func (b *Blacklist) List() []common.Address {
+	if b == nil {
+		return nil
+	}
+	addrs := *b.addrs.Load()
+	list := make([]common.Address, 0, len(addrs))
+	for addr := range addrs {
+		list = append(list, addr)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return bytes.Compare(list[i][:], list[j][:]) < 0
+	})
+	return list
+}
+
+// loadBlacklist parses a blacklist file, which contains an address per line.
+// Empty lines and the ones starting with # are ignored.
+func loadBlacklist(file string) (map[common.Address]struct{}, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		listed  = make(map[common.Address]struct{})
+		scanner = bufio.NewScanner(f)
+	)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if !common.IsHexAddress(text) {
+			return nil, fmt.Errorf("invalid address %q in %s, line %d", text, file, line)
+		}
+		listed[common.HexToAddress(text)] = struct{}{}
+	}
+	return listed, scanner.Err()
+}
+
+// storeBlacklist atomically writes the addresses into the blacklist file.
+func storeBlacklist(file string, listed map[common.Address]struct{}) error {
+	addrs := make([]string, 0, len(listed))
+	for addr := range listed {
+		addrs = append(addrs, addr.Hex())
+	}
+	sort.Strings(addrs)
+
+	tmp, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString("# Transactions sent from or to the addresses below are rejected\n"); err != nil {
+		tmp.Close()
+		return err
+	}
+	for _, addr := range addrs {
+		if _, err := tmp.WriteString(addr + "\n"); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
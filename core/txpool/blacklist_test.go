@@ -0,0 +1,73 @@
+package txpool
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestBlacklist(t *testing.T) {
+	var (
+		file  = filepath.Join(t.TempDir(), "blacklist.txt")
+		addr1 = common.HexToAddress("0x1111111111111111111111111111111111111111")
+		addr2 = common.HexToAddress("0x2222222222222222222222222222222222222222")
+		addr3 = common.HexToAddress("0x3333333333333333333333333333333333333333")
+	)
+	content := "# banned\n\n" + addr1.Hex() + "\n  " + addr2.Hex() + "  \n"
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	blacklist, err := NewBlacklist(file)
+	if err != nil {
+		t.Fatalf("failed to create blacklist: %v", err)
+	}
+	defer blacklist.Close()
+
+	if !blacklist.Contains(addr1) || !blacklist.Contains(addr2) || blacklist.Contains(addr3) {
+		t.Fatal("loaded blacklist mismatch")
+	}
+	if !blacklist.Contains(types.NanoBlackList[0]) {
+		t.Fatal("builtin address missing")
+	}
+	if err := blacklist.Check(addr3, &addr1); err != ErrInBlackList {
+		t.Fatalf("recipient check mismatch: have %v, want %v", err, ErrInBlackList)
+	}
+	if err := blacklist.Check(addr3, nil); err != nil {
+		t.Fatalf("unexpected check error: %v", err)
+	}
+
+	// Updates should be persisted and survive a reload
+	if err := blacklist.Add([]common.Address{addr3}); err != nil {
+		t.Fatalf("failed to add address: %v", err)
+	}
+	if err := blacklist.Remove([]common.Address{addr1}); err != nil {
+		t.Fatalf("failed to remove address: %v", err)
+	}
+	if err := blacklist.Remove([]common.Address{types.NanoBlackList[0]}); !errors.Is(err, errBuiltinBlacklist) {
+		t.Fatalf("builtin removal error mismatch: have %v, want %v", err, errBuiltinBlacklist)
+	}
+	if err := blacklist.Reload(); err != nil {
+		t.Fatalf("failed to reload blacklist: %v", err)
+	}
+	if blacklist.Contains(addr1) || !blacklist.Contains(addr2) || !blacklist.Contains(addr3) {
+		t.Fatal("reloaded blacklist mismatch")
+	}
+	if have, want := len(blacklist.List()), 2+len(types.NanoBlackList); have != want {
+		t.Fatalf("blacklist size mismatch: have %d, want %d", have, want)
+	}
+
+	// Invalid files should be rejected without touching the current set
+	if err := os.WriteFile(file, []byte("0xinvalid\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := blacklist.Reload(); err == nil {
+		t.Fatal("invalid blacklist file accepted")
+	}
+	if !blacklist.Contains(addr3) {
+		t.Fatal("blacklist changed by invalid file")
+	}
+}
@@ -93,6 +93,8 @@ type blobTxMeta struct {
 	id   uint64      // Storage ID in the pool's persistent store
 	size uint32      // Byte size in the pool's persistent store
 
+	to common.Address // Needed to enforce the blacklist without reading the blob
+
 	nonce      uint64       // Needed to prioritize inclusion order within an account
 	costCap    *uint256.Int // Needed to validate cumulative balance sufficiency
 	execTipCap *uint256.Int // Needed to prioritize inclusion order across accounts and validate replacement price bump
@@ -116,6 +118,7 @@ func newBlobTxMeta(id uint64, size uint32, tx *types.Transaction) *blobTxMeta {
 		hash:       tx.Hash(),
 		id:         id,
 		size:       size,
+		to:         *tx.To(),
 		nonce:      tx.Nonce(),
 		costCap:    uint256.MustFromBig(tx.Cost()),
 		execTipCap: uint256.MustFromBig(tx.GasTipCap()),
@@ -316,8 +319,11 @@ type BlobPool struct {
 	discoverFeed event.Feed // Event feed to send out new tx events on pool discovery (reorg excluded)
 	insertFeed   event.Feed // Event feed to send out new tx events on pool inclusion (reorg included)
 	reannoTxFeed event.Feed // Event feed for announcing transactions again
+	eventFeed    event.Feed // Event feed of the transactions leaving the pool
 	scope        event.SubscriptionScope
 
+	events []core.TxPoolEvent // Transactions left the pool since the last feed
+
 	lock sync.RWMutex // Mutex protecting the pool during reorg handling
 }
 
@@ -431,6 +437,9 @@ func (p *BlobPool) Init(gasTip uint64, head *types.Header, reserve txpool.Addres
 	for p.stored > p.config.Datacap {
 		p.drop()
 	}
+	// Nobody could subscribe yet, discard the events of the startup cleanup
+	p.takeEvents()
+
 	// Update the metrics and return the constructed pool
 	datacapGauge.Update(int64(p.config.Datacap))
 	p.updateStorageMetrics()
@@ -537,6 +546,11 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 			p.stored -= uint64(txs[i].size)
 			delete(p.lookup, txs[i].hash)
 
+			if filled {
+				p.dropped(txs[i].hash, staleReason(txs[i].hash, inclusions), nil)
+			} else {
+				p.dropped(txs[i].hash, core.TxEvicted, nil)
+			}
 			// Included transactions blobs need to be moved to the limbo
 			if filled && inclusions != nil {
 				p.offload(addr, txs[i].nonce, txs[i].id, inclusions)
@@ -577,6 +591,7 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 			p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], txs[0].costCap)
 			p.stored -= uint64(txs[0].size)
 			delete(p.lookup, txs[0].hash)
+			p.dropped(txs[0].hash, staleReason(txs[0].hash, inclusions), nil)
 
 			// Included transactions blobs need to be moved to the limbo
 			if inclusions != nil {
@@ -633,6 +648,7 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 			p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], txs[i].costCap)
 			p.stored -= uint64(txs[i].size)
 			delete(p.lookup, txs[i].hash)
+			p.dropped(txs[i].hash, core.TxEvicted, nil)
 
 			if err := p.store.Delete(id); err != nil {
 				log.Error("Failed to delete blob transaction", "from", addr, "id", id, "err", err)
@@ -655,6 +671,7 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 			p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], txs[j].costCap)
 			p.stored -= uint64(txs[j].size)
 			delete(p.lookup, txs[j].hash)
+			p.dropped(txs[j].hash, core.TxEvicted, nil)
 		}
 		txs = txs[:i]
 
@@ -693,6 +710,7 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 			p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], last.costCap)
 			p.stored -= uint64(last.size)
 			delete(p.lookup, last.hash)
+			p.dropped(last.hash, core.TxInsufficientFunds, nil)
 		}
 		if len(txs) == 0 {
 			delete(p.index, addr)
@@ -733,6 +751,7 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 			p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], last.costCap)
 			p.stored -= uint64(last.size)
 			delete(p.lookup, last.hash)
+			p.dropped(last.hash, core.TxEvicted, nil)
 		}
 		p.index[addr] = txs
 
@@ -820,6 +839,9 @@ func (p *BlobPool) Reset(oldHead, newHead *types.Header) {
 			p.insertFeed.Send(core.NewTxsEvent{Txs: adds})
 		}
 	}
+	if events := p.takeEvents(); len(events) > 0 {
+		p.eventFeed.Send(events)
+	}
 	// Flush out any blobs from limbo that are older than the latest finality
 	if p.chain.Config().IsCancun(p.head.Number, p.head.Time) {
 		p.limbo.finalize(p.chain.CurrentFinalBlock())
@@ -1038,6 +1060,7 @@ func (p *BlobPool) SetGasTip(tip *big.Int) {
 					p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], txs[i].costCap)
 					p.stored -= uint64(tx.size)
 					delete(p.lookup, tx.hash)
+					p.dropped(tx.hash, core.TxUnderpriced, nil)
 					txs[i] = nil
 
 					// Drop everything afterwards, no gaps allowed
@@ -1048,6 +1071,7 @@ func (p *BlobPool) SetGasTip(tip *big.Int) {
 						p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], tx.costCap)
 						p.stored -= uint64(tx.size)
 						delete(p.lookup, tx.hash)
+						p.dropped(tx.hash, core.TxUnderpriced, nil)
 						txs[i+1+j] = nil
 					}
 					// Clear out the dropped transactions from the index
@@ -1075,24 +1099,74 @@ func (p *BlobPool) SetGasTip(tip *big.Int) {
 			}
 		}
 	}
+	if events := p.takeEvents(); len(events) > 0 {
+		p.eventFeed.Send(events)
+	}
 	log.Debug("Blobpool tip threshold updated", "tip", tip)
 	pooltipGauge.Update(tip.Int64())
 	p.updateStorageMetrics()
 }
 
+// Purge implements txpool.Purger, dropping the transactions matched by their
+// sender and recipient. No nonce gaps are allowed in the blob pool, so all the
+// subsequent transactions of their senders are evicted too.
+func (p *BlobPool) Purge(match func(from common.Address, to *common.Address) bool, reason core.TxPoolEventReason) int {
+	p.lock.Lock()
+
+	var dropped int
+	for addr, txs := range p.index {
+		for i, tx := range txs {
+			if !match(addr, &tx.to) {
+				continue
+			}
+			ids := make([]uint64, 0, len(txs)-i)
+			for j, drop := range txs[i:] {
+				ids = append(ids, drop.id)
+
+				p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], drop.costCap)
+				p.stored -= uint64(drop.size)
+				delete(p.lookup, drop.hash)
+				if j == 0 || match(addr, &drop.to) {
+					p.dropped(drop.hash, reason, nil)
+				} else {
+					p.dropped(drop.hash, core.TxEvicted, nil)
+				}
+				txs[i+j] = nil
+			}
+			// Clear out the dropped transactions from the index
+			if i > 0 {
+				p.index[addr] = txs[:i]
+				heap.Fix(p.evict, p.evict.index[addr])
+			} else {
+				delete(p.index, addr)
+				delete(p.spent, addr)
+
+				heap.Remove(p.evict, p.evict.index[addr])
+				p.reserve(addr, false)
+			}
+			log.Debug("Dropping purged blob transactions", "from", addr, "nonce", tx.nonce, "ids", ids, "reason", reason)
+			for _, id := range ids {
+				if err := p.store.Delete(id); err != nil {
+					log.Error("Failed to delete dropped transaction", "id", id, "err", err)
+				}
+			}
+			dropped += len(ids)
+			break
+		}
+	}
+	p.updateStorageMetrics()
+	events := p.takeEvents()
+	p.lock.Unlock()
+
+	if len(events) > 0 {
+		p.eventFeed.Send(events)
+	}
+	return dropped
+}
+
 // validateTx checks whether a transaction is valid according to the consensus
 // rules and adheres to some heuristic limits of the local node (price and size).
 func (p *BlobPool) validateTx(tx *types.Transaction) error {
-	sender, err := types.Sender(p.signer, tx)
-	if err != nil {
-		return err
-	}
-	for _, blackAddr := range types.NanoBlackList {
-		if sender == blackAddr || (tx.To() != nil && *tx.To() == blackAddr) {
-			log.Error("blacklist account detected", "account", blackAddr, "tx", tx.Hash())
-			return txpool.ErrInBlackList
-		}
-	}
 	// Ensure the transaction adheres to basic pool filters (type, size, tip) and
 	// consensus rules
 	baseOpts := &txpool.ValidationOptions{
@@ -1238,6 +1312,13 @@ func (p *BlobPool) Add(txs []*types.Transaction, local bool, sync bool) []error
 		p.discoverFeed.Send(core.NewTxsEvent{Txs: adds})
 		p.insertFeed.Send(core.NewTxsEvent{Txs: adds})
 	}
+	p.lock.Lock()
+	events := p.takeEvents()
+	p.lock.Unlock()
+
+	if len(events) > 0 {
+		p.eventFeed.Send(events)
+	}
 	return errs
 }
 
@@ -1325,6 +1406,7 @@ func (p *BlobPool) add(tx *types.Transaction) (err error) {
 		dropReplacedMeter.Mark(1)
 
 		prev := p.index[from][offset]
+		p.dropped(prev.hash, core.TxReplaced, &meta.hash)
 		if err := p.store.Delete(prev.id); err != nil {
 			// Shitty situation, but try to recover gracefully instead of going boom
 			log.Error("Failed to delete replaced transaction", "id", prev.id, "err", err)
@@ -1435,6 +1517,7 @@ func (p *BlobPool) drop() {
 	}
 	p.stored -= uint64(drop.size)
 	delete(p.lookup, drop.hash)
+	p.dropped(drop.hash, core.TxEvicted, nil)
 
 	// Remove the transaction from the pool's eviction heap:
 	//   - If the entire account was dropped, pop off the address
@@ -1616,6 +1699,35 @@ func (pool *BlobPool) SubscribeReannoTxsEvent(ch chan<- core.ReannoTxsEvent) eve
 	return pool.scope.Track(pool.reannoTxFeed.Subscribe(ch))
 }
 
+// SubscribeTxPoolEvents registers a subscription for the events of transactions
+// leaving the pool, along with the reason.
+func (p *BlobPool) SubscribeTxPoolEvents(ch chan<- []core.TxPoolEvent) event.Subscription {
+	return p.scope.Track(p.eventFeed.Subscribe(ch))
+}
+
+// dropped records the event of a transaction leaving the pool. The caller must
+// hold the pool lock.
+func (p *BlobPool) dropped(hash common.Hash, reason core.TxPoolEventReason, replacement *common.Hash) {
+	p.events = append(p.events, core.TxPoolEvent{Hash: hash, Reason: reason, ReplacedBy: replacement})
+}
+
+// takeEvents returns the events recorded since the last call. The caller must
+// hold the pool lock.
+func (p *BlobPool) takeEvents() []core.TxPoolEvent {
+	events := p.events
+	p.events = nil
+	return events
+}
+
+// staleReason returns the reason of a transaction left behind by the nonce of its
+// sender, depending on whether the chain included it or another one.
+func staleReason(hash common.Hash, inclusions map[common.Hash]uint64) core.TxPoolEventReason {
+	if _, ok := inclusions[hash]; ok {
+		return core.TxMined
+	}
+	return core.TxNonceTooLow
+}
+
 // Nonce returns the next nonce of an account, with all transactions executable
 // by the pool already applied on top.
 func (p *BlobPool) Nonce(addr common.Address) uint64 {
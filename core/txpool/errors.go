@@ -63,4 +63,8 @@ var (
 
 	// ErrInBlackList is returned if the transaction send by banned address
 	ErrInBlackList = errors.New("sender or to in black list")
+
+	// ErrPrivateTxUnsupported is returned if a transaction is submitted privately
+	// to a subpool unable to expire it, such as the blob one.
+	ErrPrivateTxUnsupported = errors.New("private transaction type not supported")
 )
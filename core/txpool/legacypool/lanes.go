@@ -0,0 +1,217 @@
+package legacypool
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	// errLaneName is returned if a priority lane is configured without a name.
+	errLaneName = errors.New("priority lane without name")
+
+	// errLaneUnknown is returned when removing a priority lane not configured.
+	errLaneUnknown = errors.New("unknown priority lane")
+)
+
+//go:generate go run github.com/fjl/gencodec -type Lane -field-override laneMarshaling -out gen_lane_json.go
+
+// Lane is a set of senders whose transactions get a number of pool slots reserved
+// and are evicted after the ones of the regular remote senders.
+//
+// Up to Slots of the lane transactions paying at least MinTip are admitted even
+// if the pool is full, evicting the cheapest remote transactions like the local
+// ones do, and survive the pool overflows until leaving the pool or the lane
+// being removed. The lane transactions beyond are pooled as regular remote ones.
+type Lane struct {
+	Name      string           `json:"name"`
+	Addresses []common.Address `json:"addresses"`
+	Slots     uint64           `json:"slots"`  // Number of pool slots reserved for the lane transactions
+	MinTip    uint64           `json:"minTip"` // Minimum gas tip (in wei) of a transaction to use the reserved slots
+}
+
+// field type overrides for gencodec
+type laneMarshaling struct {
+	Slots  hexutil.Uint64
+	MinTip hexutil.Uint64
+}
+
+// LaneStatus is the configuration and usage of a priority lane.
+type LaneStatus struct {
+	Name      string           `json:"name"`
+	Addresses []common.Address `json:"addresses"`
+	Slots     hexutil.Uint64   `json:"slots"`
+	MinTip    hexutil.Uint64   `json:"minTip"`
+	Reserved  hexutil.Uint64   `json:"reserved"` // Number of reserved slots taken
+	Pending   hexutil.Uint     `json:"pending"`  // Number of executable transactions sent by the lane senders
+	Queued    hexutil.Uint     `json:"queued"`   // Number of non-executable transactions sent by the lane senders
+}
+
+// lane is a priority lane along with the transactions taking its reserved slots.
+type lane struct {
+	Lane
+	minTip   *big.Int
+	reserved map[common.Hash]laneReservation // Transactions using the reservation
+}
+
+// laneReservation is the part of the reserved slots of a lane taken by a transaction.
+type laneReservation struct {
+	from  common.Address
+	slots int
+}
+
+// laneSet is the set of priority lanes configured in the pool.
+//
+// Note, the set is not thread safe, it's protected by the pool lock.
+type laneSet struct {
+	lanes   map[string]*lane
+	senders map[common.Address]*lane
+}
+
+func newLaneSet() *laneSet {
+	return &laneSet{
+		lanes:   make(map[string]*lane),
+		senders: make(map[common.Address]*lane),
+	}
+}
+
+// set adds a lane or replaces the one with the same name, keeping the slots
+// reserved by the transactions of the senders still in the lane.
+func (s *laneSet) set(l Lane) error {
+	if l.Name == "" {
+		return errLaneName
+	}
+	for _, addr := range l.Addresses {
+		if owner, ok := s.senders[addr]; ok && owner.Name != l.Name {
+			return fmt.Errorf("address %v already in priority lane %q", addr, owner.Name)
+		}
+	}
+	l.Addresses = append([]common.Address(nil), l.Addresses...)
+	entry := &lane{
+		Lane:     l,
+		minTip:   new(big.Int).SetUint64(l.MinTip),
+		reserved: make(map[common.Hash]laneReservation),
+	}
+	old := s.lanes[l.Name]
+	if old != nil {
+		s.remove(l.Name)
+	}
+	for _, addr := range l.Addresses {
+		s.senders[addr] = entry
+	}
+	// Release the slots of the transactions whose sender left the lane
+	if old != nil {
+		for hash, r := range old.reserved {
+			if s.senders[r.from] == entry {
+				entry.reserved[hash] = r
+			}
+		}
+	}
+	s.lanes[l.Name] = entry
+	return nil
+}
+
+// remove deletes a lane, releasing the slots reserved by its transactions which
+// become regular remote ones.
+func (s *laneSet) remove(name string) error {
+	l, ok := s.lanes[name]
+	if !ok {
+		return errLaneUnknown
+	}
+	delete(s.lanes, name)
+	for _, addr := range l.Addresses {
+		delete(s.senders, addr)
+	}
+	return nil
+}
+
+// contains returns whether the address is the sender of a lane.
+func (s *laneSet) contains(addr common.Address) bool {
+	_, ok := s.senders[addr]
+	return ok
+}
+
+// reserves returns whether the transaction takes the reserved slots of a lane.
+func (s *laneSet) reserves(hash common.Hash) bool {
+	for _, l := range s.lanes {
+		if _, ok := l.reserved[hash]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// reservedPrefix returns the number of transactions of the sender, sorted by nonce,
+// up to the last one taking the reserved slots of its lane. They are exempt from the
+// pending truncation, which can't drop the ones before without gapping the account.
+func (s *laneSet) reservedPrefix(addr common.Address, txs types.Transactions) int {
+	l, ok := s.senders[addr]
+	if !ok {
+		return 0
+	}
+	var prefix int
+	for i, tx := range txs {
+		if _, ok := l.reserved[tx.Hash()]; ok {
+			prefix = i + 1
+		}
+	}
+	return prefix
+}
+
+// reservation returns the lane of the transaction if it may take its reserved
+// slots, or nil otherwise. The slots taken by the transactions no longer pooled
+// are released beforehand.
+func (s *laneSet) reservation(from common.Address, tx *types.Transaction, all *lookup) *lane {
+	l, ok := s.senders[from]
+	if !ok || tx.GasTipCapIntCmp(l.minTip) < 0 {
+		return nil
+	}
+	if l.used(all)+uint64(numSlots(tx)) > l.Slots {
+		return nil
+	}
+	return l
+}
+
+// used returns the number of reserved slots taken, releasing the ones of the
+// transactions no longer pooled.
+func (l *lane) used(all *lookup) uint64 {
+	var used uint64
+	for hash, r := range l.reserved {
+		if all.Get(hash) == nil {
+			delete(l.reserved, hash)
+			continue
+		}
+		used += uint64(r.slots)
+	}
+	return used
+}
+
+// status returns the configuration and usage of the lanes, sorted by name.
+func (s *laneSet) status(pool *LegacyPool) []LaneStatus {
+	status := make([]LaneStatus, 0, len(s.lanes))
+	for _, l := range s.lanes {
+		st := LaneStatus{
+			Name:      l.Name,
+			Addresses: append([]common.Address(nil), l.Addresses...),
+			Slots:     hexutil.Uint64(l.Slots),
+			MinTip:    hexutil.Uint64(l.MinTip),
+			Reserved:  hexutil.Uint64(l.used(pool.all)),
+		}
+		for _, addr := range l.Addresses {
+			if list := pool.pending[addr]; list != nil {
+				st.Pending += hexutil.Uint(list.Len())
+			}
+			if list := pool.queue[addr]; list != nil {
+				st.Queued += hexutil.Uint(list.Len())
+			}
+		}
+		status = append(status, st)
+	}
+	sort.Slice(status, func(i, j int) bool { return status[i].Name < status[j].Name })
+	return status
+}
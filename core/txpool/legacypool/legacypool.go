@@ -140,6 +140,11 @@ type Config struct {
 
 	Lifetime       time.Duration // Maximum amount of time non-executable transaction are queued
 	ReannounceTime time.Duration // Duration for announcing local pending transactions again
+
+	Blacklist string // File of addresses whose transactions are rejected by all the subpools
+	Snapshot  string // File the pending and queued transactions of all the subpools are dumped to on shutdown
+
+	Lanes []Lane `toml:",omitempty"` // Priority lanes of senders with reserved slots
 }
 
 // DefaultConfig contains the default configurations for the transaction pool.
@@ -216,6 +221,7 @@ type LegacyPool struct {
 	gasTip       atomic.Pointer[uint256.Int]
 	txFeed       event.Feed
 	reannoTxFeed event.Feed // Event feed for announcing transactions again
+	eventFeed    event.Feed // Event feed of the transactions leaving the pool
 	scope        event.SubscriptionScope
 	signer       types.Signer
 	mu           sync.RWMutex
@@ -225,8 +231,10 @@ type LegacyPool struct {
 	currentState  *state.StateDB               // Current state in the blockchain head
 	pendingNonces *noncer                      // Pending state tracking virtual nonces
 
-	locals  *accountSet // Set of local transaction to exempt from eviction rules
-	journal *journal    // Journal of local transaction to back up to disk
+	locals    *accountSet       // Set of local transaction to exempt from eviction rules
+	lanes     *laneSet          // Priority lanes of senders with reserved slots
+	journal   *journal          // Journal of local transaction to back up to disk
+	blacklist *txpool.Blacklist // Blacklist filtering the journaled transactions, set before Init
 
 	reserve txpool.AddressReserver       // Address reserver to ensure exclusivity across subpools
 	pending map[common.Address]*list     // All currently processable transactions
@@ -244,6 +252,9 @@ type LegacyPool struct {
 	initDoneCh      chan struct{}  // is closed once the pool is initialized (for tests)
 
 	changesSinceReorg int // A counter for how many drops we've performed in-between reorg.
+
+	events []core.TxPoolEvent       // Transactions left the pool, sent once the lock is released
+	mined  map[common.Hash]struct{} // Transactions included by the chain since the last reset
 }
 
 type txpoolResetRequest struct {
@@ -278,7 +289,16 @@ func New(config Config, chain BlockChain) *LegacyPool {
 		log.Info("Setting new local account", "address", addr)
 		pool.locals.add(addr)
 	}
+	pool.lanes = newLaneSet()
+	for _, lane := range config.Lanes {
+		if err := pool.lanes.set(lane); err != nil {
+			log.Warn("Ignoring invalid txpool priority lane", "lane", lane.Name, "err", err)
+			continue
+		}
+		log.Info("Setting txpool priority lane", "lane", lane.Name, "senders", len(lane.Addresses), "slots", lane.Slots, "mintip", lane.MinTip)
+	}
 	pool.priced = newPricedList(pool.all)
+	pool.priced.exempt = pool.lanes.reserves
 
 	if !config.NoLocals && config.Journal != "" {
 		pool.journal = newTxJournal(config.Journal)
@@ -329,7 +349,7 @@ func (pool *LegacyPool) Init(gasTip uint64, head *types.Header, reserve txpool.A
 
 	// If local transactions and journaling is enabled, load from disk
 	if pool.journal != nil {
-		if err := pool.journal.load(pool.addLocals); err != nil {
+		if err := pool.journal.load(pool.addJournaled); err != nil {
 			log.Warn("Failed to load transaction journal", "err", err)
 		}
 		if err := pool.journal.rotate(pool.local()); err != nil {
@@ -394,11 +414,14 @@ func (pool *LegacyPool) loop() {
 					list := pool.queue[addr].Flatten()
 					for _, tx := range list {
 						pool.removeTx(tx.Hash(), true, true)
+						pool.dropped(tx, core.TxEvicted, nil)
 					}
 					queuedEvictionMeter.Mark(int64(len(list)))
 				}
 			}
+			events := pool.takeEvents()
 			pool.mu.Unlock()
+			pool.sendEvents(events)
 
 		case <-reannounce.C:
 			pool.mu.RLock()
@@ -476,12 +499,58 @@ func (pool *LegacyPool) SubscribeReannoTxsEvent(ch chan<- core.ReannoTxsEvent) e
 	return pool.scope.Track(pool.reannoTxFeed.Subscribe(ch))
 }
 
+// SubscribeTxPoolEvents registers a subscription for the events of transactions
+// leaving the pool, along with the reason.
+func (pool *LegacyPool) SubscribeTxPoolEvents(ch chan<- []core.TxPoolEvent) event.Subscription {
+	return pool.scope.Track(pool.eventFeed.Subscribe(ch))
+}
+
+// dropped records the event of a transaction leaving the pool, sent once the pool
+// lock is released.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *LegacyPool) dropped(tx *types.Transaction, reason core.TxPoolEventReason, replacement *types.Transaction) {
+	ev := core.TxPoolEvent{Hash: tx.Hash(), Reason: reason}
+	if replacement != nil {
+		hash := replacement.Hash()
+		ev.ReplacedBy = &hash
+	}
+	pool.events = append(pool.events, ev)
+}
+
+// takeEvents returns the events recorded since the last call.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *LegacyPool) takeEvents() []core.TxPoolEvent {
+	events := pool.events
+	pool.events = nil
+	return events
+}
+
+// sendEvents feeds the events taken from the pool to the subscribers. It must
+// be called without holding the pool lock, not to stall the pool on a slow
+// subscriber.
+func (pool *LegacyPool) sendEvents(events []core.TxPoolEvent) {
+	if len(events) > 0 {
+		pool.eventFeed.Send(events)
+	}
+}
+
+// staleReason returns the reason of a transaction left behind by the nonce of its
+// sender, depending on whether the chain included it or another one.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *LegacyPool) staleReason(tx *types.Transaction) core.TxPoolEventReason {
+	if _, ok := pool.mined[tx.Hash()]; ok {
+		return core.TxMined
+	}
+	return core.TxNonceTooLow
+}
+
 // SetGasTip updates the minimum gas tip required by the transaction pool for a
 // new transaction, and drops all transactions below this threshold.
 func (pool *LegacyPool) SetGasTip(tip *big.Int) {
 	pool.mu.Lock()
-	defer pool.mu.Unlock()
-
 	var (
 		newTip = uint256.MustFromBig(tip)
 		old    = pool.gasTip.Load()
@@ -493,9 +562,14 @@ func (pool *LegacyPool) SetGasTip(tip *big.Int) {
 		drop := pool.all.RemotesBelowTip(tip)
 		for _, tx := range drop {
 			pool.removeTx(tx.Hash(), false, true)
+			pool.dropped(tx, core.TxUnderpriced, nil)
 		}
 		pool.priced.Removed(len(drop))
 	}
+	events := pool.takeEvents()
+	pool.mu.Unlock()
+
+	pool.sendEvents(events)
 	log.Info("Legacy pool tip threshold updated", "tip", newTip)
 }
 
@@ -652,17 +726,6 @@ func (pool *LegacyPool) local() map[common.Address]types.Transactions {
 // This check is meant as an early check which only needs to be performed once,
 // and does not require the pool mutex to be held.
 func (pool *LegacyPool) validateTxBasics(tx *types.Transaction, local bool) error {
-	sender, err := types.Sender(pool.signer, tx)
-	if err != nil {
-		return err
-	}
-	for _, blackAddr := range types.NanoBlackList {
-		if sender == blackAddr || (tx.To() != nil && *tx.To() == blackAddr) {
-			log.Error("blacklist account detected", "account", blackAddr, "tx", tx.Hash())
-			return txpool.ErrInBlackList
-		}
-	}
-
 	opts := &txpool.ValidationOptions{
 		Config: pool.chainconfig,
 		Accept: 0 |
@@ -685,17 +748,6 @@ func (pool *LegacyPool) validateTxBasics(tx *types.Transaction, local bool) erro
 // validateTx checks whether a transaction is valid according to the consensus
 // rules and adheres to some heuristic limits of the local node (price and size).
 func (pool *LegacyPool) validateTx(tx *types.Transaction, local bool) error {
-	sender, err := types.Sender(pool.signer, tx)
-	if err != nil {
-		return err
-	}
-	for _, blackAddr := range types.NanoBlackList {
-		if sender == blackAddr || (tx.To() != nil && *tx.To() == blackAddr) {
-			log.Error("blacklist account detected", "account", blackAddr, "tx", tx.Hash())
-			return txpool.ErrInBlackList
-		}
-	}
-
 	opts := &txpool.ValidationOptionsWithState{
 		State: pool.currentState,
 
@@ -781,10 +833,23 @@ func (pool *LegacyPool) add(tx *types.Transaction, local bool) (replaced bool, e
 			}
 		}()
 	}
+	// Transactions taking the reserved slots of a priority lane are exempt from
+	// eviction like the local ones, not to be pushed out by spam. They are still
+	// tracked as remote ones otherwise.
+	lane := pool.lanes.reservation(from, tx, pool.all)
+	if lane != nil && !isLocal {
+		defer func() {
+			if err == nil {
+				lane.reserved[hash] = laneReservation{from: from, slots: numSlots(tx)}
+			}
+		}()
+	}
+	protected := isLocal || lane != nil
+
 	// If the transaction pool is full, discard underpriced transactions
 	if uint64(pool.all.Slots()+numSlots(tx)) > pool.config.GlobalSlots+pool.config.GlobalQueue {
 		// If the new transaction is underpriced, don't accept it
-		if !isLocal && pool.priced.Underpriced(tx) {
+		if !protected && pool.priced.Underpriced(tx) {
 			log.Trace("Discarding underpriced transaction", "hash", hash, "gasTipCap", tx.GasTipCap(), "gasFeeCap", tx.GasFeeCap())
 			underpricedTxMeter.Mark(1)
 			return false, txpool.ErrUnderpriced
@@ -800,19 +865,19 @@ func (pool *LegacyPool) add(tx *types.Transaction, local bool) (replaced bool, e
 		}
 
 		// New transaction is better than our worse ones, make room for it.
-		// If it's a local transaction, forcibly discard all available transactions.
+		// If it's a protected transaction, forcibly discard all available transactions.
 		// Otherwise if we can't make enough room for new one, abort the operation.
-		drop, success := pool.priced.Discard(pool.all.Slots()-int(pool.config.GlobalSlots+pool.config.GlobalQueue)+numSlots(tx), isLocal)
+		drop, success := pool.priced.Discard(pool.all.Slots()-int(pool.config.GlobalSlots+pool.config.GlobalQueue)+numSlots(tx), protected)
 
 		// Special case, we still can't make the room for the new remote one.
-		if !isLocal && !success {
+		if !protected && !success {
 			log.Trace("Discarding overflown transaction", "hash", hash)
 			overflowedTxMeter.Mark(1)
 			return false, ErrTxPoolOverflow
 		}
 
 		// If the new transaction is a future transaction it should never churn pending transactions
-		if !isLocal && pool.isGapped(from, tx) {
+		if !protected && pool.isGapped(from, tx) {
 			var replacesPending bool
 			for _, dropTx := range drop {
 				dropSender, _ := types.Sender(pool.signer, dropTx)
@@ -838,6 +903,7 @@ func (pool *LegacyPool) add(tx *types.Transaction, local bool) (replaced bool, e
 
 			sender, _ := types.Sender(pool.signer, tx)
 			dropped := pool.removeTx(tx.Hash(), false, sender != from) // Don't unreserve the sender of the tx being added if last from the acc
+			pool.dropped(tx, core.TxUnderpriced, nil)
 
 			pool.changesSinceReorg += dropped
 		}
@@ -856,6 +922,7 @@ func (pool *LegacyPool) add(tx *types.Transaction, local bool) (replaced bool, e
 			pool.all.Remove(old.Hash())
 			pool.priced.Removed(1)
 			pendingReplaceMeter.Mark(1)
+			pool.dropped(old, core.TxReplaced, tx)
 		}
 		pool.all.Add(tx, isLocal)
 		pool.priced.Put(tx, isLocal)
@@ -931,6 +998,7 @@ func (pool *LegacyPool) enqueueTx(hash common.Hash, tx *types.Transaction, local
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed(1)
 		queuedReplaceMeter.Mark(1)
+		pool.dropped(old, core.TxReplaced, tx)
 	} else {
 		// Nothing was replaced, bump the queued counter
 		queuedGauge.Inc(1)
@@ -980,6 +1048,7 @@ func (pool *LegacyPool) promoteTx(addr common.Address, hash common.Hash, tx *typ
 		pool.all.Remove(hash)
 		pool.priced.Removed(1)
 		pendingDiscardMeter.Mark(1)
+		pool.dropped(tx, core.TxUnderpriced, nil)
 		return false
 	}
 	// Otherwise discard any previous transaction and mark this
@@ -987,6 +1056,7 @@ func (pool *LegacyPool) promoteTx(addr common.Address, hash common.Hash, tx *typ
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed(1)
 		pendingReplaceMeter.Mark(1)
+		pool.dropped(old, core.TxReplaced, tx)
 	} else {
 		// Nothing was replaced, bump the pending counter
 		pendingGauge.Inc(1)
@@ -1008,6 +1078,31 @@ func (pool *LegacyPool) addLocals(txs []*types.Transaction) []error {
 	return pool.Add(txs, !pool.config.NoLocals, true)
 }
 
+// addJournaled enqueues the local transactions loaded from the journal like
+// addLocals, leaving out the ones touching the blacklist: they skip the
+// admission check of the main pool.
+func (pool *LegacyPool) addJournaled(txs []*types.Transaction) []error {
+	var (
+		errs  = make([]error, len(txs))
+		adds  = make([]*types.Transaction, 0, len(txs))
+		index = make([]int, 0, len(txs))
+	)
+	for i, tx := range txs {
+		from, _ := types.Sender(pool.signer, tx) // invalid signatures are rejected by addLocals
+		if err := pool.blacklist.Check(from, tx.To()); err != nil {
+			log.Debug("Dropping blacklisted journaled transaction", "hash", tx.Hash(), "from", from)
+			errs[i] = err
+			continue
+		}
+		adds = append(adds, tx)
+		index = append(index, i)
+	}
+	for i, err := range pool.addLocals(adds) {
+		errs[index[i]] = err
+	}
+	return errs
+}
+
 // addLocal enqueues a single local transaction into the pool if it is valid. This is
 // a convenience wrapper around addLocals.
 func (pool *LegacyPool) addLocal(tx *types.Transaction) error {
@@ -1079,8 +1174,11 @@ func (pool *LegacyPool) Add(txs []*types.Transaction, local, sync bool) []error
 	// Process all the new transaction and merge any errors into the original slice
 	pool.mu.Lock()
 	newErrs, dirtyAddrs := pool.addTxsLocked(news, local)
+	events := pool.takeEvents()
 	pool.mu.Unlock()
 
+	pool.sendEvents(events)
+
 	var nilSlot = 0
 	for _, err := range newErrs {
 		for errs[nilSlot] != nil {
@@ -1153,6 +1251,79 @@ func (pool *LegacyPool) Has(hash common.Hash) bool {
 	return pool.all.Get(hash) != nil
 }
 
+// Lanes returns the configuration and usage of the priority lanes.
+func (pool *LegacyPool) Lanes() []LaneStatus {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	return pool.lanes.status(pool)
+}
+
+// SetLane adds a priority lane, or replaces the one with the same name.
+func (pool *LegacyPool) SetLane(lane Lane) error {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	return pool.lanes.set(lane)
+}
+
+// RemoveLane deletes a priority lane. Its transactions already pooled lose their
+// reserved slots and are evicted like the regular remote ones.
+func (pool *LegacyPool) RemoveLane(name string) error {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	return pool.lanes.remove(name)
+}
+
+// SetBlacklist sets the blacklist filtering the transactions loaded from the
+// journal. It must be called before the pool is initialised.
+func (pool *LegacyPool) SetBlacklist(blacklist *txpool.Blacklist) {
+	pool.blacklist = blacklist
+}
+
+// Purge implements txpool.Purger, dropping the pending and queued transactions
+// matched by their sender and recipient. The subsequent transactions of their
+// senders are moved back to the future queue.
+func (pool *LegacyPool) Purge(match func(from common.Address, to *common.Address) bool, reason core.TxPoolEventReason) int {
+	pool.mu.Lock()
+	var drops []*types.Transaction
+	for _, accounts := range []map[common.Address]*list{pool.pending, pool.queue} {
+		for addr, list := range accounts {
+			for _, tx := range list.Flatten() {
+				if match(addr, tx.To()) {
+					drops = append(drops, tx)
+				}
+			}
+		}
+	}
+	for _, tx := range drops {
+		pool.removeTx(tx.Hash(), true, true)
+		pool.dropped(tx, reason, nil)
+	}
+	events := pool.takeEvents()
+	pool.mu.Unlock()
+
+	pool.sendEvents(events)
+	return len(drops)
+}
+
+// Remove drops a transaction from the pool, moving all subsequent transactions
+// of its sender back to the future queue, and returns whether it was pooled.
+func (pool *LegacyPool) Remove(hash common.Hash, reason core.TxPoolEventReason) bool {
+	pool.mu.Lock()
+	tx := pool.all.Get(hash)
+	if tx != nil {
+		pool.removeTx(hash, true, true)
+		pool.dropped(tx, reason, nil)
+	}
+	events := pool.takeEvents()
+	pool.mu.Unlock()
+
+	pool.sendEvents(events)
+	return tx != nil
+}
+
 // removeTx removes a single transaction from the queue, moving all subsequent
 // transactions back to the future queue.
 //
@@ -1389,8 +1560,13 @@ func (pool *LegacyPool) runReorg(done chan struct{}, reset *txpoolResetRequest,
 
 	dropBetweenReorgHistogram.Update(int64(pool.changesSinceReorg))
 	pool.changesSinceReorg = 0 // Reset change counter
+	pool.mined = nil
+	dropped := pool.takeEvents()
 	pool.mu.Unlock()
 
+	// Notify subsystems of the transactions left
+	pool.sendEvents(dropped)
+
 	// Notify subsystems for newly added transactions
 	for _, tx := range promoted {
 		addr, _ := types.Sender(pool.signer, tx)
@@ -1412,8 +1588,13 @@ func (pool *LegacyPool) runReorg(done chan struct{}, reset *txpoolResetRequest,
 // of the transaction pool is valid with regard to the chain state.
 func (pool *LegacyPool) reset(oldHead, newHead *types.Header) {
 	// If we're reorging an old state, reinject all dropped transactions
-	var reinject types.Transactions
+	var reinject, included types.Transactions
 
+	if oldHead != nil && oldHead.Hash() == newHead.ParentHash {
+		if block := pool.chain.GetBlock(newHead.Hash(), newHead.Number.Uint64()); block != nil {
+			included = block.Transactions()
+		}
+	}
 	if oldHead != nil && oldHead.Hash() != newHead.ParentHash {
 		// If the reorg is too deep, avoid doing it (will happen during fast sync)
 		oldNum := oldHead.Number.Uint64()
@@ -1451,7 +1632,7 @@ func (pool *LegacyPool) reset(oldHead, newHead *types.Header) {
 					log.Warn("Transaction pool reset with missing new head", "number", newHead.Number, "hash", newHead.Hash())
 					return
 				}
-				var discarded, included types.Transactions
+				var discarded types.Transactions
 				for rem.NumberU64() > add.NumberU64() {
 					discarded = append(discarded, rem.Transactions()...)
 					if rem = pool.chain.GetBlock(rem.ParentHash(), rem.NumberU64()-1); rem == nil {
@@ -1488,6 +1669,11 @@ func (pool *LegacyPool) reset(oldHead, newHead *types.Header) {
 			}
 		}
 	}
+	// Track the included transactions to tell them apart from the superseded ones
+	pool.mined = make(map[common.Hash]struct{}, len(included))
+	for _, tx := range included {
+		pool.mined[tx.Hash()] = struct{}{}
+	}
 	// Initialize the internal state to the current head
 	if newHead == nil {
 		newHead = pool.chain.CurrentBlock() // Special case during testing
@@ -1526,6 +1712,7 @@ func (pool *LegacyPool) promoteExecutables(accounts []common.Address) []*types.T
 		for _, tx := range forwards {
 			hash := tx.Hash()
 			pool.all.Remove(hash)
+			pool.dropped(tx, pool.staleReason(tx), nil)
 		}
 		log.Trace("Removed old queued transactions", "count", len(forwards))
 		// Drop all transactions that are too costly (low balance or out of gas)
@@ -1533,6 +1720,7 @@ func (pool *LegacyPool) promoteExecutables(accounts []common.Address) []*types.T
 		for _, tx := range drops {
 			hash := tx.Hash()
 			pool.all.Remove(hash)
+			pool.dropped(tx, core.TxInsufficientFunds, nil)
 		}
 		log.Trace("Removed unpayable queued transactions", "count", len(drops))
 		queuedNofundsMeter.Mark(int64(len(drops)))
@@ -1555,6 +1743,7 @@ func (pool *LegacyPool) promoteExecutables(accounts []common.Address) []*types.T
 			for _, tx := range caps {
 				hash := tx.Hash()
 				pool.all.Remove(hash)
+				pool.dropped(tx, core.TxEvicted, nil)
 				log.Trace("Removed cap-exceeding queued transaction", "hash", hash)
 			}
 			queuedRateLimitMeter.Mark(int64(len(caps)))
@@ -1590,12 +1779,24 @@ func (pool *LegacyPool) truncatePending() {
 	}
 
 	pendingBeforeCap := pending
+	// The transactions taking the reserved slots of a priority lane are exempt, along
+	// with the ones before them, only the transactions above are evictable
+	exempt := make(map[common.Address]int)
+	evictable := func(addr common.Address) int {
+		return pool.pending[addr].Len() - exempt[addr]
+	}
 	// Assemble a spam order to penalize large transactors first
 	spammers := prque.New[int64, common.Address](nil)
 	for addr, list := range pool.pending {
+		if pool.locals.contains(addr) {
+			continue
+		}
+		if pool.lanes.contains(addr) {
+			exempt[addr] = pool.lanes.reservedPrefix(addr, list.Flatten())
+		}
 		// Only evict transactions from high rollers
-		if !pool.locals.contains(addr) && uint64(list.Len()) > pool.config.AccountSlots {
-			spammers.Push(addr, int64(list.Len()))
+		if uint64(evictable(addr)) > pool.config.AccountSlots {
+			spammers.Push(addr, int64(evictable(addr)))
 		}
 	}
 	// Gradually drop transactions from offenders
@@ -1608,10 +1809,10 @@ func (pool *LegacyPool) truncatePending() {
 		// Equalize balances until all the same or below threshold
 		if len(offenders) > 1 {
 			// Calculate the equalization threshold for all current offenders
-			threshold := pool.pending[offender].Len()
+			threshold := evictable(offender)
 
 			// Iteratively reduce all offenders until below limit or threshold reached
-			for pending > pool.config.GlobalSlots && pool.pending[offenders[len(offenders)-2]].Len() > threshold {
+			for pending > pool.config.GlobalSlots && evictable(offenders[len(offenders)-2]) > threshold {
 				for i := 0; i < len(offenders)-1; i++ {
 					list := pool.pending[offenders[i]]
 
@@ -1620,6 +1821,7 @@ func (pool *LegacyPool) truncatePending() {
 						// Drop the transaction from the global pools too
 						hash := tx.Hash()
 						pool.all.Remove(hash)
+						pool.dropped(tx, core.TxEvicted, nil)
 
 						// Update the account nonce to the dropped transaction
 						pool.pendingNonces.setIfLower(offenders[i], tx.Nonce())
@@ -1638,7 +1840,7 @@ func (pool *LegacyPool) truncatePending() {
 
 	// If still above threshold, reduce to limit or min allowance
 	if pending > pool.config.GlobalSlots && len(offenders) > 0 {
-		for pending > pool.config.GlobalSlots && uint64(pool.pending[offenders[len(offenders)-1]].Len()) > pool.config.AccountSlots {
+		for pending > pool.config.GlobalSlots && uint64(evictable(offenders[len(offenders)-1])) > pool.config.AccountSlots {
 			for _, addr := range offenders {
 				list := pool.pending[addr]
 
@@ -1647,6 +1849,7 @@ func (pool *LegacyPool) truncatePending() {
 					// Drop the transaction from the global pools too
 					hash := tx.Hash()
 					pool.all.Remove(hash)
+					pool.dropped(tx, core.TxEvicted, nil)
 
 					// Update the account nonce to the dropped transaction
 					pool.pendingNonces.setIfLower(addr, tx.Nonce())
@@ -1675,13 +1878,24 @@ func (pool *LegacyPool) truncateQueue() {
 	}
 
 	// Sort all accounts with queued transactions by heartbeat
-	addresses := make(addressesByHeartbeat, 0, len(pool.queue))
+	var (
+		addresses = make(addressesByHeartbeat, 0, len(pool.queue))
+		lanes     addressesByHeartbeat
+	)
 	for addr := range pool.queue {
-		if !pool.locals.contains(addr) { // don't drop locals
+		switch {
+		case pool.locals.contains(addr): // don't drop locals
+		case pool.lanes.contains(addr):
+			lanes = append(lanes, addressByHeartbeat{addr, pool.beats[addr]})
+		default:
 			addresses = append(addresses, addressByHeartbeat{addr, pool.beats[addr]})
 		}
 	}
 	sort.Sort(sort.Reverse(addresses))
+	sort.Sort(sort.Reverse(lanes))
+
+	// Drop the priority lane senders only once all the regular ones are gone
+	addresses = append(lanes, addresses...)
 
 	// Drop transactions until the total is below the limit or only locals remain
 	for drop := queued - pool.config.GlobalQueue; drop > 0 && len(addresses) > 0; {
@@ -1694,6 +1908,7 @@ func (pool *LegacyPool) truncateQueue() {
 		if size := uint64(list.Len()); size <= drop {
 			for _, tx := range list.Flatten() {
 				pool.removeTx(tx.Hash(), true, true)
+				pool.dropped(tx, core.TxEvicted, nil)
 			}
 			drop -= size
 			queuedRateLimitMeter.Mark(int64(size))
@@ -1703,6 +1918,7 @@ func (pool *LegacyPool) truncateQueue() {
 		txs := list.Flatten()
 		for i := len(txs) - 1; i >= 0 && drop > 0; i-- {
 			pool.removeTx(txs[i].Hash(), true, true)
+			pool.dropped(txs[i], core.TxEvicted, nil)
 			drop--
 			queuedRateLimitMeter.Mark(1)
 		}
@@ -1727,6 +1943,7 @@ func (pool *LegacyPool) demoteUnexecutables() {
 		for _, tx := range olds {
 			hash := tx.Hash()
 			pool.all.Remove(hash)
+			pool.dropped(tx, pool.staleReason(tx), nil)
 			log.Trace("Removed old pending transaction", "hash", hash)
 		}
 		// Drop all transactions that are too costly (low balance or out of gas), and queue any invalids back for later
@@ -1735,6 +1952,7 @@ func (pool *LegacyPool) demoteUnexecutables() {
 			hash := tx.Hash()
 			log.Trace("Removed unpayable pending transaction", "hash", hash)
 			pool.all.Remove(hash)
+			pool.dropped(tx, core.TxInsufficientFunds, nil)
 		}
 		pendingNofundsMeter.Mark(int64(len(drops)))
 
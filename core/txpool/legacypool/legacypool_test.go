@@ -17,6 +17,7 @@
 package legacypool
 
 import (
+	"bytes"
 	"crypto/ecdsa"
 	crand "crypto/rand"
 	"errors"
@@ -24,6 +25,8 @@ import (
 	"math/big"
 	"math/rand"
 	"os"
+	"path/filepath"
+	"reflect"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -2343,6 +2346,175 @@ func TestReplacementDynamicFee(t *testing.T) {
 
 // Tests that local transactions are journaled to disk, but remote transactions
 // get discarded between restarts.
+// minedTestChain is a test chain whose blocks all include the same transactions.
+type minedTestChain struct {
+	*testBlockChain
+	block *types.Block
+}
+
+func (bc *minedTestChain) GetBlock(hash common.Hash, number uint64) *types.Block {
+	return bc.block
+}
+
+// Tests that the transactions leaving the pool are announced along with the
+// reason, telling the mined ones from the ones superseded on chain.
+func TestTxPoolEvents(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := newTestBlockChain(params.TestChainConfig, 1000000, statedb, new(event.Feed))
+
+	pool := New(testTxPoolConfig, blockchain)
+	pool.Init(testTxPoolConfig.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver())
+	defer pool.Close()
+
+	events := make(chan []core.TxPoolEvent, 32)
+	sub := pool.SubscribeTxPoolEvents(events)
+	defer sub.Unsubscribe()
+
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	testAddBalance(pool, addr, big.NewInt(1000000000))
+
+	expect := func(want ...core.TxPoolEvent) {
+		t.Helper()
+		var have []core.TxPoolEvent
+		for len(have) < len(want) {
+			select {
+			case evs := <-events:
+				have = append(have, evs...)
+			case <-time.After(time.Second):
+				t.Fatalf("event count mismatch: have %d, want %d", len(have), len(want))
+			}
+		}
+		if !reflect.DeepEqual(have, want) {
+			t.Fatalf("events mismatch:\nhave %+v\nwant %+v", have, want)
+		}
+	}
+	// Replace a pending transaction
+	var (
+		tx0 = pricedTransaction(0, 100000, big.NewInt(1), key)
+		tx1 = pricedTransaction(0, 100000, big.NewInt(2), key)
+		tx2 = pricedTransaction(1, 100000, big.NewInt(2), key)
+	)
+	if err := pool.addRemoteSync(tx0); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	if err := pool.addRemoteSync(tx1); err != nil {
+		t.Fatalf("failed to replace transaction: %v", err)
+	}
+	replacement := tx1.Hash()
+	expect(core.TxPoolEvent{Hash: tx0.Hash(), Reason: core.TxReplaced, ReplacedBy: &replacement})
+
+	// Include the replacement in a block while the nonce of the next transaction
+	// gets spent by another one
+	if err := pool.addRemoteSync(tx2); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	var (
+		parent = blockchain.CurrentBlock()
+		block  = types.NewBlock(&types.Header{Number: big.NewInt(1), ParentHash: parent.Hash(), GasLimit: parent.GasLimit, BaseFee: big.NewInt(1)}, []*types.Transaction{tx1}, nil, nil, trie.NewStackTrie(nil))
+	)
+	pool.chain = &minedTestChain{testBlockChain: blockchain, block: block}
+	testSetNonce(pool, addr, 2)
+	<-pool.requestReset(parent, block.Header())
+
+	expect(
+		core.TxPoolEvent{Hash: tx1.Hash(), Reason: core.TxMined},
+		core.TxPoolEvent{Hash: tx2.Hash(), Reason: core.TxNonceTooLow},
+	)
+	// Raise the minimum tip above a pooled transaction
+	tx3 := pricedTransaction(2, 100000, big.NewInt(1), key)
+	if err := pool.addRemoteSync(tx3); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	pool.SetGasTip(big.NewInt(2))
+	expect(core.TxPoolEvent{Hash: tx3.Hash(), Reason: core.TxUnderpriced})
+}
+
+// Tests that the pooled transactions of newly blacklisted accounts are dropped,
+// and that the journaled ones are filtered on load.
+func TestBlacklistPurge(t *testing.T) {
+	t.Parallel()
+
+	var (
+		statedb, _ = state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		blockchain = newTestBlockChain(params.TestChainConfig, 1000000, statedb, new(event.Feed))
+		journal    = filepath.Join(t.TempDir(), "transactions.rlp")
+		banned, _  = crypto.GenerateKey()
+		other, _   = crypto.GenerateKey()
+	)
+	bannedAddr := crypto.PubkeyToAddress(banned.PublicKey)
+	statedb.AddBalance(bannedAddr, uint256.NewInt(1000000000))
+	statedb.AddBalance(crypto.PubkeyToAddress(other.PublicKey), uint256.NewInt(1000000000))
+
+	config := testTxPoolConfig
+	config.Journal = journal
+
+	blacklist, _ := txpool.NewBlacklist("")
+	legacy := New(config, blockchain)
+	pool, err := txpool.New(config.PriceLimit, blockchain, []txpool.SubPool{legacy})
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	pool.SetBlacklist(blacklist)
+
+	events := make(chan []core.TxPoolEvent, 32)
+	sub := pool.SubscribeTxPoolEvents(events)
+	defer sub.Unsubscribe()
+
+	// Pool a pending and a queued transaction of the account to ban, a local one
+	// journaled, and a transaction of another account
+	var (
+		pending = transaction(0, 100000, banned)
+		queued  = transaction(2, 100000, banned)
+		kept    = transaction(0, 100000, other)
+	)
+	if err := legacy.addLocal(pending); err != nil {
+		t.Fatalf("failed to add local transaction: %v", err)
+	}
+	for _, err := range pool.Add([]*types.Transaction{queued, kept}, false, true) {
+		if err != nil {
+			t.Fatalf("failed to add transaction: %v", err)
+		}
+	}
+	if err := blacklist.Add([]common.Address{bannedAddr}); err != nil {
+		t.Fatalf("failed to blacklist address: %v", err)
+	}
+	if pool.Has(pending.Hash()) || pool.Has(queued.Hash()) || !pool.Has(kept.Hash()) {
+		t.Fatal("blacklisted transactions not purged")
+	}
+	dropped := make(map[common.Hash]core.TxPoolEventReason)
+	for len(dropped) < 2 {
+		select {
+		case evs := <-events:
+			for _, ev := range evs {
+				dropped[ev.Hash] = ev.Reason
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("event count mismatch: have %d, want 2", len(dropped))
+		}
+	}
+	if dropped[pending.Hash()] != core.TxBlacklisted || dropped[queued.Hash()] != core.TxBlacklisted {
+		t.Fatalf("drop reasons mismatch: %v", dropped)
+	}
+	// The local transaction is still journaled, restart with the banned account
+	pool.Close()
+
+	blacklist, _ = txpool.NewBlacklist("")
+	defer blacklist.Close()
+	blacklist.Add([]common.Address{bannedAddr})
+
+	legacy = New(config, blockchain)
+	legacy.SetBlacklist(blacklist)
+	legacy.Init(config.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver())
+	defer legacy.Close()
+
+	if legacy.Has(pending.Hash()) {
+		t.Fatal("blacklisted journaled transaction loaded")
+	}
+}
+
 func TestJournaling(t *testing.T)         { testJournaling(t, false) }
 func TestJournalingNoLocals(t *testing.T) { testJournaling(t, true) }
 
@@ -2712,3 +2884,339 @@ func BenchmarkMultiAccountBatchInsert(b *testing.B) {
 		pool.addRemotesSync([]*types.Transaction{tx})
 	}
 }
+
+// Tests that removing a pending transaction on demand demotes the subsequent
+// ones of the same sender and reports the given reason.
+func TestRemove(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupPool()
+	defer pool.Close()
+
+	events := make(chan []core.TxPoolEvent, 32)
+	sub := pool.SubscribeTxPoolEvents(events)
+	defer sub.Unsubscribe()
+
+	testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+
+	txs := []*types.Transaction{transaction(0, 100000, key), transaction(1, 100000, key)}
+	for _, err := range pool.addRemotesSync(txs) {
+		if err != nil {
+			t.Fatalf("failed to add transaction: %v", err)
+		}
+	}
+	if !pool.Remove(txs[0].Hash(), core.TxExpired) {
+		t.Fatalf("pooled transaction not removed")
+	}
+	if pool.Remove(txs[0].Hash(), core.TxExpired) {
+		t.Fatalf("unknown transaction removed")
+	}
+	if pending, queued := pool.Stats(); pending != 0 || queued != 1 {
+		t.Fatalf("pool stats mismatch: have %d/%d, want 0/1", pending, queued)
+	}
+	select {
+	case evs := <-events:
+		want := []core.TxPoolEvent{{Hash: txs[0].Hash(), Reason: core.TxExpired}}
+		if !reflect.DeepEqual(evs, want) {
+			t.Fatalf("events mismatch: have %+v, want %+v", evs, want)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("removal event not sent")
+	}
+}
+
+// Tests that the transactions of a priority lane take its reserved slots even if
+// the pool is full of better paying ones, as long as they pay the lane minimum.
+func TestPriorityLanes(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := newTestBlockChain(params.TestChainConfig, 1000000, statedb, new(event.Feed))
+
+	laneKey, _ := crypto.GenerateKey()
+	laneAddr := crypto.PubkeyToAddress(laneKey.PublicKey)
+
+	config := testTxPoolConfig
+	config.GlobalSlots = 2
+	config.GlobalQueue = 2
+	config.Lanes = []Lane{{Name: "bots", Addresses: []common.Address{laneAddr}, Slots: 2, MinTip: 2}}
+
+	pool := New(config, blockchain)
+	pool.Init(config.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver())
+	defer pool.Close()
+
+	testAddBalance(pool, laneAddr, big.NewInt(1000000000))
+
+	// Fill the pool up with well paying remote transactions
+	for i := 0; i < 4; i++ {
+		key, _ := crypto.GenerateKey()
+		testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+		if err := pool.addRemoteSync(pricedTransaction(0, 100000, big.NewInt(10), key)); err != nil {
+			t.Fatalf("failed to add remote transaction: %v", err)
+		}
+	}
+	// Lane transactions below the lane minimum tip are regular remote ones
+	if err := pool.addRemoteSync(pricedTransaction(0, 100000, big.NewInt(1), laneKey)); !errors.Is(err, txpool.ErrUnderpriced) {
+		t.Fatalf("adding lane transaction below minimum tip error mismatch: have %v, want %v", err, txpool.ErrUnderpriced)
+	}
+	// Lane transactions take the reserved slots until exhausted
+	for nonce := uint64(0); nonce < 2; nonce++ {
+		if err := pool.addRemoteSync(pricedTransaction(nonce, 100000, big.NewInt(2), laneKey)); err != nil {
+			t.Fatalf("failed to add lane transaction %d: %v", nonce, err)
+		}
+	}
+	if err := pool.addRemoteSync(pricedTransaction(2, 100000, big.NewInt(2), laneKey)); !errors.Is(err, txpool.ErrUnderpriced) {
+		t.Fatalf("adding lane transaction beyond reservation error mismatch: have %v, want %v", err, txpool.ErrUnderpriced)
+	}
+	if pending, queued := pool.Stats(); pending != 4 || queued != 0 {
+		t.Fatalf("pool stats mismatch: have %d/%d, want 4/0", pending, queued)
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+	lanes := pool.Lanes()
+	if len(lanes) != 1 || lanes[0].Reserved != 2 || lanes[0].Pending != 2 {
+		t.Fatalf("lane status mismatch: have %+v", lanes)
+	}
+	// Lanes are managed at runtime, senders belonging to a single one
+	if err := pool.SetLane(Lane{Name: "other", Addresses: []common.Address{laneAddr}}); err == nil {
+		t.Fatalf("sender added to two lanes")
+	}
+	if local := pool.all.LocalCount(); local != 0 {
+		t.Fatalf("lane transactions tracked as local: %d", local)
+	}
+	if err := pool.RemoveLane("bots"); err != nil {
+		t.Fatalf("failed to remove lane: %v", err)
+	}
+	if err := pool.RemoveLane("bots"); !errors.Is(err, errLaneUnknown) {
+		t.Fatalf("removing unknown lane error mismatch: have %v, want %v", err, errLaneUnknown)
+	}
+	// The transactions of a removed lane are regular remote ones
+	pool.SetGasTip(big.NewInt(3))
+	if pending, queued := pool.Stats(); pending != 2 || queued != 0 {
+		t.Fatalf("pool stats after raising the gas tip mismatch: have %d/%d, want 2/0", pending, queued)
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that the pending truncation only spares the transactions taking the
+// reserved slots of a priority lane, the other lane transactions being evicted
+// like the ones of any remote sender.
+func TestPriorityLanePendingTruncation(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := newTestBlockChain(params.TestChainConfig, 1000000, statedb, new(event.Feed))
+
+	laneKey, _ := crypto.GenerateKey()
+	laneAddr := crypto.PubkeyToAddress(laneKey.PublicKey)
+	spamKey, _ := crypto.GenerateKey()
+	spamAddr := crypto.PubkeyToAddress(spamKey.PublicKey)
+
+	config := testTxPoolConfig
+	config.GlobalSlots = 4
+	config.AccountSlots = 1
+	config.Lanes = []Lane{{Name: "bots", Addresses: []common.Address{laneAddr}, Slots: 2, MinTip: 2}}
+
+	pool := New(config, blockchain)
+	pool.Init(config.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver())
+	defer pool.Close()
+
+	testAddBalance(pool, laneAddr, big.NewInt(1000000000))
+	testAddBalance(pool, spamAddr, big.NewInt(1000000000))
+
+	// The first two lane transactions take the reserved slots, the others don't
+	var txs types.Transactions
+	for nonce := uint64(0); nonce < 4; nonce++ {
+		txs = append(txs, pricedTransaction(nonce, 100000, big.NewInt(2), laneKey))
+	}
+	pool.addRemotesSync(txs)
+	if pending, _ := pool.Stats(); pending != 4 {
+		t.Fatalf("pending transactions mismatch: have %d, want 4", pending)
+	}
+	// Overflow the pending limit with a regular sender
+	txs = txs[:0]
+	for nonce := uint64(0); nonce < 4; nonce++ {
+		txs = append(txs, pricedTransaction(nonce, 100000, big.NewInt(2), spamKey))
+	}
+	pool.addRemotesSync(txs)
+
+	if have := pool.pending[spamAddr].Len(); have != 1 {
+		t.Errorf("regular sender pending mismatch: have %d, want 1", have)
+	}
+	// The reserved transactions are left out, the ones above are truncated
+	// down to the account allowance
+	if have := pool.pending[laneAddr].Len(); have != 3 {
+		t.Errorf("lane sender pending mismatch: have %d, want 3", have)
+	}
+	if lanes := pool.Lanes(); len(lanes) != 1 || lanes[0].Reserved != 2 {
+		t.Errorf("lane status mismatch: have %+v", lanes)
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that the queue truncation drops the transactions of the regular senders
+// before the ones of the priority lane senders, regardless of their heartbeat.
+func TestPriorityLaneQueueTruncation(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := newTestBlockChain(params.TestChainConfig, 1000000, statedb, new(event.Feed))
+
+	laneKey, _ := crypto.GenerateKey()
+	laneAddr := crypto.PubkeyToAddress(laneKey.PublicKey)
+	regularKey, _ := crypto.GenerateKey()
+	regularAddr := crypto.PubkeyToAddress(regularKey.PublicKey)
+
+	config := testTxPoolConfig
+	config.GlobalQueue = 2
+	config.Lanes = []Lane{{Name: "bots", Addresses: []common.Address{laneAddr}, Slots: 2, MinTip: 2}}
+
+	pool := New(config, blockchain)
+	pool.Init(config.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver())
+	defer pool.Close()
+
+	testAddBalance(pool, laneAddr, big.NewInt(1000000000))
+	testAddBalance(pool, regularAddr, big.NewInt(1000000000))
+
+	// Queue the lane transactions first, their sender having the oldest heartbeat
+	pool.addRemotesSync(types.Transactions{
+		pricedTransaction(1, 100000, big.NewInt(2), laneKey),
+		pricedTransaction(2, 100000, big.NewInt(2), laneKey),
+	})
+	time.Sleep(10 * time.Millisecond) // order the heartbeats
+	pool.addRemotesSync(types.Transactions{
+		pricedTransaction(1, 100000, big.NewInt(2), regularKey),
+		pricedTransaction(2, 100000, big.NewInt(2), regularKey),
+	})
+	if list := pool.queue[regularAddr]; list != nil && list.Len() != 0 {
+		t.Errorf("regular sender queue not truncated: have %d", list.Len())
+	}
+	if list := pool.queue[laneAddr]; list == nil || list.Len() != 2 {
+		t.Errorf("lane sender queue truncated")
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that replacing a priority lane releases the reserved slots taken by the
+// transactions of the senders removed from it.
+func TestPriorityLaneAddressChange(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := newTestBlockChain(params.TestChainConfig, 1000000, statedb, new(event.Feed))
+
+	keptKey, _ := crypto.GenerateKey()
+	keptAddr := crypto.PubkeyToAddress(keptKey.PublicKey)
+	leftKey, _ := crypto.GenerateKey()
+	leftAddr := crypto.PubkeyToAddress(leftKey.PublicKey)
+
+	config := testTxPoolConfig
+	config.Lanes = []Lane{{Name: "bots", Addresses: []common.Address{keptAddr, leftAddr}, Slots: 2, MinTip: 2}}
+
+	pool := New(config, blockchain)
+	pool.Init(config.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver())
+	defer pool.Close()
+
+	testAddBalance(pool, keptAddr, big.NewInt(1000000000))
+	testAddBalance(pool, leftAddr, big.NewInt(1000000000))
+
+	var (
+		kept = pricedTransaction(0, 100000, big.NewInt(2), keptKey)
+		left = pricedTransaction(0, 100000, big.NewInt(2), leftKey)
+	)
+	pool.addRemotesSync(types.Transactions{kept, left})
+	if lanes := pool.Lanes(); len(lanes) != 1 || lanes[0].Reserved != 2 {
+		t.Fatalf("lane status mismatch: have %+v", lanes)
+	}
+	if err := pool.SetLane(Lane{Name: "bots", Addresses: []common.Address{keptAddr}, Slots: 2, MinTip: 2}); err != nil {
+		t.Fatalf("failed to replace lane: %v", err)
+	}
+	if lanes := pool.Lanes(); len(lanes) != 1 || lanes[0].Reserved != 1 {
+		t.Fatalf("lane status mismatch after replacement: have %+v", lanes)
+	}
+	if !pool.lanes.reserves(kept.Hash()) {
+		t.Errorf("transaction of the kept sender lost its reservation")
+	}
+	if pool.lanes.reserves(left.Hash()) {
+		t.Errorf("transaction of the removed sender still exempt from eviction")
+	}
+	// The removed sender can join another lane
+	if err := pool.SetLane(Lane{Name: "other", Addresses: []common.Address{leftAddr}}); err != nil {
+		t.Fatalf("failed to add removed sender to another lane: %v", err)
+	}
+}
+
+// Tests that the pending and queued transactions exported by a pool are imported
+// back by another one, leaving the private transactions out.
+func TestTxPoolSnapshot(t *testing.T) {
+	t.Parallel()
+
+	var (
+		statedb, _ = state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		key, _     = crypto.GenerateKey()
+		privKey, _ = crypto.GenerateKey()
+		file       = filepath.Join(t.TempDir(), "txpool.rlp")
+	)
+	statedb.AddBalance(crypto.PubkeyToAddress(key.PublicKey), uint256.NewInt(1000000000))
+	statedb.AddBalance(crypto.PubkeyToAddress(privKey.PublicKey), uint256.NewInt(1000000000))
+
+	newPool := func() *txpool.TxPool {
+		chain := newTestBlockChain(params.TestChainConfig, 1000000, statedb, new(event.Feed))
+		pool, err := txpool.New(testTxPoolConfig.PriceLimit, chain, []txpool.SubPool{New(testTxPoolConfig, chain)})
+		if err != nil {
+			t.Fatalf("failed to create pool: %v", err)
+		}
+		return pool
+	}
+	source := newPool()
+	defer source.Close()
+
+	// Pool two pending and one queued transactions, plus a private one
+	txs := []*types.Transaction{transaction(0, 100000, key), transaction(1, 100000, key), transaction(3, 100000, key)}
+	for _, err := range source.Add(txs, false, true) {
+		if err != nil {
+			t.Fatalf("failed to add transaction: %v", err)
+		}
+	}
+	if err := source.AddPrivate([]*types.Transaction{transaction(0, 100000, privKey)}, 100, true)[0]; err != nil {
+		t.Fatalf("failed to add private transaction: %v", err)
+	}
+	if n, err := source.Export(file, false); err != nil || n != len(txs) {
+		t.Fatalf("export mismatch: have %d/%v, want %d/nil", n, err, len(txs))
+	}
+	// Exporting again must refuse to overwrite the snapshot, leaving nothing aside
+	blob, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("failed to read snapshot: %v", err)
+	}
+	if _, err := source.Export(file, false); err == nil {
+		t.Fatalf("export overwrote an existing file")
+	}
+	if have, err := os.ReadFile(file); err != nil || !bytes.Equal(have, blob) {
+		t.Fatalf("snapshot modified by refused export: %v", err)
+	}
+	if entries, err := os.ReadDir(filepath.Dir(file)); err != nil || len(entries) != 1 {
+		t.Fatalf("temporary files left behind: %v", entries)
+	}
+	if n, err := source.Export(file, true); err != nil || n != len(txs) {
+		t.Fatalf("overwriting export mismatch: have %d/%v, want %d/nil", n, err, len(txs))
+	}
+	target := newPool()
+	defer target.Close()
+
+	if n, err := target.Import(file); err != nil || n != len(txs) {
+		t.Fatalf("import mismatch: have %d/%v, want %d/nil", n, err, len(txs))
+	}
+	for _, tx := range txs {
+		if !target.Has(tx.Hash()) {
+			t.Errorf("transaction %x not imported", tx.Hash())
+		}
+	}
+}
@@ -563,9 +563,10 @@ type pricedList struct {
 	// Number of stale price points to (re-heap trigger).
 	stales atomic.Int64
 
-	all              *lookup    // Pointer to the map of all transactions
-	urgent, floating priceHeap  // Heaps of prices of all the stored **remote** transactions
-	reheapMu         sync.Mutex // Mutex asserts that only one routine is reheaping the list
+	all              *lookup                // Pointer to the map of all transactions
+	urgent, floating priceHeap              // Heaps of prices of all the stored **remote** transactions
+	exempt           func(common.Hash) bool // Remote transactions never discarded, if set
+	reheapMu         sync.Mutex             // Mutex asserts that only one routine is reheaping the list
 }
 
 const (
@@ -639,9 +640,13 @@ func (l *pricedList) underpricedFor(h *priceHeap, tx *types.Transaction) bool {
 // priced list and returns them for further removal from the entire pool.
 // If noPending is set to true, we will only consider the floating list
 //
-// Note local transaction won't be considered for eviction.
+// Note local transaction won't be considered for eviction, neither will the
+// exempt remote ones.
 func (l *pricedList) Discard(slots int, force bool) (types.Transactions, bool) {
-	drop := make(types.Transactions, 0, slots) // Remote underpriced transactions to drop
+	var (
+		drop = make(types.Transactions, 0, slots) // Remote underpriced transactions to drop
+		kept types.Transactions                   // Exempt transactions to put back
+	)
 	for slots > 0 {
 		if len(l.urgent.list)*floatingRatio > len(l.floating.list)*urgentRatio {
 			// Discard stale transactions if found during cleanup
@@ -663,11 +668,18 @@ func (l *pricedList) Discard(slots int, force bool) (types.Transactions, bool) {
 				l.stales.Add(-1)
 				continue
 			}
-			// Non stale transaction found, discard it
+			// Non stale transaction found, discard it unless exempt
+			if l.exempt != nil && l.exempt(tx.Hash()) {
+				kept = append(kept, tx)
+				continue
+			}
 			drop = append(drop, tx)
 			slots -= numSlots(tx)
 		}
 	}
+	for _, tx := range kept {
+		heap.Push(&l.urgent, tx)
+	}
 	// If we still can't make enough room for the new transaction
 	if slots > 0 && !force {
 		for _, tx := range drop {
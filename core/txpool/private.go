@@ -0,0 +1,84 @@
+package txpool
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// privateTxs tracks the transactions submitted privately, which are never
+// propagated to the network and are dropped from the pool once expired. They
+// are unmarked when they leave the pool, mined, replaced or dropped.
+type privateTxs struct {
+	expiry map[common.Hash]uint64 // Last block number each transaction may be included in
+	lock   sync.RWMutex
+
+	adding sync.Mutex // Held while marked transactions are added, not to sweep them before being pooled
+}
+
+func newPrivateTxs() *privateTxs {
+	return &privateTxs{expiry: make(map[common.Hash]uint64)}
+}
+
+// add marks a transaction private until the given block, returning whether it
+// was not private yet.
+func (p *privateTxs) add(hash common.Hash, expiry uint64) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	_, known := p.expiry[hash]
+	p.expiry[hash] = expiry
+	return !known
+}
+
+// remove unmarks a private transaction.
+func (p *privateTxs) remove(hash common.Hash) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	delete(p.expiry, hash)
+}
+
+// contains returns whether a transaction is private.
+func (p *privateTxs) contains(hash common.Hash) bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	_, ok := p.expiry[hash]
+	return ok
+}
+
+// sweep unmarks the private transactions which aren't pooled anymore.
+func (p *privateTxs) sweep(pooled func(common.Hash) bool) {
+	p.adding.Lock()
+	defer p.adding.Unlock()
+
+	p.lock.RLock()
+	hashes := make([]common.Hash, 0, len(p.expiry))
+	for hash := range p.expiry {
+		hashes = append(hashes, hash)
+	}
+	p.lock.RUnlock()
+
+	for _, hash := range hashes {
+		if !pooled(hash) {
+			p.remove(hash)
+		}
+	}
+}
+
+// expire unmarks and returns the private transactions which can't be included
+// anymore on top of the given block.
+func (p *privateTxs) expire(number uint64) []common.Hash {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	var expired []common.Hash
+	for hash, expiry := range p.expiry {
+		if expiry <= number {
+			expired = append(expired, hash)
+			delete(p.expiry, hash)
+		}
+	}
+	return expired
+}
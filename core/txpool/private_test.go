@@ -0,0 +1,54 @@
+package txpool
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Tests that private transactions are tracked until their expiry block and
+// resubmissions are told apart from the new ones.
+func TestPrivateTxsExpiry(t *testing.T) {
+	var (
+		private = newPrivateTxs()
+		a       = common.Hash{0x01}
+		b       = common.Hash{0x02}
+	)
+	if !private.add(a, 10) {
+		t.Fatalf("new private transaction reported known")
+	}
+	if private.add(a, 12) {
+		t.Fatalf("resubmitted private transaction reported new")
+	}
+	private.add(b, 11)
+
+	if expired := private.expire(10); len(expired) != 0 {
+		t.Fatalf("expired before the max block: %v", expired)
+	}
+	if expired := private.expire(11); len(expired) != 1 || expired[0] != b {
+		t.Fatalf("expired mismatch: have %v, want [%v]", expired, b)
+	}
+	if private.contains(b) || !private.contains(a) {
+		t.Fatalf("private set mismatch after expiry")
+	}
+	private.remove(a)
+	if private.contains(a) {
+		t.Fatalf("removed transaction still private")
+	}
+}
+
+// Tests that private transactions are unmarked once they leave the pool.
+func TestPrivateTxsSweep(t *testing.T) {
+	var (
+		private = newPrivateTxs()
+		pooled  = common.Hash{0x01}
+		mined   = common.Hash{0x02}
+	)
+	private.add(pooled, 10)
+	private.add(mined, 10)
+
+	private.sweep(func(hash common.Hash) bool { return hash == pooled })
+	if !private.contains(pooled) || private.contains(mined) {
+		t.Fatalf("private set mismatch after sweep")
+	}
+}
@@ -0,0 +1,127 @@
+package txpool
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// snapshotImportBatch is the number of transactions added to the pool at once
+// when importing a snapshot.
+const snapshotImportBatch = 1024
+
+// errSnapshotExists is returned when exporting the pool to an existing file.
+var errSnapshotExists = errors.New("location would overwrite an existing file")
+
+// Export writes the pending and queued transactions of the pool to the given file
+// as an RLP stream, in nonce order per sender, and returns their number. The blob
+// transactions, persisted by their own subpool, and the private ones are left out.
+//
+// The file is written to a fresh temporary file aside and moved in place once
+// complete, not to leave a truncated snapshot behind on failure. Existing files
+// are only replaced if overwrite is set, the path may point anywhere on the drive.
+func (p *TxPool) Export(path string, overwrite bool) (int, error) {
+	if _, err := os.Lstat(path); err == nil && !overwrite {
+		return 0, errSnapshotExists
+	}
+	out, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.new")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(out.Name()) // noop once renamed in place
+
+	var (
+		pending, queued = p.Content()
+		exported        int
+	)
+	// Write the pending transactions first, so the queued ones of the same sender
+	// follow them on import
+	for _, content := range []map[common.Address][]*types.Transaction{pending, queued} {
+		for _, txs := range content {
+			for _, tx := range txs {
+				if tx.Type() == types.BlobTxType || p.IsPrivate(tx.Hash()) {
+					continue
+				}
+				if err := rlp.Encode(out, tx); err != nil {
+					out.Close()
+					return 0, err
+				}
+				exported++
+			}
+		}
+	}
+	if err := out.Chmod(0644); err != nil {
+		out.Close()
+		return 0, err
+	}
+	if err := out.Close(); err != nil {
+		return 0, err
+	}
+	if overwrite {
+		if err := os.Rename(out.Name(), path); err != nil {
+			return 0, err
+		}
+		return exported, nil
+	}
+	// Linking fails if the file was created meanwhile, unlike renaming
+	if err := os.Link(out.Name(), path); err != nil {
+		if os.IsExist(err) {
+			return 0, errSnapshotExists
+		}
+		return 0, err
+	}
+	return exported, nil
+}
+
+// Import adds the transactions of a file written by Export to the pool as remote
+// ones, validated against the current head, and returns the number of them added.
+func (p *TxPool) Import(path string) (int, error) {
+	input, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer input.Close()
+
+	var (
+		stream  = rlp.NewStream(input, 0)
+		batch   = make([]*types.Transaction, 0, snapshotImportBatch)
+		total   int
+		added   int
+		failure error
+	)
+	flush := func() {
+		for _, err := range p.Add(batch, false, false) {
+			if err != nil {
+				log.Debug("Failed to import pooled transaction", "err", err)
+				continue
+			}
+			added++
+		}
+		batch = batch[:0]
+	}
+	for {
+		tx := new(types.Transaction)
+		if err := stream.Decode(tx); err != nil {
+			if err != io.EOF {
+				failure = err
+			}
+			break
+		}
+		total++
+
+		if batch = append(batch, tx); len(batch) == snapshotImportBatch {
+			flush()
+		}
+	}
+	if len(batch) > 0 {
+		flush()
+	}
+	log.Info("Imported transaction pool snapshot", "file", path, "transactions", total, "added", added)
+	return added, failure
+}
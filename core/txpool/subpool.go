@@ -144,6 +144,10 @@ type SubPool interface {
 	// ReannoTxsEvent and send events to the given channel.
 	SubscribeReannoTxsEvent(chan<- core.ReannoTxsEvent) event.Subscription
 
+	// SubscribeTxPoolEvents subscribes to the events of transactions leaving
+	// the pool, or refused by it, along with the reason.
+	SubscribeTxPoolEvents(ch chan<- []core.TxPoolEvent) event.Subscription
+
 	// Nonce returns the next nonce of an account, with all transactions executable
 	// by the pool already applied on top.
 	Nonce(addr common.Address) uint64
@@ -170,3 +174,20 @@ type SubPool interface {
 	// SetMaxGas limit max acceptable tx gas when mine is enabled
 	SetMaxGas(maxGas uint64)
 }
+
+// Remover is implemented by the subpools able to drop transactions on demand,
+// which is required to accept private transactions, dropped once expired.
+type Remover interface {
+	// Remove drops a transaction from the pool, reporting the reason to the event
+	// subscribers, and returns whether it was pooled.
+	Remove(hash common.Hash, reason core.TxPoolEventReason) bool
+}
+
+// Purger is implemented by the subpools able to drop the pooled transactions of
+// a set of accounts, which is required to enforce blacklist updates.
+type Purger interface {
+	// Purge drops the pending and queued transactions for which match returns
+	// true given their sender and recipient, reporting the reason to the event
+	// subscribers, and returns the number of dropped transactions.
+	Purge(match func(from common.Address, to *common.Address) bool, reason core.TxPoolEventReason) int
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"math/big"
 	"sync"
+	"sync/atomic"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
@@ -47,6 +48,9 @@ var (
 	// This is mostly a sanity metric to ensure there's no bug that would make
 	// some subpool hog all the reservations due to mis-accounting.
 	reservationsGaugeName = "txpool/reservations"
+
+	// blacklistedTxMeter counts the transactions refused by the blacklist.
+	blacklistedTxMeter = metrics.NewRegisteredMeter("txpool/blacklisted", nil)
 )
 
 // BlockChain defines the minimal set of methods needed to back a tx pool with
@@ -70,6 +74,10 @@ type TxPool struct {
 	reservations map[common.Address]SubPool // Map with the account to pool reservations
 	reserveLock  sync.Mutex                 // Lock protecting the account reservations
 
+	blacklist atomic.Pointer[Blacklist] // Addresses whose transactions are rejected
+	eventFeed event.Feed                // Event feed of the transactions refused by the blacklist
+	private   *privateTxs               // Transactions never propagated, dropped once expired
+
 	subs event.SubscriptionScope // Subscription scope to unsubscribe all on shutdown
 	quit chan chan error         // Quit channel to tear down the head updater
 	term chan struct{}           // Termination channel to detect a closed pool
@@ -88,6 +96,7 @@ func New(gasTip uint64, chain BlockChain, subpools []SubPool) (*TxPool, error) {
 	pool := &TxPool{
 		subpools:     subpools,
 		reservations: make(map[common.Address]SubPool),
+		private:      newPrivateTxs(),
 		quit:         make(chan chan error),
 		term:         make(chan struct{}),
 		sync:         make(chan chan error),
@@ -169,6 +178,8 @@ func (p *TxPool) Close() error {
 	// Unsubscribe anyone still listening for tx events
 	p.subs.Close()
 
+	p.blacklist.Load().Close()
+
 	if len(errs) > 0 {
 		return fmt.Errorf("subpool close errors: %v", errs)
 	}
@@ -223,6 +234,7 @@ func (p *TxPool) loop(head *types.Header, chain BlockChain) {
 					for _, subpool := range p.subpools {
 						subpool.Reset(oldHead, newHead)
 					}
+					p.expirePrivate(newHead.Number.Uint64())
 					resetDone <- newHead
 				}(oldHead, newHead)
 
@@ -311,6 +323,42 @@ func (p *TxPool) Get(hash common.Hash) *types.Transaction {
 	return nil
 }
 
+// SetBlacklist sets the blacklist checked on transaction admission, the pool
+// takes over its ownership and closes it on shutdown. The pooled transactions
+// touching the blacklist are dropped, now and whenever it's reloaded or extended.
+func (p *TxPool) SetBlacklist(blacklist *Blacklist) {
+	p.blacklist.Store(blacklist)
+	if blacklist != nil {
+		blacklist.setUpdateHook(p.purgeBlacklisted)
+		p.purgeBlacklisted()
+	}
+}
+
+// purgeBlacklisted drops the transactions sent from or to a blacklisted account
+// from the subpools supporting it.
+func (p *TxPool) purgeBlacklisted() {
+	blacklist := p.blacklist.Load()
+	if blacklist == nil {
+		return
+	}
+	match := func(from common.Address, to *common.Address) bool {
+		return blacklist.Check(from, to) != nil
+	}
+	for _, subpool := range p.subpools {
+		if purger, ok := subpool.(Purger); ok {
+			if dropped := purger.Purge(match, core.TxBlacklisted); dropped > 0 {
+				log.Info("Dropped blacklisted transactions", "count", dropped)
+			}
+		}
+	}
+}
+
+// Blacklist returns the blacklist checked on transaction admission, which may
+// be nil.
+func (p *TxPool) Blacklist() *Blacklist {
+	return p.blacklist.Load()
+}
+
 // Add enqueues a batch of transactions into the pool if they are valid. Due
 // to the large transaction churn, add may postpone fully integrating the tx
 // to a later point to batch multiple ones together.
@@ -323,11 +371,26 @@ func (p *TxPool) Add(txs []*types.Transaction, local bool, sync bool) []error {
 	// so we can piece back the returned errors into the original order.
 	txsets := make([][]*types.Transaction, len(p.subpools))
 	splits := make([]int, len(txs))
+	errs := make([]error, len(txs))
 
+	var (
+		blacklist = p.blacklist.Load()
+		refused   []core.TxPoolEvent
+	)
 	for i, tx := range txs {
 		// Mark this transaction belonging to no-subpool
 		splits[i] = -1
 
+		// Reject the transactions touching the blacklist before any subpool
+		if err := blacklist.checkTx(tx); err != nil {
+			// Peers can gossip such txs at will, count them instead of flooding the log
+			log.Trace("Rejected blacklisted transaction", "tx", tx.Hash(), "err", err)
+			blacklistedTxMeter.Mark(1)
+			errs[i] = err
+			refused = append(refused, core.TxPoolEvent{Hash: tx.Hash(), Reason: core.TxBlacklisted})
+			continue
+		}
+
 		// Try to find a subpool that accepts the transaction
 		for j, subpool := range p.subpools {
 			if subpool.Filter(tx) {
@@ -343,20 +406,92 @@ func (p *TxPool) Add(txs []*types.Transaction, local bool, sync bool) []error {
 	for i := 0; i < len(p.subpools); i++ {
 		errsets[i] = p.subpools[i].Add(txsets[i], local, sync)
 	}
-	errs := make([]error, len(txs))
 	for i, split := range splits {
 		// If the transaction was rejected by all subpools, mark it unsupported
 		if split == -1 {
-			errs[i] = core.ErrTxTypeNotSupported
+			if errs[i] == nil {
+				errs[i] = core.ErrTxTypeNotSupported
+			}
 			continue
 		}
 		// Find which subpool handled it and pull in the corresponding error
 		errs[i] = errsets[split][0]
 		errsets[split] = errsets[split][1:]
 	}
+	if len(refused) > 0 {
+		p.eventFeed.Send(refused)
+	}
 	return errs
 }
 
+// AddPrivate adds a batch of transactions to the pool like Add does, marking them
+// private: they are never propagated to the network, only included in the locally
+// built blocks, and dropped from the pool once the expiry block is reached.
+//
+// Private transactions are always added as remote ones, not to be journaled nor
+// reannounced.
+func (p *TxPool) AddPrivate(txs []*types.Transaction, expiry uint64, sync bool) []error {
+	var (
+		errs   = make([]error, len(txs))
+		adds   = make([]*types.Transaction, 0, len(txs))
+		splits = make([]int, 0, len(txs))
+		marked = make([]bool, 0, len(txs))
+	)
+	p.private.adding.Lock()
+	defer p.private.adding.Unlock()
+
+	for i, tx := range txs {
+		if !p.removable(tx) {
+			errs[i] = ErrPrivateTxUnsupported
+			continue
+		}
+		// Mark the transaction before the insertion, so it never gets announced
+		adds = append(adds, tx)
+		splits = append(splits, i)
+		marked = append(marked, p.private.add(tx.Hash(), expiry))
+	}
+	for i, err := range p.Add(adds, false, sync) {
+		if err != nil && marked[i] {
+			p.private.remove(adds[i].Hash())
+		}
+		errs[splits[i]] = err
+	}
+	return errs
+}
+
+// IsPrivate returns whether a transaction was submitted privately and must not
+// be propagated to the network.
+func (p *TxPool) IsPrivate(hash common.Hash) bool {
+	return p.private.contains(hash)
+}
+
+// removable returns whether the transaction is handled by a subpool able to drop
+// it on expiry.
+func (p *TxPool) removable(tx *types.Transaction) bool {
+	for _, subpool := range p.subpools {
+		if subpool.Filter(tx) {
+			_, ok := subpool.(Remover)
+			return ok
+		}
+	}
+	return false
+}
+
+// expirePrivate drops the private transactions which can't be included anymore
+// on top of the given block, and unmarks the ones which left the pool.
+func (p *TxPool) expirePrivate(number uint64) {
+	defer p.private.sweep(p.Has)
+
+	for _, hash := range p.private.expire(number) {
+		for _, subpool := range p.subpools {
+			if remover, ok := subpool.(Remover); ok && remover.Remove(hash, core.TxExpired) {
+				log.Debug("Dropped expired private transaction", "hash", hash)
+				break
+			}
+		}
+	}
+}
+
 // Pending retrieves all currently processable transactions, grouped by origin
 // account and sorted by nonce.
 //
@@ -398,6 +533,20 @@ func (p *TxPool) SubscribeReannoTxsEvent(ch chan<- core.ReannoTxsEvent) event.Su
 	return p.subs.Track(event.JoinSubscriptions(subs...))
 }
 
+// SubscribeTxPoolEvents registers a subscription for the events of transactions
+// leaving, or refused by, any of the subpools or the blacklist.
+func (p *TxPool) SubscribeTxPoolEvents(ch chan<- []core.TxPoolEvent) event.Subscription {
+	subs := make([]event.Subscription, 0, len(p.subpools)+1)
+	for _, subpool := range p.subpools {
+		sub := subpool.SubscribeTxPoolEvents(ch)
+		if sub != nil { // sub will be nil when subpool have been shut down
+			subs = append(subs, sub)
+		}
+	}
+	subs = append(subs, p.eventFeed.Subscribe(ch))
+	return p.subs.Track(event.JoinSubscriptions(subs...))
+}
+
 // Nonce returns the next nonce of an account, with all transactions executable
 // by the pool already applied on top.
 func (p *TxPool) Nonce(addr common.Address) uint64 {
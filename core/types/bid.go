@@ -48,6 +48,11 @@ func (b *BidArgs) ToBid(builder common.Address, signer Signer) (*Bid, error) {
 	unRevertibleHashes := mapset.NewThreadUnsafeSetWithSize[common.Hash](len(b.RawBid.UnRevertible))
 	unRevertibleHashes.Append(b.RawBid.UnRevertible...)
 
+	bundles, err := b.RawBid.bundleSpans(txs, unRevertibleHashes)
+	if err != nil {
+		return nil, err
+	}
+
 	if len(b.PayBidTx) != 0 {
 		var payBidTx = new(Transaction)
 		err = payBidTx.UnmarshalBinary(b.PayBidTx)
@@ -64,11 +69,18 @@ func (b *BidArgs) ToBid(builder common.Address, signer Signer) (*Bid, error) {
 		ParentHash:   b.RawBid.ParentHash,
 		Txs:          txs,
 		UnRevertible: unRevertibleHashes,
+		Bundles:      bundles,
 		GasUsed:      b.RawBid.GasUsed + b.PayBidTxGasUsed,
 		GasFee:       b.RawBid.GasFee,
 		BuilderFee:   b.RawBid.BuilderFee,
 		rawBid:       *b.RawBid,
 	}
+	if len(bundles) != 0 {
+		bid.bundleAt = make(map[int]*BundleSpan, len(bundles))
+		for _, span := range bundles {
+			bid.bundleAt[span.Start] = span
+		}
+	}
 
 	if bid.BuilderFee == nil {
 		bid.BuilderFee = big.NewInt(0)
@@ -87,9 +99,79 @@ type RawBid struct {
 	GasFee       *big.Int        `json:"gasFee"`
 	BuilderFee   *big.Int        `json:"builderFee"`
 
+	// Bundles is optional in rlp, so that the hash of a bid without bundles is unchanged
+	Bundles []BidBundle `json:"bundles,omitempty" rlp:"optional"`
+
 	hash atomic.Value
 }
 
+// BidBundle groups consecutive txs of a bid which are included all together or not at all.
+// A failed or reverted tx in the bundle drops the whole bundle from the block, except for
+// the txs in DroppingTxs which are tolerated to fail and are only dropped themselves.
+type BidBundle struct {
+	Txs         []common.Hash `json:"txs"`
+	DroppingTxs []common.Hash `json:"droppingTxs,omitempty"`
+}
+
+// BundleSpan locates a validated bundle in the txs of a bid.
+type BundleSpan struct {
+	Start    int // index of the first tx of the bundle
+	End      int // index after the last tx of the bundle
+	Dropping mapset.Set[common.Hash]
+}
+
+// bundleSpans validates the bundles against the decoded txs of the bid and locates them.
+func (b *RawBid) bundleSpans(txs []*Transaction, unRevertible mapset.Set[common.Hash]) ([]*BundleSpan, error) {
+	if len(b.Bundles) == 0 {
+		return nil, nil
+	}
+	index := make(map[common.Hash]int, len(txs))
+	for i, tx := range txs {
+		index[tx.Hash()] = i
+	}
+	var (
+		spans   = make([]*BundleSpan, 0, len(b.Bundles))
+		bundled = make(map[common.Hash]struct{})
+	)
+	for i, bundle := range b.Bundles {
+		if len(bundle.Txs) == 0 {
+			return nil, NewInvalidBidError(fmt.Sprintf("bundle %d is empty", i))
+		}
+		start, ok := index[bundle.Txs[0]]
+		if !ok {
+			return nil, NewInvalidBidError(fmt.Sprintf("bundle %d contains unknown tx %v", i, bundle.Txs[0]))
+		}
+		for j, hash := range bundle.Txs {
+			if pos, ok := index[hash]; !ok {
+				return nil, NewInvalidBidError(fmt.Sprintf("bundle %d contains unknown tx %v", i, hash))
+			} else if pos != start+j {
+				return nil, NewInvalidBidError(fmt.Sprintf("txs of bundle %d are not consecutive in the bid", i))
+			}
+			if _, ok := bundled[hash]; ok {
+				return nil, NewInvalidBidError(fmt.Sprintf("tx %v is in more than one bundle", hash))
+			}
+			bundled[hash] = struct{}{}
+		}
+		span := &BundleSpan{
+			Start:    start,
+			End:      start + len(bundle.Txs),
+			Dropping: mapset.NewThreadUnsafeSetWithSize[common.Hash](len(bundle.DroppingTxs)),
+		}
+		members := mapset.NewThreadUnsafeSet[common.Hash](bundle.Txs...)
+		for _, hash := range bundle.DroppingTxs {
+			if !members.Contains(hash) {
+				return nil, NewInvalidBidError(fmt.Sprintf("dropping tx %v is not in bundle %d", hash, i))
+			}
+			if unRevertible.Contains(hash) {
+				return nil, NewInvalidBidError(fmt.Sprintf("dropping tx %v of bundle %d is unRevertible", hash, i))
+			}
+			span.Dropping.Add(hash)
+		}
+		spans = append(spans, span)
+	}
+	return spans, nil
+}
+
 func (b *RawBid) DecodeTxs(signer Signer) ([]*Transaction, error) {
 	if len(b.Txs) == 0 {
 		return []*Transaction{}, nil
@@ -169,11 +251,13 @@ type Bid struct {
 	ParentHash   common.Hash
 	Txs          Transactions
 	UnRevertible mapset.Set[common.Hash]
+	Bundles      []*BundleSpan
 	GasUsed      uint64
 	GasFee       *big.Int
 	BuilderFee   *big.Int
 
-	rawBid RawBid
+	rawBid   RawBid
+	bundleAt map[int]*BundleSpan // bundles indexed by their first tx
 }
 
 // Hash returns the bid hash.
@@ -181,6 +265,11 @@ func (b *Bid) Hash() common.Hash {
 	return b.rawBid.Hash()
 }
 
+// BundleAt returns the bundle starting at the given tx index, nil if there is none.
+func (b *Bid) BundleAt(index int) *BundleSpan {
+	return b.bundleAt[index]
+}
+
 // BidIssue represents a bid issue.
 type BidIssue struct {
 	Validator common.Address
@@ -197,3 +286,20 @@ type MevParams struct {
 	BuilderFeeCeil        *big.Int
 	Version               string
 }
+
+// BuilderStats represents the health and latency statistics the validator keeps for a builder.
+type BuilderStats struct {
+	Builder        common.Address    `json:"builder"`
+	BidsReceived   uint64            `json:"bidsReceived"`
+	BidsTooLate    uint64            `json:"bidsTooLate"`
+	SimSuccess     uint64            `json:"simSuccess"`
+	SimFailure     uint64            `json:"simFailure"`
+	BidsWon        uint64            `json:"bidsWon"`
+	RPCErrors      uint64            `json:"rpcErrors"`
+	FailureReasons map[string]uint64 `json:"failureReasons"`
+	AvgArrivalLead time.Duration     `json:"avgArrivalLead"` // average time left before bidBetterBefore when bids arrived
+	SuccessRate    float64           `json:"successRate"`
+	Score          float64           `json:"score"`
+	Suspended      bool              `json:"suspended"`
+	SuspendedUntil time.Time         `json:"suspendedUntil"`
+}
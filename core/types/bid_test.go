@@ -0,0 +1,65 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	mapset "github.com/deckarep/golang-set/v2"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestRawBidHashWithoutBundles(t *testing.T) {
+	type legacyRawBid struct {
+		BlockNumber  uint64
+		ParentHash   common.Hash
+		Txs          [][]byte
+		UnRevertible []common.Hash
+		GasUsed      uint64
+		GasFee       *big.Int
+		BuilderFee   *big.Int
+	}
+	bid := &RawBid{BlockNumber: 1, ParentHash: common.Hash{0x01}, GasUsed: 21000, GasFee: big.NewInt(1), BuilderFee: big.NewInt(0)}
+	legacy := &legacyRawBid{BlockNumber: 1, ParentHash: common.Hash{0x01}, Txs: [][]byte{}, UnRevertible: []common.Hash{}, GasUsed: 21000, GasFee: big.NewInt(1), BuilderFee: big.NewInt(0)}
+	if have, want := bid.Hash(), rlpHash(legacy); have != want {
+		t.Fatalf("bid hash changed: have %x, want %x", have, want)
+	}
+}
+
+func TestRawBidBundleSpans(t *testing.T) {
+	txs := make([]*Transaction, 4)
+	for i := range txs {
+		txs[i] = NewTransaction(uint64(i), common.Address{0x01}, big.NewInt(1), 21000, big.NewInt(1), nil)
+	}
+	hash := func(i int) common.Hash { return txs[i].Hash() }
+
+	tests := []struct {
+		bundles      []BidBundle
+		unRevertible []common.Hash
+		fail         bool
+	}{
+		{bundles: []BidBundle{{Txs: []common.Hash{hash(1), hash(2)}, DroppingTxs: []common.Hash{hash(2)}}}},
+		{bundles: []BidBundle{{}}, fail: true},
+		{bundles: []BidBundle{{Txs: []common.Hash{hash(0), hash(2)}}}, fail: true},
+		{bundles: []BidBundle{{Txs: []common.Hash{common.Hash{0xff}}}}, fail: true},
+		{bundles: []BidBundle{{Txs: []common.Hash{hash(0), hash(1)}}, {Txs: []common.Hash{hash(1)}}}, fail: true},
+		{bundles: []BidBundle{{Txs: []common.Hash{hash(0)}, DroppingTxs: []common.Hash{hash(1)}}}, fail: true},
+		{bundles: []BidBundle{{Txs: []common.Hash{hash(0)}, DroppingTxs: []common.Hash{hash(0)}}}, unRevertible: []common.Hash{hash(0)}, fail: true},
+	}
+	for i, tt := range tests {
+		bid := &RawBid{Bundles: tt.bundles}
+		spans, err := bid.bundleSpans(txs, mapset.NewThreadUnsafeSet[common.Hash](tt.unRevertible...))
+		if tt.fail {
+			if err == nil {
+				t.Errorf("test %d: expected error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		if len(spans) != 1 || spans[0].Start != 1 || spans[0].End != 3 || !spans[0].Dropping.Contains(hash(2)) {
+			t.Fatalf("test %d: span mismatch: %+v", i, spans[0])
+		}
+	}
+}
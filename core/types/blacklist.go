@@ -3,6 +3,8 @@ package types
 import "github.com/ethereum/go-ethereum/common"
 
 // This is introduced because of the Tendermint IAVL Merkle Proof verification exploitation.
+// It's enforced by the consensus since the Nano fork, the configurable txpool.Blacklist
+// always includes these addresses.
 var NanoBlackList = []common.Address{
 	common.HexToAddress("0x489A8756C18C0b8B24EC2a2b9FF3D4d447F79BEc"),
 	common.HexToAddress("0xFd6042Df3D74ce9959922FeC559d7995F3933c55"),
@@ -0,0 +1,113 @@
+package types
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DoubleSignEvidence is a pair of different headers signed by the same validator at the same height,
+// which can be submitted to SlashIndicator.submitDoubleSignEvidence.
+type DoubleSignEvidence struct {
+	Header1    *Header
+	Header2    *Header
+	DetectedAt uint64 // unix time the evidence was detected
+
+	// set once the evidence was submitted, cleared if the submission tx is lost
+	TxHash         common.Hash
+	SubmittedAt    uint64
+	SubmittedBlock uint64 `rlp:"optional"` // head number when the tx was submitted
+	IncludedBlock  uint64 `rlp:"optional"` // number of the block including the tx
+	Reverts        uint64 `rlp:"optional"` // number of reverted submission txs
+}
+
+// NewDoubleSignEvidence creates the evidence of two headers, ordered by hash so that
+// the same pair always results in the same evidence.
+func NewDoubleSignEvidence(h1, h2 *Header, detectedAt uint64) *DoubleSignEvidence {
+	hash1, hash2 := h1.Hash(), h2.Hash()
+	if bytes.Compare(hash1[:], hash2[:]) > 0 {
+		h1, h2 = h2, h1
+	}
+	return &DoubleSignEvidence{Header1: h1, Header2: h2, DetectedAt: detectedAt}
+}
+
+// Hash identifies the evidence by the hashes of its headers.
+func (e *DoubleSignEvidence) Hash() common.Hash {
+	hash1, hash2 := e.Header1.Hash(), e.Header2.Hash()
+	return crypto.Keccak256Hash(hash1[:], hash2[:])
+}
+
+// Validator returns the validator which signed both headers.
+func (e *DoubleSignEvidence) Validator() common.Address {
+	return e.Header1.Coinbase
+}
+
+// Number returns the height of the headers.
+func (e *DoubleSignEvidence) Number() uint64 {
+	return e.Header1.Number.Uint64()
+}
+
+// Submitted returns whether the evidence was already submitted.
+func (e *DoubleSignEvidence) Submitted() bool {
+	return e.TxHash != (common.Hash{})
+}
+
+// Included returns whether the submission tx of the evidence was included successfully.
+func (e *DoubleSignEvidence) Included() bool {
+	return e.IncludedBlock != 0
+}
+
+// FinalityViolationEvidence is a pair of conflicting votes signed by the same validator,
+// which can be submitted to SlashIndicator.submitFinalityViolationEvidence.
+type FinalityViolationEvidence struct {
+	VoteA      *VoteEnvelope
+	VoteB      *VoteEnvelope
+	DetectedAt uint64 // unix time the evidence was detected
+
+	// set once the evidence was submitted, cleared if the submission tx is lost
+	TxHash         common.Hash
+	SubmittedAt    uint64
+	SubmittedBlock uint64 `rlp:"optional"` // head number when the tx was submitted
+	IncludedBlock  uint64 `rlp:"optional"` // number of the block including the tx
+	Reverts        uint64 `rlp:"optional"` // number of reverted submission txs
+}
+
+// NewFinalityViolationEvidence creates the evidence of two votes, ordered by hash so that
+// the same pair always results in the same evidence.
+func NewFinalityViolationEvidence(vote1, vote2 *VoteEnvelope, detectedAt uint64) *FinalityViolationEvidence {
+	hash1, hash2 := vote1.Hash(), vote2.Hash()
+	if bytes.Compare(hash1[:], hash2[:]) > 0 {
+		vote1, vote2 = vote2, vote1
+	}
+	return &FinalityViolationEvidence{VoteA: vote1, VoteB: vote2, DetectedAt: detectedAt}
+}
+
+// Hash identifies the evidence by the hashes of its votes.
+func (e *FinalityViolationEvidence) Hash() common.Hash {
+	hash1, hash2 := e.VoteA.Hash(), e.VoteB.Hash()
+	return crypto.Keccak256Hash(hash1[:], hash2[:])
+}
+
+// VoteAddress returns the BLS public key of the validator which signed both votes.
+func (e *FinalityViolationEvidence) VoteAddress() BLSPublicKey {
+	return e.VoteA.VoteAddress
+}
+
+// Number returns the higher target number of the votes.
+func (e *FinalityViolationEvidence) Number() uint64 {
+	if e.VoteA.Data.TargetNumber > e.VoteB.Data.TargetNumber {
+		return e.VoteA.Data.TargetNumber
+	}
+	return e.VoteB.Data.TargetNumber
+}
+
+// Submitted returns whether the evidence was already submitted.
+func (e *FinalityViolationEvidence) Submitted() bool {
+	return e.TxHash != (common.Hash{})
+}
+
+// Included returns whether the submission tx of the evidence was included successfully.
+func (e *FinalityViolationEvidence) Included() bool {
+	return e.IncludedBlock != 0
+}
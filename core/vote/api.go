@@ -0,0 +1,130 @@
+package vote
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// API exposes the votes of the vote pool and the local vote journal, which helps
+// to find out why the attestations fail to reach the quorum.
+type API struct {
+	pool    *VotePool
+	manager *VoteManager // nil if the node doesn't vote
+}
+
+// NewAPI creates the vote API, the manager is optional.
+func NewAPI(pool *VotePool, manager *VoteManager) *API {
+	return &API{pool: pool, manager: manager}
+}
+
+// RPCVote is the RPC representation of a vote.
+type RPCVote struct {
+	Hash         common.Hash    `json:"hash"`
+	VoteAddress  hexutil.Bytes  `json:"voteAddress"`
+	Signature    hexutil.Bytes  `json:"signature"`
+	SourceNumber hexutil.Uint64 `json:"sourceNumber"`
+	SourceHash   common.Hash    `json:"sourceHash"`
+	TargetNumber hexutil.Uint64 `json:"targetNumber"`
+	TargetHash   common.Hash    `json:"targetHash"`
+}
+
+func newRPCVote(vote *types.VoteEnvelope) *RPCVote {
+	return &RPCVote{
+		Hash:         vote.Hash(),
+		VoteAddress:  common.CopyBytes(vote.VoteAddress[:]),
+		Signature:    common.CopyBytes(vote.Signature[:]),
+		SourceNumber: hexutil.Uint64(vote.Data.SourceNumber),
+		SourceHash:   vote.Data.SourceHash,
+		TargetNumber: hexutil.Uint64(vote.Data.TargetNumber),
+		TargetHash:   vote.Data.TargetHash,
+	}
+}
+
+// BlockVotes are the votes in the pool for a single block.
+type BlockVotes struct {
+	BlockHash common.Hash `json:"blockHash"`
+	Future    bool        `json:"future"` // whether the block is unknown yet
+	Votes     []*RPCVote  `json:"votes"`
+}
+
+// GetVotes returns the votes in the pool for the given block.
+func (api *API) GetVotes(blockHash common.Hash) *BlockVotes {
+	votes, future := api.pool.VotesByBlockHash(blockHash)
+	result := &BlockVotes{
+		BlockHash: blockHash,
+		Future:    future,
+		Votes:     make([]*RPCVote, 0, len(votes)),
+	}
+	for _, vote := range votes {
+		result.Votes = append(result.Votes, newRPCVote(vote))
+	}
+	return result
+}
+
+// RPCVoteBox is the RPC representation of the votes summary for a single block.
+type RPCVoteBox struct {
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	BlockHash   common.Hash    `json:"blockHash"`
+	Votes       int            `json:"votes"`
+}
+
+// PoolStatus is the summary of the vote pool.
+type PoolStatus struct {
+	CurrentVotes  int           `json:"currentVotes"`
+	FutureVotes   int           `json:"futureVotes"`
+	ReceivedVotes int           `json:"receivedVotes"`
+	Current       []*RPCVoteBox `json:"current"`
+	Future        []*RPCVoteBox `json:"future"`
+}
+
+// GetPoolStatus returns the number of votes in the pool for each block.
+func (api *API) GetPoolStatus() *PoolStatus {
+	cur, future, received := api.pool.Status()
+
+	status := &PoolStatus{ReceivedVotes: received}
+	status.Current, status.CurrentVotes = newRPCVoteBoxes(cur)
+	status.Future, status.FutureVotes = newRPCVoteBoxes(future)
+	return status
+}
+
+func newRPCVoteBoxes(boxes []VoteBoxStatus) ([]*RPCVoteBox, int) {
+	var (
+		result = make([]*RPCVoteBox, 0, len(boxes))
+		votes  int
+	)
+	for _, box := range boxes {
+		result = append(result, &RPCVoteBox{
+			BlockNumber: hexutil.Uint64(box.BlockNumber),
+			BlockHash:   box.BlockHash,
+			Votes:       box.Votes,
+		})
+		votes += box.Votes
+	}
+	return result, votes
+}
+
+// GetLocalVoteHistory returns the votes signed by the local validator for the
+// given number of most recent blocks, as recorded in the vote journal.
+func (api *API) GetLocalVoteHistory(blockRange uint64) ([]*RPCVote, error) {
+	if api.manager == nil {
+		return nil, errors.New("voting is not enabled")
+	}
+	votes, err := api.manager.journal.ReadVotes()
+	if err != nil {
+		return nil, err
+	}
+	var lowest uint64
+	if head := api.pool.chain.CurrentBlock().Number.Uint64(); head >= blockRange {
+		lowest = head - blockRange + 1
+	}
+	result := make([]*RPCVote, 0, len(votes))
+	for _, vote := range votes {
+		if vote.Data.TargetNumber >= lowest {
+			result = append(result, newRPCVote(vote))
+		}
+	}
+	return result, nil
+}
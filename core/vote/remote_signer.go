@@ -0,0 +1,377 @@
+package vote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/crypto/bls"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// SlashingProtectionDir is the directory in the datadir where the votes signed
+// by the remote signer are persisted.
+const SlashingProtectionDir = "voteprotection"
+
+// remoteSigner signs the votes through a Web3Signer compatible remote signer
+// holding the BLS key, using the eth2 signing API:
+//
+//	GET  <url>/api/v1/eth2/publicKeys               -> ["0x<BLS public key>", ...]
+//	POST <url>/api/v1/eth2/sign/<BLS public key>    -> {"signature": "0x<BLS signature>"}
+//
+// A vote is sent as an ATTESTATION signing request whose source and target
+// checkpoints are the ones of the vote, so that the signer applies its attestation
+// slashing protection to the votes. Votes are signed over the plain hash of the
+// vote data, without the fork domain of the eth2 signing roots, so the signer must
+// sign the given signingRoot as is. The returned signatures are always verified.
+type remoteSigner struct {
+	url    string
+	client *http.Client
+}
+
+// remoteVote is the vote data persisted by the slashing protection.
+type remoteVote struct {
+	SourceNumber hexutil.Uint64 `json:"sourceNumber"`
+	SourceHash   common.Hash    `json:"sourceHash"`
+	TargetNumber hexutil.Uint64 `json:"targetNumber"`
+	TargetHash   common.Hash    `json:"targetHash"`
+}
+
+// remoteCheckpoint is an eth2 checkpoint, a vote source or target.
+type remoteCheckpoint struct {
+	Epoch string      `json:"epoch"`
+	Root  common.Hash `json:"root"`
+}
+
+// remoteAttestation is the eth2 attestation data a vote is mapped to.
+type remoteAttestation struct {
+	Slot            string           `json:"slot"`
+	Index           string           `json:"index"`
+	BeaconBlockRoot common.Hash      `json:"beacon_block_root"`
+	Source          remoteCheckpoint `json:"source"`
+	Target          remoteCheckpoint `json:"target"`
+}
+
+// remoteFork is the eth2 fork info of a signing request, BSC has none.
+type remoteFork struct {
+	Fork struct {
+		PreviousVersion hexutil.Bytes `json:"previous_version"`
+		CurrentVersion  hexutil.Bytes `json:"current_version"`
+		Epoch           string        `json:"epoch"`
+	} `json:"fork"`
+	GenesisValidatorsRoot common.Hash `json:"genesis_validators_root"`
+}
+
+// remoteSignRequest is the body of an eth2 signing request.
+type remoteSignRequest struct {
+	Type        string            `json:"type"`
+	ForkInfo    remoteFork        `json:"fork_info"`
+	SigningRoot common.Hash       `json:"signingRoot"`
+	Attestation remoteAttestation `json:"attestation"`
+}
+
+// remoteSignResponse is the JSON response of a signing request.
+type remoteSignResponse struct {
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// NewRemoteVoteSigner creates a vote signer backed by a remote signer. If no key
+// is given, the remote signer must hold exactly one. The votes signed by the key
+// are persisted into the given slashing protection directory.
+func NewRemoteVoteSigner(url string, key string, protectionDir string) (*VoteSigner, error) {
+	remote := &remoteSigner{
+		url:    strings.TrimSuffix(url, "/"),
+		client: &http.Client{Timeout: voteSignerTimeout},
+	}
+	keys, err := remote.publicKeys()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch remote public keys")
+	}
+	signer := &VoteSigner{remote: remote}
+	switch {
+	case key != "":
+		pubKey, err := hexutil.Decode(key)
+		if err != nil || len(pubKey) != types.BLSPublicKeyLength {
+			return nil, fmt.Errorf("invalid BLS public key %q", key)
+		}
+		copy(signer.PubKey[:], pubKey)
+		found := false
+		for _, k := range keys {
+			if k == signer.PubKey {
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("BLS public key %s is not held by the remote signer", key)
+		}
+	case len(keys) == 1:
+		signer.PubKey = keys[0]
+	default:
+		return nil, fmt.Errorf("remote signer holds %d keys, the key to vote with must be configured", len(keys))
+	}
+	if _, err := bls.PublicKeyFromBytes(signer.PubKey[:]); err != nil {
+		return nil, errors.Wrap(err, "convert public key from bytes to bls failed")
+	}
+	if signer.protection, err = newSlashingProtection(filepath.Join(protectionDir, hexutil.Encode(signer.PubKey[:])+".json")); err != nil {
+		return nil, errors.Wrap(err, "could not load slashing protection")
+	}
+	log.Info("Connected to remote BLS signer", "url", remote.url, "key", common.Bytes2Hex(signer.PubKey[:]))
+	return signer, nil
+}
+
+// publicKeys returns the BLS keys held by the remote signer.
+func (r *remoteSigner) publicKeys() ([][48]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), voteSignerTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url+"/api/v1/eth2/publicKeys", nil)
+	if err != nil {
+		return nil, err
+	}
+	var keys []hexutil.Bytes
+	if err := r.do(req, &keys); err != nil {
+		return nil, err
+	}
+	result := make([][48]byte, 0, len(keys))
+	for _, key := range keys {
+		if len(key) != types.BLSPublicKeyLength {
+			return nil, fmt.Errorf("invalid BLS public key %x", key)
+		}
+		result = append(result, [48]byte(key))
+	}
+	return result, nil
+}
+
+// sign requests the signature of the vote data with the given key.
+func (r *remoteSigner) sign(pubKey [48]byte, data *types.VoteData) ([]byte, error) {
+	body := &remoteSignRequest{
+		Type:        "ATTESTATION",
+		SigningRoot: data.Hash(),
+		Attestation: remoteAttestation{
+			Slot:            strconv.FormatUint(data.TargetNumber, 10),
+			Index:           "0",
+			BeaconBlockRoot: data.TargetHash,
+			Source:          remoteCheckpoint{Epoch: strconv.FormatUint(data.SourceNumber, 10), Root: data.SourceHash},
+			Target:          remoteCheckpoint{Epoch: strconv.FormatUint(data.TargetNumber, 10), Root: data.TargetHash},
+		},
+	}
+	body.ForkInfo.Fork.PreviousVersion = make([]byte, 4)
+	body.ForkInfo.Fork.CurrentVersion = make([]byte, 4)
+	body.ForkInfo.Fork.Epoch = "0"
+
+	blob, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), voteSignerTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url+"/api/v1/eth2/sign/"+hexutil.Encode(pubKey[:]), bytes.NewReader(blob))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	var resp remoteSignResponse
+	if err := r.do(req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Signature, nil
+}
+
+// do sends the request and decodes the JSON response.
+func (r *remoteSigner) do(req *http.Request, result interface{}) error {
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	blob, err := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("remote signer returned %s: %s", resp.Status, strings.TrimSpace(string(blob)))
+	}
+	// Web3Signer answers the signing requests in plain text unless told otherwise,
+	// the content type isn't trusted since servers omitting it get it sniffed
+	if sig, ok := result.(*remoteSignResponse); ok {
+		if text := strings.TrimSpace(string(blob)); !strings.HasPrefix(text, "{") {
+			sig.Signature, err = hexutil.Decode(text)
+			return err
+		}
+	}
+	return json.Unmarshal(blob, result)
+}
+
+// signRemote signs the vote with the remote signer, refusing to sign votes which
+// are slashable together with a vote signed before.
+func (signer *VoteSigner) signRemote(vote *types.VoteEnvelope) error {
+	if err := signer.protection.check(vote.Data); err != nil {
+		return err
+	}
+	signature, err := signer.remote.sign(signer.PubKey, vote.Data)
+	if err != nil {
+		return err
+	}
+	blsPubKey, err := bls.PublicKeyFromBytes(signer.PubKey[:])
+	if err != nil {
+		return errors.Wrap(err, "convert public key from bytes to bls failed")
+	}
+	sig, err := bls.SignatureFromBytes(signature)
+	if err != nil {
+		return errors.Wrap(err, "invalid signature")
+	}
+	voteDataHash := vote.Data.Hash()
+	if !sig.Verify(blsPubKey, voteDataHash[:]) {
+		return errors.New("remote signer returned an invalid signature")
+	}
+	// Never release a signature which the slashing protection could forget
+	signer.protection.record(vote.Data)
+	if err := signer.protection.save(); err != nil {
+		return errors.Wrap(err, "could not persist slashing protection")
+	}
+	copy(vote.VoteAddress[:], signer.PubKey[:])
+	copy(vote.Signature[:], signature)
+	return nil
+}
+
+// slashingProtection tracks the recently signed votes to refuse signing double
+// votes and surround votes. The votes are persisted into a file, so that they are
+// protected across restarts even if the vote journal lost them.
+type slashingProtection struct {
+	path          string
+	votes         map[uint64]*types.VoteData // signed votes by target number
+	highestTarget uint64
+	lock          sync.Mutex
+}
+
+// newSlashingProtection creates the slashing protection persisted at the given
+// path, loading the votes signed before if any.
+func newSlashingProtection(path string) (*slashingProtection, error) {
+	p := &slashingProtection{path: path, votes: make(map[uint64]*types.VoteData)}
+
+	blob, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var votes []*remoteVote
+	if err := json.Unmarshal(blob, &votes); err != nil {
+		return nil, fmt.Errorf("malformed slashing protection %s: %v", path, err)
+	}
+	for _, vote := range votes {
+		p.record(&types.VoteData{
+			SourceNumber: uint64(vote.SourceNumber),
+			SourceHash:   vote.SourceHash,
+			TargetNumber: uint64(vote.TargetNumber),
+			TargetHash:   vote.TargetHash,
+		})
+	}
+	log.Info("Loaded vote slashing protection", "path", path, "votes", len(p.votes), "highestTarget", p.highestTarget)
+	return p, nil
+}
+
+// save writes the recorded votes into the file of the slashing protection,
+// replacing it atomically.
+func (p *slashingProtection) save() error {
+	p.lock.Lock()
+	votes := make([]*remoteVote, 0, len(p.votes))
+	for _, data := range p.votes {
+		votes = append(votes, &remoteVote{
+			SourceNumber: hexutil.Uint64(data.SourceNumber),
+			SourceHash:   data.SourceHash,
+			TargetNumber: hexutil.Uint64(data.TargetNumber),
+			TargetHash:   data.TargetHash,
+		})
+	}
+	p.lock.Unlock()
+
+	sort.Slice(votes, func(i, j int) bool { return votes[i].TargetNumber < votes[j].TargetNumber })
+	blob, err := json.Marshal(votes)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p.path), 0700); err != nil {
+		return err
+	}
+	tmp := p.path + ".tmp"
+	if err := os.WriteFile(tmp, blob, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p.path)
+}
+
+// check returns an error if signing the vote could get the validator slashed.
+func (p *slashingProtection) check(data *types.VoteData) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.highestTarget > maliciousVoteSlashScope && data.TargetNumber <= p.highestTarget-maliciousVoteSlashScope {
+		return fmt.Errorf("vote target %d too old, highest signed target is %d", data.TargetNumber, p.highestTarget)
+	}
+	for _, signed := range p.votes {
+		if signed.Hash() == data.Hash() {
+			return nil
+		}
+	}
+	for _, signed := range p.votes {
+		if reason := conflict(signed, data); reason != "" {
+			return fmt.Errorf("refusing to sign %s with source %d target %d", reason, signed.SourceNumber, signed.TargetNumber)
+		}
+	}
+	return nil
+}
+
+// record adds a signed vote, dropping the ones too old to be slashed.
+func (p *slashingProtection) record(data *types.VoteData) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if _, ok := p.votes[data.TargetNumber]; !ok {
+		p.votes[data.TargetNumber] = data
+	}
+	if data.TargetNumber > p.highestTarget {
+		p.highestTarget = data.TargetNumber
+	}
+	for target := range p.votes {
+		if p.highestTarget > maliciousVoteSlashScope && target <= p.highestTarget-maliciousVoteSlashScope {
+			delete(p.votes, target)
+		}
+	}
+}
+
+// recordVotes feeds the slashing protection with votes signed before, like the
+// ones of the vote journal.
+func (signer *VoteSigner) recordVotes(votes []*types.VoteEnvelope) {
+	if signer.protection == nil {
+		return
+	}
+	for _, vote := range votes {
+		if vote.Data != nil && vote.VoteAddress == signer.PubKey {
+			signer.protection.record(vote.Data)
+		}
+	}
+	if err := signer.protection.save(); err != nil {
+		log.Warn("Failed to persist vote slashing protection", "err", err)
+	}
+}
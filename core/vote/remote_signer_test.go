@@ -0,0 +1,179 @@
+package vote
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/crypto/bls"
+	"github.com/prysmaticlabs/prysm/v5/crypto/bls/common"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// mockRemoteSigner is an in-process remote signer serving the eth2 signing API of Web3Signer.
+type mockRemoteSigner struct {
+	keys  map[string]common.SecretKey
+	forge common.SecretKey // key signing instead of the requested one, if set
+	plain bool             // answer the signing requests in plain text
+}
+
+func newMockRemoteSigner(t *testing.T, keys ...common.SecretKey) (*mockRemoteSigner, *httptest.Server) {
+	mock := &mockRemoteSigner{keys: make(map[string]common.SecretKey)}
+	for _, key := range keys {
+		mock.keys[hexutil.Encode(key.PublicKey().Marshal())] = key
+	}
+	server := httptest.NewServer(mock)
+	t.Cleanup(server.Close)
+	return mock, server
+}
+
+func (m *mockRemoteSigner) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v1/eth2/publicKeys":
+		keys := make([]string, 0, len(m.keys))
+		for key := range m.keys {
+			keys = append(keys, key)
+		}
+		json.NewEncoder(w).Encode(keys)
+
+	case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/api/v1/eth2/sign/"):
+		key, ok := m.keys[strings.TrimPrefix(r.URL.Path, "/api/v1/eth2/sign/")]
+		if !ok {
+			http.Error(w, "key not found", http.StatusNotFound)
+			return
+		}
+		req := new(remoteSignRequest)
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil || req.Type != "ATTESTATION" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		source, err1 := strconv.ParseUint(req.Attestation.Source.Epoch, 10, 64)
+		target, err2 := strconv.ParseUint(req.Attestation.Target.Epoch, 10, 64)
+		if err1 != nil || err2 != nil {
+			http.Error(w, "invalid epoch", http.StatusBadRequest)
+			return
+		}
+		data := &types.VoteData{
+			SourceNumber: source,
+			SourceHash:   req.Attestation.Source.Root,
+			TargetNumber: target,
+			TargetHash:   req.Attestation.Target.Root,
+		}
+		if data.Hash() != req.SigningRoot {
+			http.Error(w, "signing root mismatch", http.StatusBadRequest)
+			return
+		}
+		if m.forge != nil {
+			key = m.forge
+		}
+		signature := key.Sign(req.SigningRoot[:]).Marshal()
+		if m.plain {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			w.Write([]byte(hexutil.Encode(signature)))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(&remoteSignResponse{Signature: signature})
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestRemoteVoteSigner(t *testing.T) {
+	key1, _ := bls.RandKey()
+	key2, _ := bls.RandKey()
+	dir := t.TempDir()
+
+	// The key must be configured if the remote signer holds several of them
+	mock, server := newMockRemoteSigner(t, key1, key2)
+	if _, err := NewRemoteVoteSigner(server.URL, "", dir); err == nil {
+		t.Fatal("ambiguous remote key accepted")
+	}
+	if _, err := NewRemoteVoteSigner(server.URL, hexutil.Encode(make([]byte, 48)), dir); err == nil {
+		t.Fatal("unknown remote key accepted")
+	}
+	signer, err := NewRemoteVoteSigner(server.URL, hexutil.Encode(key2.PublicKey().Marshal()), dir)
+	if err != nil {
+		t.Fatalf("failed to create remote signer: %v", err)
+	}
+	newVote := func(source, target uint64, hash byte) *types.VoteEnvelope {
+		return &types.VoteEnvelope{Data: &types.VoteData{
+			SourceNumber: source,
+			TargetNumber: target,
+			TargetHash:   gethcommon.Hash{hash},
+		}}
+	}
+	vote := newVote(9, 10, 1)
+	if err := signer.SignVote(vote); err != nil {
+		t.Fatalf("failed to sign vote: %v", err)
+	}
+	if err := vote.Verify(); err != nil {
+		t.Fatalf("invalid remote signature: %v", err)
+	}
+	if vote.VoteAddress != types.BLSPublicKey(key2.PublicKey().Marshal()) {
+		t.Fatal("vote signed by wrong key")
+	}
+
+	// Slashable votes must be refused before reaching the remote signer
+	if err := signer.SignVote(newVote(9, 10, 1)); err != nil {
+		t.Fatalf("failed to sign the same vote again: %v", err)
+	}
+	if err := signer.SignVote(newVote(9, 10, 2)); err == nil {
+		t.Fatal("double vote signed")
+	}
+	// Signatures answered in plain text are accepted as well
+	mock.plain = true
+	if err := signer.SignVote(newVote(10, 12, 3)); err != nil {
+		t.Fatalf("failed to sign vote: %v", err)
+	}
+	if err := signer.SignVote(newVote(8, 13, 4)); err == nil {
+		t.Fatal("surround vote signed")
+	}
+
+	// Votes signed before a restart must be protected, even without the journal
+	signer, err = NewRemoteVoteSigner(server.URL, hexutil.Encode(key2.PublicKey().Marshal()), dir)
+	if err != nil {
+		t.Fatalf("failed to reopen remote signer: %v", err)
+	}
+	if err := signer.SignVote(newVote(10, 12, 5)); err == nil {
+		t.Fatal("double vote of a persisted vote signed")
+	}
+	if err := signer.SignVote(newVote(8, 13, 5)); err == nil {
+		t.Fatal("surround vote of a persisted vote signed")
+	}
+	// As well as the ones of the journal
+	signer, _ = NewRemoteVoteSigner(server.URL, hexutil.Encode(key2.PublicKey().Marshal()), t.TempDir())
+	signer.recordVotes([]*types.VoteEnvelope{vote})
+	if err := signer.SignVote(newVote(9, 10, 5)); err == nil {
+		t.Fatal("double vote of a journaled vote signed")
+	}
+}
+
+func TestRemoteVoteSignerInvalidSignature(t *testing.T) {
+	key1, _ := bls.RandKey()
+	key2, _ := bls.RandKey()
+	dir := t.TempDir()
+
+	// Signatures made with another key must be rejected
+	mock, server := newMockRemoteSigner(t, key1)
+	signer, err := NewRemoteVoteSigner(server.URL, "", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mock.forge = key2
+	if err := signer.SignVote(&types.VoteEnvelope{Data: &types.VoteData{TargetNumber: 1}}); err == nil {
+		t.Fatal("invalid remote signature accepted")
+	}
+	// Rejected votes must not count for the slashing protection
+	mock.forge = nil
+	if err := signer.SignVote(&types.VoteEnvelope{Data: &types.VoteData{TargetNumber: 1, TargetHash: gethcommon.Hash{1}}}); err != nil {
+		t.Fatalf("failed to sign vote: %v", err)
+	}
+}
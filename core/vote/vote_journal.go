@@ -2,6 +2,8 @@ package vote
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/tidwall/wal"
@@ -22,11 +24,22 @@ type VoteJournal struct {
 	walLog *wal.Log
 
 	voteDataBuffer *lru.Cache
+
+	floor *voteFloor // votes at or below it may have been lost by a repair
 }
 
 var voteJournalErrorCounter = metrics.NewRegisteredCounter("voteJournal/error", nil)
 
 func NewVoteJournal(filePath string) (*VoteJournal, error) {
+	floor, err := loadVoteFloor(filePath)
+	if err != nil {
+		log.Error("Failed to load vote floor", "err", err)
+		return nil, err
+	}
+	if floor.Target > 0 {
+		log.Warn("Vote journal was repaired, votes are bounded", "source", floor.Source, "target", floor.Target)
+	}
+
 	walLog, err := wal.Open(filePath, &wal.Options{
 		LogFormat:        wal.JSON,
 		SegmentCacheSize: maxSizeOfRecentEntry,
@@ -55,11 +68,21 @@ func NewVoteJournal(filePath string) (*VoteJournal, error) {
 	voteJournal := &VoteJournal{
 		journalPath: filePath,
 		walLog:      walLog,
+		floor:       floor,
 	}
 
 	// Reload all voteData from journal to lru memory everytime node reboot.
+	// Skipping an unreadable vote could lead to a double vote, so refuse to start.
 	for index := firstIndex; index <= lastIndex; index++ {
-		if voteEnvelop, err := voteJournal.ReadVote(index); err == nil && voteEnvelop != nil {
+		voteEnvelop, err := voteJournal.ReadVote(index)
+		if err == nil && voteEnvelop != nil && voteEnvelop.Data == nil {
+			err = errors.New("missing vote data")
+		}
+		if err != nil {
+			walLog.Close()
+			return nil, fmt.Errorf("corrupted vote journal entry %d, check it with `geth vote journal verify`: %v", index, err)
+		}
+		if voteEnvelop != nil {
 			voteData := voteEnvelop.Data
 			voteDataBuffer.Add(voteData.TargetNumber, voteData)
 		}
@@ -105,6 +128,29 @@ func (journal *VoteJournal) WriteVote(voteMessage *types.VoteEnvelope) error {
 	return nil
 }
 
+// ReadVotes returns all the votes in the journal, from the oldest to the newest.
+func (journal *VoteJournal) ReadVotes() ([]*types.VoteEnvelope, error) {
+	firstIndex, err := journal.walLog.FirstIndex()
+	if err != nil {
+		return nil, err
+	}
+	lastIndex, err := journal.walLog.LastIndex()
+	if err != nil {
+		return nil, err
+	}
+	votes := make([]*types.VoteEnvelope, 0, lastIndex-firstIndex+1)
+	for index := firstIndex; index <= lastIndex && index > 0; index++ {
+		vote, err := journal.ReadVote(index)
+		if err != nil {
+			return nil, err
+		}
+		if vote != nil {
+			votes = append(votes, vote)
+		}
+	}
+	return votes, nil
+}
+
 func (journal *VoteJournal) ReadVote(index uint64) (*types.VoteEnvelope, error) {
 	voteMessage, err := journal.walLog.Read(index)
 	if err != nil && err != wal.ErrNotFound {
@@ -0,0 +1,282 @@
+package vote
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// JournalEntry is an entry of the vote journal read straight from its segment
+// files, so that a journal which can't be opened any more is still readable.
+type JournalEntry struct {
+	Index uint64
+	Vote  *types.VoteEnvelope // nil if the entry can't be decoded
+	Err   error               // the reason the entry is unusable, if any
+}
+
+// JournalConflict is a pair of journaled votes violating the voting rules.
+type JournalConflict struct {
+	Reason string
+	VoteA  *types.VoteEnvelope
+	VoteB  *types.VoteEnvelope
+}
+
+// JournalReport is the result of verifying a vote journal.
+type JournalReport struct {
+	Entries   int
+	Invalid   []*JournalEntry    // entries which can't be decoded or aren't signed by the expected key
+	Gaps      [][2]uint64        // ranges of missing indexes
+	Conflicts []*JournalConflict // votes which are slashable together
+
+	// The next vote must have a higher target and a source no lower than these,
+	// the invalid entries which can still be decoded count too
+	HighestSource uint64
+	HighestTarget uint64
+}
+
+// Healthy reports whether the journal can be used safely as it is.
+func (r *JournalReport) Healthy() bool {
+	return len(r.Invalid) == 0 && len(r.Gaps) == 0 && len(r.Conflicts) == 0
+}
+
+// ReadJournalEntries reads all the entries of the vote journal at the given path,
+// decoding and checking the signature of every vote. If a key is given, votes signed
+// by other keys are rejected too.
+func ReadJournalEntries(path string, key *types.BLSPublicKey) ([]*JournalEntry, error) {
+	files, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	// Segments are named after the index of their first entry
+	var segments []string
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || len(name) != 20 {
+			continue
+		}
+		if _, err := strconv.ParseUint(name, 10, 64); err == nil {
+			segments = append(segments, name)
+		}
+	}
+	sort.Strings(segments)
+
+	var entries []*JournalEntry
+	for _, segment := range segments {
+		index, _ := strconv.ParseUint(segment, 10, 64)
+		f, err := os.Open(filepath.Join(path, segment))
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 4096), 1024*1024)
+		for ; scanner.Scan(); index++ {
+			entries = append(entries, decodeJournalEntry(index, scanner.Bytes(), key))
+		}
+		err = scanner.Err()
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// decodeJournalEntry decodes a line of a segment in the JSON format of the WAL,
+// which is {"index":"number","data":string}.
+func decodeJournalEntry(index uint64, line []byte, key *types.BLSPublicKey) *JournalEntry {
+	entry := &JournalEntry{Index: index}
+
+	var raw struct {
+		Index uint64 `json:"index,string"`
+		Data  string `json:"data"`
+	}
+	if err := json.Unmarshal(line, &raw); err != nil {
+		entry.Err = fmt.Errorf("malformed entry: %v", err)
+		return entry
+	}
+	entry.Index = raw.Index
+
+	var data []byte
+	switch {
+	case len(raw.Data) > 0 && raw.Data[0] == '+':
+		data = []byte(raw.Data[1:])
+	case len(raw.Data) > 0 && raw.Data[0] == '$':
+		var err error
+		if data, err = base64.URLEncoding.DecodeString(raw.Data[1:]); err != nil {
+			entry.Err = fmt.Errorf("malformed data: %v", err)
+			return entry
+		}
+	default:
+		entry.Err = errors.New("malformed data")
+		return entry
+	}
+	vote := new(types.VoteEnvelope)
+	if err := json.Unmarshal(data, vote); err != nil {
+		entry.Err = fmt.Errorf("malformed vote: %v", err)
+		return entry
+	}
+	entry.Vote = vote
+
+	if vote.Data == nil {
+		entry.Err = errors.New("missing vote data")
+		return entry
+	}
+	if key != nil && vote.VoteAddress != *key {
+		entry.Err = fmt.Errorf("signed by foreign key %x", vote.VoteAddress[:])
+		return entry
+	}
+	if err := vote.Verify(); err != nil {
+		entry.Err = err
+	}
+	return entry
+}
+
+// VerifyJournal checks the entries for gaps and for votes violating the rules
+// of fast finality, and finds the boundaries the next vote must respect.
+func VerifyJournal(entries []*JournalEntry) *JournalReport {
+	report := &JournalReport{Entries: len(entries)}
+
+	var votes []*types.VoteEnvelope
+	for i, entry := range entries {
+		if i > 0 && entry.Index > entries[i-1].Index+1 {
+			report.Gaps = append(report.Gaps, [2]uint64{entries[i-1].Index + 1, entry.Index - 1})
+		}
+		// A vote failing verification may still have been signed and broadcast
+		// by the node, so it must bound the next vote as much as a valid one
+		if entry.Vote != nil && entry.Vote.Data != nil {
+			if entry.Vote.Data.SourceNumber > report.HighestSource {
+				report.HighestSource = entry.Vote.Data.SourceNumber
+			}
+			if entry.Vote.Data.TargetNumber > report.HighestTarget {
+				report.HighestTarget = entry.Vote.Data.TargetNumber
+			}
+		}
+		if entry.Err != nil {
+			report.Invalid = append(report.Invalid, entry)
+			continue
+		}
+		votes = append(votes, entry.Vote)
+	}
+	for i, a := range votes {
+		for _, b := range votes[i+1:] {
+			if reason := conflict(a.Data, b.Data); reason != "" {
+				report.Conflicts = append(report.Conflicts, &JournalConflict{reason, a, b})
+			}
+		}
+	}
+	return report
+}
+
+// conflict returns why the two votes are slashable together, or an empty string.
+func conflict(a, b *types.VoteData) string {
+	switch {
+	case a.TargetNumber == b.TargetNumber && a.Hash() != b.Hash():
+		return "double vote"
+	case a.SourceNumber < b.SourceNumber && b.TargetNumber < a.TargetNumber,
+		b.SourceNumber < a.SourceNumber && a.TargetNumber < b.TargetNumber:
+		return "surround vote"
+	}
+	return ""
+}
+
+// RepairJournal moves the journal at the given path to a backup and rewrites it
+// with the usable entries only, keeping every valid vote so none of them can be
+// signed differently again. As the dropped entries may hide signed votes, the
+// highest source and target of the journal are persisted as a vote floor, which
+// the node never votes at or below. It returns the path of the backup.
+func RepairJournal(path string, entries []*JournalEntry) (string, error) {
+	report := VerifyJournal(entries)
+	floor, err := loadVoteFloor(path)
+	if err != nil {
+		return "", err
+	}
+	floor.raise(report.HighestSource, report.HighestTarget)
+
+	backup := fmt.Sprintf("%s.bak-%d", filepath.Clean(path), time.Now().Unix())
+	if err := os.Rename(path, backup); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(path, 0700); err != nil {
+		return backup, err
+	}
+	if err := storeVoteFloor(path, floor); err != nil {
+		return backup, err
+	}
+	journal, err := NewVoteJournal(path)
+	if err != nil {
+		return backup, err
+	}
+	defer journal.walLog.Close()
+
+	for _, entry := range entries {
+		if entry.Err != nil {
+			continue
+		}
+		if err := journal.WriteVote(entry.Vote); err != nil {
+			return backup, err
+		}
+	}
+	return backup, nil
+}
+
+// voteFloorFile is the file of the vote floor in the journal directory, ignored
+// by the WAL which only loads the numbered segments.
+const voteFloorFile = "FLOOR"
+
+// voteFloor bounds the votes of a node whose journal was repaired: the dropped
+// entries may hide signed votes, so a new vote must have a higher target and a
+// source no lower than the highest ones seen in the journal.
+type voteFloor struct {
+	Source uint64 `json:"source"`
+	Target uint64 `json:"target"`
+}
+
+// raise lifts the floor to the given source and target, never lowering it.
+func (f *voteFloor) raise(source, target uint64) {
+	if source > f.Source {
+		f.Source = source
+	}
+	if target > f.Target {
+		f.Target = target
+	}
+}
+
+// allows reports whether a vote with the given source and target respects the floor.
+func (f *voteFloor) allows(source, target uint64) bool {
+	return target > f.Target && source >= f.Source
+}
+
+// loadVoteFloor reads the vote floor of the journal at the given path, which is
+// zero if the journal was never repaired.
+func loadVoteFloor(path string) (*voteFloor, error) {
+	floor := new(voteFloor)
+	blob, err := os.ReadFile(filepath.Join(path, voteFloorFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return floor, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(blob, floor); err != nil {
+		return nil, fmt.Errorf("malformed vote floor: %v", err)
+	}
+	return floor, nil
+}
+
+// storeVoteFloor writes the vote floor into the journal at the given path.
+func storeVoteFloor(path string, floor *voteFloor) error {
+	blob, err := json.Marshal(floor)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(path, voteFloorFile), blob, 0600)
+}
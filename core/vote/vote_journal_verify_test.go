@@ -0,0 +1,126 @@
+package vote
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/crypto/bls"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestVerifyJournal(t *testing.T) {
+	secretKey, err := bls.RandKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	newVote := func(source, target uint64, hash byte) *types.VoteEnvelope {
+		vote := &types.VoteEnvelope{
+			Data: &types.VoteData{
+				SourceNumber: source,
+				SourceHash:   common.Hash{byte(source)},
+				TargetNumber: target,
+				TargetHash:   common.Hash{hash},
+			},
+		}
+		copy(vote.VoteAddress[:], secretKey.PublicKey().Marshal())
+		copy(vote.Signature[:], secretKey.Sign(vote.Data.Hash().Bytes()).Marshal())
+		return vote
+	}
+	path := filepath.Join(t.TempDir(), "voteJournal")
+	journal, err := NewVoteJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	votes := []*types.VoteEnvelope{
+		newVote(9, 10, 10),
+		newVote(10, 11, 11),
+		newVote(11, 12, 12),
+		newVote(11, 12, 13), // double vote
+	}
+	// A vote with a broken signature must still raise the floor
+	forged := newVote(12, 14, 14)
+	forged.Signature = types.BLSSignature{0x01}
+	for _, vote := range append(votes, forged) {
+		if err := journal.WriteVote(vote); err != nil {
+			t.Fatal(err)
+		}
+	}
+	journal.walLog.Close()
+
+	// Corrupt the second entry, the journal shouldn't be usable any more
+	segment := filepath.Join(path, "00000000000000000001")
+	blob, err := os.ReadFile(segment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(string(blob), "\n")
+	lines[1] = `{"index":"2","data":"+{}"}`
+	if err := os.WriteFile(segment, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewVoteJournal(path); err == nil {
+		t.Fatal("corrupted journal opened")
+	}
+
+	entries, err := ReadJournalEntries(path, nil)
+	if err != nil {
+		t.Fatalf("failed to read journal: %v", err)
+	}
+	report := VerifyJournal(entries)
+	if report.Healthy() {
+		t.Fatal("corrupted journal reported healthy")
+	}
+	if report.Entries != 5 || len(report.Invalid) != 2 || report.Invalid[0].Index != 2 || report.Invalid[1].Index != 5 {
+		t.Fatalf("invalid entries mismatch: %d entries, %v invalid", report.Entries, report.Invalid)
+	}
+	if len(report.Conflicts) != 1 || report.Conflicts[0].Reason != "double vote" {
+		t.Fatalf("conflicts mismatch: %v", report.Conflicts)
+	}
+	if report.HighestSource != 12 || report.HighestTarget != 14 {
+		t.Fatalf("highest vote mismatch: source %d, target %d", report.HighestSource, report.HighestTarget)
+	}
+
+	// Votes signed by another key should be rejected
+	otherKey := types.BLSPublicKey{0x01}
+	entries, err = ReadJournalEntries(path, &otherKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report := VerifyJournal(entries); len(report.Invalid) != 5 {
+		t.Fatalf("foreign votes accepted: %d invalid", len(report.Invalid))
+	}
+
+	// Repairing should keep all the valid votes, conflicting ones included
+	entries, _ = ReadJournalEntries(path, nil)
+	backup, err := RepairJournal(path, entries)
+	if err != nil {
+		t.Fatalf("failed to repair journal: %v", err)
+	}
+	if _, err := os.Stat(backup); err != nil {
+		t.Fatalf("missing backup: %v", err)
+	}
+	journal, err = NewVoteJournal(path)
+	if err != nil {
+		t.Fatalf("failed to open repaired journal: %v", err)
+	}
+	defer journal.walLog.Close()
+
+	repaired, err := journal.ReadVotes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repaired) != 3 || repaired[0].Hash() != votes[0].Hash() || repaired[1].Hash() != votes[2].Hash() {
+		t.Fatalf("repaired journal mismatch: %d votes", len(repaired))
+	}
+	// The floor of the repaired journal should survive restarts
+	if floor := journal.floor; floor.Source != 12 || floor.Target != 14 {
+		t.Fatalf("vote floor mismatch: source %d, target %d", floor.Source, floor.Target)
+	}
+	if journal.floor.allows(12, 14) || journal.floor.allows(11, 15) || !journal.floor.allows(12, 15) {
+		t.Fatal("vote floor not enforced")
+	}
+}
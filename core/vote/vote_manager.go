@@ -47,7 +47,7 @@ type VoteManager struct {
 	engine consensus.PoSA
 }
 
-func NewVoteManager(eth Backend, chain *core.BlockChain, pool *VotePool, journalPath, blsPasswordPath, blsWalletPath string, engine consensus.PoSA) (*VoteManager, error) {
+func NewVoteManager(eth Backend, chain *core.BlockChain, pool *VotePool, journalPath string, voteSigner *VoteSigner, engine consensus.PoSA) (*VoteManager, error) {
 	voteManager := &VoteManager{
 		eth:         eth,
 		chain:       chain,
@@ -57,12 +57,6 @@ func NewVoteManager(eth Backend, chain *core.BlockChain, pool *VotePool, journal
 		engine:      engine,
 	}
 
-	// Create voteSigner.
-	voteSigner, err := NewVoteSigner(blsPasswordPath, blsWalletPath)
-	if err != nil {
-		return nil, err
-	}
-	log.Info("Create voteSigner successfully")
 	voteManager.signer = voteSigner
 
 	// Create voteJournal
@@ -73,6 +67,13 @@ func NewVoteManager(eth Backend, chain *core.BlockChain, pool *VotePool, journal
 	log.Info("Create voteJournal successfully")
 	voteManager.journal = voteJournal
 
+	// Let the slashing protection know about the votes signed before the restart
+	votes, err := voteJournal.ReadVotes()
+	if err != nil {
+		return nil, err
+	}
+	voteSigner.recordVotes(votes)
+
 	// Subscribe to chain head event.
 	voteManager.chainHeadSub = voteManager.chain.SubscribeChainHeadEvent(voteManager.chainHeadCh)
 	voteManager.syncVoteSub = voteManager.pool.SubscribeNewVoteEvent(voteManager.syncVoteCh)
@@ -212,6 +213,7 @@ func (voteManager *VoteManager) loop() {
 				voteJournalErrorCounter.Inc(1)
 				continue
 			}
+			voteManager.signer.recordVotes([]*types.VoteEnvelope{voteMessage})
 			log.Debug("vote manager synced vote", "votedBlockNumber", voteMessage.Data.TargetNumber, "votedBlockHash", voteMessage.Data.TargetHash, "voteMessageHash", voteMessage.Hash())
 			votesManagerCounter.Inc(1)
 		case <-voteManager.syncVoteSub.Err():
@@ -237,6 +239,13 @@ func (voteManager *VoteManager) UnderRules(header *types.Header) (bool, uint64,
 
 	targetNumber := header.Number.Uint64()
 
+	// Votes dropped by a journal repair may have been signed, never vote at or below them.
+	if !voteManager.journal.floor.allows(sourceNumber, targetNumber) {
+		log.Debug("err: vote below the floor of the repaired journal", "source", sourceNumber, "target", targetNumber,
+			"floorSource", voteManager.journal.floor.Source, "floorTarget", voteManager.journal.floor.Target)
+		return false, 0, common.Hash{}
+	}
+
 	voteDataBuffer := voteManager.journal.voteDataBuffer
 	//Rule 1:  A validator must not publish two distinct votes for the same height.
 	if voteDataBuffer.Contains(targetNumber) {
@@ -1,7 +1,9 @@
 package vote
 
 import (
+	"bytes"
 	"container/heap"
+	"sort"
 	"sync"
 
 	mapset "github.com/deckarep/golang-set/v2"
@@ -331,6 +333,50 @@ func (pool *VotePool) FetchVoteByBlockHash(blockHash common.Hash) []*types.VoteE
 	return nil
 }
 
+// VotesByBlockHash returns a copy of the votes for the given block, along with
+// whether they're future votes waiting for the block to be imported.
+func (pool *VotePool) VotesByBlockHash(blockHash common.Hash) ([]*types.VoteEnvelope, bool) {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	if voteBox, ok := pool.curVotes[blockHash]; ok {
+		return append([]*types.VoteEnvelope(nil), voteBox.voteMessages...), false
+	}
+	if voteBox, ok := pool.futureVotes[blockHash]; ok {
+		return append([]*types.VoteEnvelope(nil), voteBox.voteMessages...), true
+	}
+	return nil, false
+}
+
+// VoteBoxStatus is the summary of the votes for a single block.
+type VoteBoxStatus struct {
+	BlockNumber uint64
+	BlockHash   common.Hash
+	Votes       int
+}
+
+// Status returns the summary of the current and future votes, ordered by block
+// number, and the number of the received votes.
+func (pool *VotePool) Status() ([]VoteBoxStatus, []VoteBoxStatus, int) {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	summarize := func(m map[common.Hash]*VoteBox) []VoteBoxStatus {
+		boxes := make([]VoteBoxStatus, 0, len(m))
+		for hash, voteBox := range m {
+			boxes = append(boxes, VoteBoxStatus{voteBox.blockNumber, hash, len(voteBox.voteMessages)})
+		}
+		sort.Slice(boxes, func(i, j int) bool {
+			if boxes[i].BlockNumber != boxes[j].BlockNumber {
+				return boxes[i].BlockNumber < boxes[j].BlockNumber
+			}
+			return bytes.Compare(boxes[i].BlockHash[:], boxes[j].BlockHash[:]) < 0
+		})
+		return boxes
+	}
+	return summarize(pool.curVotes), summarize(pool.futureVotes), pool.receivedVotes.Cardinality()
+}
+
 func (pool *VotePool) basicVerify(vote *types.VoteEnvelope, headNumber uint64, m map[common.Hash]*VoteBox, isFutureVote bool, voteHash common.Hash) bool {
 	targetHash := vote.Data.TargetHash
 	pool.mu.RLock()
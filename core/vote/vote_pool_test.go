@@ -176,7 +176,11 @@ func testVotePool(t *testing.T, isValidRules bool) {
 	file.Close()
 	os.Remove(journal)
 
-	voteManager, err := NewVoteManager(newTestBackend(), chain, votePool, journal, walletPasswordDir, walletDir, mockEngine)
+	voteSigner, err := NewVoteSigner(walletPasswordDir, walletDir)
+	if err != nil {
+		t.Fatalf("failed to create vote signer: %v", err)
+	}
+	voteManager, err := NewVoteManager(newTestBackend(), chain, votePool, journal, voteSigner, mockEngine)
 	if err != nil {
 		t.Fatalf("failed to create vote managers")
 	}
@@ -303,6 +307,22 @@ func testVotePool(t *testing.T, isValidRules bool) {
 		t.Fatalf("journal failed")
 	}
 
+	// Verify the views of the vote pool and the journal
+	api := NewAPI(votePool, voteManager)
+	if status := api.GetPoolStatus(); status.CurrentVotes != 256 || len(status.Current) != 256 || status.FutureVotes != 1 || len(status.Future) != 1 {
+		t.Fatalf("pool status mismatch: current %d, future %d", status.CurrentVotes, status.FutureVotes)
+	}
+	if votes := api.GetVotes(common.Hash{}); !votes.Future || len(votes.Votes) != 1 || votes.Votes[0].TargetNumber != 279 {
+		t.Fatalf("future votes mismatch: %+v", votes)
+	}
+	history, err := api.GetLocalVoteHistory(10)
+	if err != nil || len(history) != 10 {
+		t.Fatalf("local vote history mismatch: %v", err)
+	}
+	if head := chain.CurrentBlock().Number.Uint64(); uint64(history[9].TargetNumber) != head {
+		t.Fatalf("latest local vote mismatch: have %d, want %d", history[9].TargetNumber, head)
+	}
+
 	// Test duplicate vote case, shouldn'd be put into vote pool
 	duplicateVote := &types.VoteEnvelope{
 		Data: &types.VoteData{
@@ -27,6 +27,9 @@ var votesSigningErrorCounter = metrics.NewRegisteredCounter("votesSigner/error",
 type VoteSigner struct {
 	km     *keymanager.IKeymanager
 	PubKey [48]byte
+
+	remote     *remoteSigner       // remote signer used instead of the local wallet, if any
+	protection *slashingProtection // slashing protection of the remote signer
 }
 
 func NewVoteSigner(blsPasswordPath, blsWalletPath string) (*VoteSigner, error) {
@@ -79,6 +82,9 @@ func NewVoteSigner(blsPasswordPath, blsWalletPath string) (*VoteSigner, error) {
 }
 
 func (signer *VoteSigner) SignVote(vote *types.VoteEnvelope) error {
+	if signer.remote != nil {
+		return signer.signRemote(vote)
+	}
 	// Sign the vote, fetch the first pubKey as validator's bls public key.
 	pubKey := signer.PubKey
 	blsPubKey, err := bls.PublicKeyFromBytes(pubKey[:])
@@ -24,7 +24,11 @@ import (
 	"os"
 	"strings"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rlp"
 )
@@ -141,3 +145,93 @@ func (api *AdminAPI) ImportChain(file string) (bool, error) {
 	}
 	return true, nil
 }
+
+// Blacklist returns the addresses whose transactions are rejected by the pool.
+func (api *AdminAPI) Blacklist() ([]common.Address, error) {
+	blacklist, err := api.blacklist()
+	if err != nil {
+		return nil, err
+	}
+	return blacklist.List(), nil
+}
+
+// AddToBlacklist bans the transactions sent from or to the given addresses.
+func (api *AdminAPI) AddToBlacklist(addrs []common.Address) (bool, error) {
+	blacklist, err := api.blacklist()
+	if err != nil {
+		return false, err
+	}
+	if err := blacklist.Add(addrs); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RemoveFromBlacklist lifts the ban of the given addresses.
+func (api *AdminAPI) RemoveFromBlacklist(addrs []common.Address) (bool, error) {
+	blacklist, err := api.blacklist()
+	if err != nil {
+		return false, err
+	}
+	if err := blacklist.Remove(addrs); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ReloadBlacklist reloads the blacklist from its file.
+func (api *AdminAPI) ReloadBlacklist() (bool, error) {
+	blacklist, err := api.blacklist()
+	if err != nil {
+		return false, err
+	}
+	if err := blacklist.Reload(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// TxPoolLanes returns the configuration and usage of the transaction pool
+// priority lanes.
+func (api *AdminAPI) TxPoolLanes() []legacypool.LaneStatus {
+	return api.eth.legacyPool.Lanes()
+}
+
+// SetTxPoolLane adds a transaction pool priority lane, or replaces the one with
+// the same name.
+func (api *AdminAPI) SetTxPoolLane(lane legacypool.Lane) (bool, error) {
+	if err := api.eth.legacyPool.SetLane(lane); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RemoveTxPoolLane deletes a transaction pool priority lane.
+func (api *AdminAPI) RemoveTxPoolLane(name string) (bool, error) {
+	if err := api.eth.legacyPool.RemoveLane(name); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ExportTxPool writes the pending and queued transactions of the pool to a file
+// in RLP, returning their number. Existing files are never overwritten.
+func (api *AdminAPI) ExportTxPool(file string) (hexutil.Uint, error) {
+	n, err := api.eth.TxPool().Export(file, false)
+	return hexutil.Uint(n), err
+}
+
+// ImportTxPool adds the transactions of a file written by ExportTxPool to the
+// pool, after validating them against the current head, returning the number added.
+func (api *AdminAPI) ImportTxPool(file string) (hexutil.Uint, error) {
+	n, err := api.eth.TxPool().Import(file)
+	return hexutil.Uint(n), err
+}
+
+func (api *AdminAPI) blacklist() (*txpool.Blacklist, error) {
+	blacklist := api.eth.TxPool().Blacklist()
+	if blacklist == nil {
+		return nil, errors.New("transaction blacklist unavailable")
+	}
+	return blacklist, nil
+}
@@ -19,6 +19,7 @@ package eth
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/ethereum/go-ethereum/log"
 	"math/big"
 	"time"
@@ -32,6 +33,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/eth/downloader"
@@ -254,7 +256,15 @@ func (b *EthAPIBackend) GetReceipts(ctx context.Context, hash common.Hash) (type
 }
 
 func (b *EthAPIBackend) GetBlobSidecars(ctx context.Context, hash common.Hash) (types.BlobSidecars, error) {
-	return b.eth.blockchain.GetSidecarsByHash(hash), nil
+	sidecars := b.eth.blockchain.GetSidecarsByHash(hash)
+	if sidecars == nil && b.eth.blobArchive != nil {
+		// Fall back to the archives for the sidecars beyond the retention window
+		number := rawdb.ReadHeaderNumber(b.eth.chainDb, hash)
+		if number != nil && *number+params.MinBlocksForBlobRequests < b.eth.blockchain.CurrentBlock().Number.Uint64() {
+			return b.eth.blobArchive.Sidecars(*number, hash)
+		}
+	}
+	return sidecars, nil
 }
 func (b *EthAPIBackend) GetLogs(ctx context.Context, hash common.Hash, number uint64) ([][]*types.Log, error) {
 	return rawdb.ReadLogs(b.eth.chainDb, hash, number), nil
@@ -327,6 +337,25 @@ func (b *EthAPIBackend) SendTx(ctx context.Context, signedTx *types.Transaction)
 	return b.eth.txPool.Add([]*types.Transaction{signedTx}, true, false)[0]
 }
 
+// SendPrivateTx adds a transaction to the pool without propagating it to the
+// network, includable until the given block or for the configured lifetime if
+// zero, and forwards it to the builders. The given block can't be beyond the
+// configured lifetime.
+func (b *EthAPIBackend) SendPrivateTx(ctx context.Context, signedTx *types.Transaction, maxBlockNumber uint64) error {
+	limit := b.eth.blockchain.CurrentBlock().Number.Uint64() + b.eth.config.RPCPrivateTxLifetime
+	if maxBlockNumber == 0 {
+		maxBlockNumber = limit
+	}
+	if maxBlockNumber > limit {
+		return fmt.Errorf("max block number %d beyond the private transaction lifetime, limit is %d", maxBlockNumber, limit)
+	}
+	if err := b.eth.txPool.AddPrivate([]*types.Transaction{signedTx}, maxBlockNumber, false)[0]; err != nil {
+		return err
+	}
+	b.eth.miner.ForwardPrivateTx(signedTx, maxBlockNumber)
+	return nil
+}
+
 func (b *EthAPIBackend) GetPoolTransactions() (types.Transactions, error) {
 	pending := b.eth.txPool.Pending(txpool.PendingFilter{})
 	var txs types.Transactions
@@ -373,20 +402,79 @@ func (b *EthAPIBackend) Stats() (runnable int, blocked int) {
 	return b.eth.txPool.Stats()
 }
 
+func (b *EthAPIBackend) TxPoolLanes() []legacypool.LaneStatus {
+	return b.eth.legacyPool.Lanes()
+}
+
+// TxPoolContent returns the pending and queued transactions of the pool, the
+// private ones left out.
 func (b *EthAPIBackend) TxPoolContent() (map[common.Address][]*types.Transaction, map[common.Address][]*types.Transaction) {
-	return b.eth.txPool.Content()
+	pending, queued := b.eth.txPool.Content()
+	return b.publicTxsByAccount(pending), b.publicTxsByAccount(queued)
 }
 
+// TxPoolContentFrom returns the pending and queued transactions of the account,
+// the private ones left out.
 func (b *EthAPIBackend) TxPoolContentFrom(addr common.Address) ([]*types.Transaction, []*types.Transaction) {
-	return b.eth.txPool.ContentFrom(addr)
+	pending, queued := b.eth.txPool.ContentFrom(addr)
+	return b.publicTxs(pending), b.publicTxs(queued)
+}
+
+// publicTxs filters out the private transactions.
+func (b *EthAPIBackend) publicTxs(txs []*types.Transaction) []*types.Transaction {
+	public := make([]*types.Transaction, 0, len(txs))
+	for _, tx := range txs {
+		if !b.eth.txPool.IsPrivate(tx.Hash()) {
+			public = append(public, tx)
+		}
+	}
+	return public
+}
+
+func (b *EthAPIBackend) publicTxsByAccount(accounts map[common.Address][]*types.Transaction) map[common.Address][]*types.Transaction {
+	public := make(map[common.Address][]*types.Transaction, len(accounts))
+	for addr, txs := range accounts {
+		if txs = b.publicTxs(txs); len(txs) > 0 {
+			public[addr] = txs
+		}
+	}
+	return public
 }
 
 func (b *EthAPIBackend) TxPool() *txpool.TxPool {
 	return b.eth.txPool
 }
 
+// SubscribeNewTxsEvent subscribes to the transactions entering the pool, the
+// private ones left out.
 func (b *EthAPIBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription {
-	return b.eth.txPool.SubscribeTransactions(ch, true)
+	txsCh := make(chan core.NewTxsEvent, cap(ch))
+	sub := b.eth.txPool.SubscribeTransactions(txsCh, true)
+
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case ev := <-txsCh:
+				if ev.Txs = b.publicTxs(ev.Txs); len(ev.Txs) == 0 {
+					continue
+				}
+				select {
+				case ch <- ev:
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	})
+}
+
+func (b *EthAPIBackend) SubscribeTxPoolEvents(ch chan<- []core.TxPoolEvent) event.Subscription {
+	return b.eth.txPool.SubscribeTxPoolEvents(ch)
 }
 
 func (b *EthAPIBackend) SubscribeNewVoteEvent(ch chan<- core.NewVoteEvent) event.Subscription {
@@ -525,6 +613,10 @@ func (b *EthAPIBackend) BestBidGasFee(parentHash common.Hash) *big.Int {
 	return b.Miner().BestPackedBlockReward(parentHash)
 }
 
+func (b *EthAPIBackend) BuilderStats() []*types.BuilderStats {
+	return b.Miner().BuilderStats()
+}
+
 func (b *EthAPIBackend) MinerInTurn() bool {
 	return b.Miner().InTurn()
 }
@@ -32,8 +32,8 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/clique"
 	"github.com/ethereum/go-ethereum/consensus/parlia"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/blobarchive"
 	"github.com/ethereum/go-ethereum/core/bloombits"
-	"github.com/ethereum/go-ethereum/core/monitor"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state/pruner"
 	"github.com/ethereum/go-ethereum/core/txpool"
@@ -83,6 +83,7 @@ type Ethereum struct {
 
 	// Handlers
 	txPool              *txpool.TxPool
+	legacyPool          *legacypool.LegacyPool
 	blockchain          *core.BlockChain
 	handler             *handler
 	ethDialCandidates   enode.Iterator
@@ -117,7 +118,11 @@ type Ethereum struct {
 
 	shutdownTracker *shutdowncheck.ShutdownTracker // Tracks if and when the node has shutdown ungracefully
 
-	votePool *vote.VotePool
+	votePool    *vote.VotePool
+	voteManager *vote.VoteManager
+
+	blobArchiver *blobarchive.Archiver
+	blobArchive  *blobarchive.Archive // serves the archived sidecars, nil if disabled
 }
 
 // New creates a new Ethereum object (including the
@@ -287,9 +292,6 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	if config.PersistDiff {
 		bcOps = append(bcOps, core.EnablePersistDiff(config.DiffBlock))
 	}
-	if stack.Config().EnableDoubleSignMonitor {
-		bcOps = append(bcOps, core.EnableDoubleSignChecker)
-	}
 
 	peers := newPeerSet()
 	bcOps = append(bcOps, core.EnableBlockValidator(chainConfig, eth.engine, config.TriesVerifyMode, peers))
@@ -300,6 +302,17 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	}
 	eth.bloomIndexer.Start(eth.blockchain)
 
+	if config.BlobArchiveDir != "" {
+		config.BlobArchiveDir = stack.ResolvePath(config.BlobArchiveDir)
+		eth.blobArchiver, err = blobarchive.NewArchiver(chainDb, eth.blockchain, config.BlobArchiveDir, config.BlobArchiveRetention)
+		if err != nil {
+			return nil, err
+		}
+		if config.BlobArchiveRPC {
+			eth.blobArchive = blobarchive.NewArchive(config.BlobArchiveDir, blobarchive.NetworkName(chainConfig))
+		}
+	}
+
 	if config.BlobPool.Datadir != "" {
 		config.BlobPool.Datadir = stack.ResolvePath(config.BlobPool.Datadir)
 	}
@@ -308,12 +321,25 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = stack.ResolvePath(config.TxPool.Journal)
 	}
-	legacyPool := legacypool.New(config.TxPool, eth.blockchain)
+	if config.TxPool.Snapshot != "" {
+		config.TxPool.Snapshot = stack.ResolvePath(config.TxPool.Snapshot)
+	}
+	if config.TxPool.Blacklist != "" {
+		config.TxPool.Blacklist = stack.ResolvePath(config.TxPool.Blacklist)
+	}
+	blacklist, err := txpool.NewBlacklist(config.TxPool.Blacklist)
+	if err != nil {
+		return nil, err
+	}
+	eth.legacyPool = legacypool.New(config.TxPool, eth.blockchain)
+	eth.legacyPool.SetBlacklist(blacklist)
 
-	eth.txPool, err = txpool.New(config.TxPool.PriceLimit, eth.blockchain, []txpool.SubPool{legacyPool, blobPool})
+	eth.txPool, err = txpool.New(config.TxPool.PriceLimit, eth.blockchain, []txpool.SubPool{eth.legacyPool, blobPool})
 	if err != nil {
+		blacklist.Close()
 		return nil, err
 	}
+	eth.txPool.SetBlacklist(blacklist)
 	// Permit the downloader to use the trie cache allowance during fast sync
 	cacheLimit := cacheConfig.TrieCleanLimit + cacheConfig.TrieDirtyLimit + cacheConfig.SnapshotLimit
 	if eth.handler, err = newHandler(&handlerConfig{
@@ -333,6 +359,9 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		return nil, err
 	}
 
+	if config.Miner.Mev.BidJournalDir != "" {
+		config.Miner.Mev.BidJournalDir = stack.ResolvePath(config.Miner.Mev.BidJournalDir)
+	}
 	eth.miner = miner.New(eth, &config.Miner, eth.blockchain.Config(), eth.EventMux(), eth.engine, eth.isLocalBlock)
 	eth.miner.SetExtra(makeExtraData(config.Miner.ExtraData))
 
@@ -351,17 +380,20 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		}
 		log.Info("Create votePool successfully")
 		eth.handler.votepool = votePool
-		if stack.Config().EnableMaliciousVoteMonitor {
-			eth.handler.maliciousVoteMonitor = monitor.NewMaliciousVoteMonitor()
-			log.Info("Create MaliciousVoteMonitor successfully")
-		}
-
 		if config.Miner.VoteEnable {
 			conf := stack.Config()
-			blsPasswordPath := stack.ResolvePath(conf.BLSPasswordFile)
-			blsWalletPath := stack.ResolvePath(conf.BLSWalletDir)
+			var voteSigner *vote.VoteSigner
+			if conf.BLSRemoteSigner != "" {
+				voteSigner, err = vote.NewRemoteVoteSigner(conf.BLSRemoteSigner, conf.BLSRemoteSignerKey, stack.ResolvePath(vote.SlashingProtectionDir))
+			} else {
+				voteSigner, err = vote.NewVoteSigner(stack.ResolvePath(conf.BLSPasswordFile), stack.ResolvePath(conf.BLSWalletDir))
+			}
+			if err != nil {
+				log.Error("Failed to create voteSigner", "err", err)
+				return nil, err
+			}
 			voteJournalPath := stack.ResolvePath(conf.VoteJournalDir)
-			if _, err := vote.NewVoteManager(eth, eth.blockchain, votePool, voteJournalPath, blsPasswordPath, blsWalletPath, posa); err != nil {
+			if eth.voteManager, err = vote.NewVoteManager(eth, eth.blockchain, votePool, voteJournalPath, voteSigner, posa); err != nil {
 				log.Error("Failed to Initialize voteManager", "err", err)
 				return nil, err
 			}
@@ -433,6 +465,14 @@ func (s *Ethereum) APIs() []rpc.API {
 	// Append any APIs exposed explicitly by the consensus engine
 	apis = append(apis, s.engine.APIs(s.BlockChain())...)
 
+	// Append the vote APIs if fast finality is supported
+	if s.votePool != nil {
+		apis = append(apis, rpc.API{
+			Namespace: "vote",
+			Service:   vote.NewAPI(s.votePool, s.voteManager),
+		})
+	}
+
 	// Append all the local APIs and return
 	return append(apis, []rpc.API{
 		{
@@ -660,6 +700,10 @@ func (s *Ethereum) Start() error {
 	// Regularly update shutdown marker
 	s.shutdownTracker.Start()
 
+	if s.blobArchiver != nil {
+		s.blobArchiver.Start()
+	}
+
 	// Figure out a max peers count based on the server limits
 	maxPeers := s.p2pServer.MaxPeers
 	if s.config.LightServ > 0 {
@@ -686,8 +730,18 @@ func (s *Ethereum) Stop() error {
 	// Then stop everything else.
 	s.bloomIndexer.Close()
 	close(s.closeBloomHandler)
+	if file := s.config.TxPool.Snapshot; file != "" {
+		if n, err := s.txPool.Export(file, true); err != nil {
+			log.Error("Failed to dump transaction pool", "file", file, "err", err)
+		} else {
+			log.Info("Dumped transaction pool", "file", file, "transactions", n)
+		}
+	}
 	s.txPool.Close()
 	s.miner.Close()
+	if s.blobArchiver != nil {
+		s.blobArchiver.Stop()
+	}
 	s.blockchain.Stop()
 	s.engine.Close()
 
@@ -49,29 +49,31 @@ var FullNodeGPO = gasprice.Config{
 
 // Defaults contains default settings for use on the BSC main net.
 var Defaults = Config{
-	SyncMode:           downloader.SnapSync,
-	NetworkId:          0, // enable auto configuration of networkID == chainID
-	TxLookupLimit:      2350000,
-	TransactionHistory: 2350000,
-	StateHistory:       params.FullImmutabilityThreshold,
-	LightPeers:         100,
-	DatabaseCache:      512,
-	TrieCleanCache:     154,
-	TrieDirtyCache:     256,
-	TrieTimeout:        60 * time.Minute,
-	TriesInMemory:      128,
-	TriesVerifyMode:    core.LocalVerify,
-	SnapshotCache:      102,
-	DiffBlock:          uint64(86400),
-	FilterLogCacheSize: 32,
-	Miner:              miner.DefaultConfig,
-	TxPool:             legacypool.DefaultConfig,
-	BlobPool:           blobpool.DefaultConfig,
-	RPCGasCap:          50000000,
-	RPCEVMTimeout:      5 * time.Second,
-	GPO:                FullNodeGPO,
-	RPCTxFeeCap:        1,                                         // 1 ether
-	BlobExtraReserve:   params.DefaultExtraReserveForBlobRequests, // Extra reserve threshold for blob, blob never expires when -1 is set, default 28800
+	SyncMode:             downloader.SnapSync,
+	NetworkId:            0, // enable auto configuration of networkID == chainID
+	TxLookupLimit:        2350000,
+	TransactionHistory:   2350000,
+	StateHistory:         params.FullImmutabilityThreshold,
+	LightPeers:           100,
+	DatabaseCache:        512,
+	TrieCleanCache:       154,
+	TrieDirtyCache:       256,
+	TrieTimeout:          60 * time.Minute,
+	TriesInMemory:        128,
+	TriesVerifyMode:      core.LocalVerify,
+	SnapshotCache:        102,
+	DiffBlock:            uint64(86400),
+	FilterLogCacheSize:   32,
+	Miner:                miner.DefaultConfig,
+	TxPool:               legacypool.DefaultConfig,
+	BlobPool:             blobpool.DefaultConfig,
+	RPCGasCap:            50000000,
+	RPCEVMTimeout:        5 * time.Second,
+	TraceFilterRange:     1000,
+	GPO:                  FullNodeGPO,
+	RPCTxFeeCap:          1, // 1 ether
+	RPCPrivateTxLifetime: 25,
+	BlobExtraReserve:     params.DefaultExtraReserveForBlobRequests, // Extra reserve threshold for blob, blob never expires when -1 is set, default 28800
 }
 
 //go:generate go run github.com/fjl/gencodec -type Config -formats toml -out gen_config.go
@@ -188,6 +190,10 @@ type Config struct {
 	// send-transaction variants. The unit is ether.
 	RPCTxFeeCap float64
 
+	// RPCPrivateTxLifetime is the number of blocks a private transaction stays
+	// includable in when submitted without an explicit max block number.
+	RPCPrivateTxLifetime uint64
+
 	// OverrideBohr (TODO: remove after the fork)
 	OverrideBohr *uint64 `toml:",omitempty"`
 
@@ -196,6 +202,30 @@ type Config struct {
 
 	// blob setting
 	BlobExtraReserve uint64
+
+	// BlobArchiveDir is the directory of the blob sidecar archives, the expired
+	// sidecars are archived there if set.
+	BlobArchiveDir string `toml:",omitempty"`
+
+	// BlobArchiveRPC enables serving the archived sidecars through the RPC once
+	// they are pruned from the database.
+	BlobArchiveRPC bool `toml:",omitempty"`
+
+	// BlobArchiveRetention is the number of most recent epochs whose archives are
+	// kept, the older ones are deleted. Zero keeps all the archives.
+	BlobArchiveRetention uint64 `toml:",omitempty"`
+
+	// TraceCacheSize is the disk space allowance of the cache of the block traces
+	// in megabytes, the cache being disabled if zero.
+	TraceCacheSize uint64 `toml:",omitempty"`
+
+	// TraceCacheTracers are the tracers run on the new heads in the background,
+	// their traces being cached.
+	TraceCacheTracers []string `toml:",omitempty"`
+
+	// TraceFilterRange is the maximum number of blocks traced by a trace_filter
+	// request, unlimited if zero.
+	TraceFilterRange uint64
 }
 
 // CreateConsensusEngine creates a consensus engine for the given chain config.
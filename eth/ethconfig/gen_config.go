@@ -70,9 +70,16 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		RPCGasCap               uint64
 		RPCEVMTimeout           time.Duration
 		RPCTxFeeCap             float64
+		RPCPrivateTxLifetime    uint64
 		OverrideBohr            *uint64 `toml:",omitempty"`
 		OverrideVerkle          *uint64 `toml:",omitempty"`
 		BlobExtraReserve        uint64
+		BlobArchiveDir          string   `toml:",omitempty"`
+		BlobArchiveRPC          bool     `toml:",omitempty"`
+		BlobArchiveRetention    uint64   `toml:",omitempty"`
+		TraceCacheSize          uint64   `toml:",omitempty"`
+		TraceCacheTracers       []string `toml:",omitempty"`
+		TraceFilterRange        uint64
 	}
 	var enc Config
 	enc.Genesis = c.Genesis
@@ -128,9 +135,16 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.RPCGasCap = c.RPCGasCap
 	enc.RPCEVMTimeout = c.RPCEVMTimeout
 	enc.RPCTxFeeCap = c.RPCTxFeeCap
+	enc.RPCPrivateTxLifetime = c.RPCPrivateTxLifetime
 	enc.OverrideBohr = c.OverrideBohr
 	enc.OverrideVerkle = c.OverrideVerkle
 	enc.BlobExtraReserve = c.BlobExtraReserve
+	enc.BlobArchiveDir = c.BlobArchiveDir
+	enc.BlobArchiveRPC = c.BlobArchiveRPC
+	enc.BlobArchiveRetention = c.BlobArchiveRetention
+	enc.TraceCacheSize = c.TraceCacheSize
+	enc.TraceCacheTracers = c.TraceCacheTracers
+	enc.TraceFilterRange = c.TraceFilterRange
 	return &enc, nil
 }
 
@@ -190,9 +204,16 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		RPCGasCap               *uint64
 		RPCEVMTimeout           *time.Duration
 		RPCTxFeeCap             *float64
+		RPCPrivateTxLifetime    *uint64
 		OverrideBohr            *uint64 `toml:",omitempty"`
 		OverrideVerkle          *uint64 `toml:",omitempty"`
 		BlobExtraReserve        *uint64
+		BlobArchiveDir          *string  `toml:",omitempty"`
+		BlobArchiveRPC          *bool    `toml:",omitempty"`
+		BlobArchiveRetention    *uint64  `toml:",omitempty"`
+		TraceCacheSize          *uint64  `toml:",omitempty"`
+		TraceCacheTracers       []string `toml:",omitempty"`
+		TraceFilterRange        *uint64
 	}
 	var dec Config
 	if err := unmarshal(&dec); err != nil {
@@ -357,6 +378,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.RPCTxFeeCap != nil {
 		c.RPCTxFeeCap = *dec.RPCTxFeeCap
 	}
+	if dec.RPCPrivateTxLifetime != nil {
+		c.RPCPrivateTxLifetime = *dec.RPCPrivateTxLifetime
+	}
 	if dec.OverrideBohr != nil {
 		c.OverrideBohr = dec.OverrideBohr
 	}
@@ -366,5 +390,23 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.BlobExtraReserve != nil {
 		c.BlobExtraReserve = *dec.BlobExtraReserve
 	}
+	if dec.BlobArchiveDir != nil {
+		c.BlobArchiveDir = *dec.BlobArchiveDir
+	}
+	if dec.BlobArchiveRPC != nil {
+		c.BlobArchiveRPC = *dec.BlobArchiveRPC
+	}
+	if dec.BlobArchiveRetention != nil {
+		c.BlobArchiveRetention = *dec.BlobArchiveRetention
+	}
+	if dec.TraceCacheSize != nil {
+		c.TraceCacheSize = *dec.TraceCacheSize
+	}
+	if dec.TraceCacheTracers != nil {
+		c.TraceCacheTracers = dec.TraceCacheTracers
+	}
+	if dec.TraceFilterRange != nil {
+		c.TraceFilterRange = *dec.TraceFilterRange
+	}
 	return nil
 }
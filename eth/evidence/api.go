@@ -0,0 +1,127 @@
+package evidence
+
+import (
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Kinds of slashing evidences.
+const (
+	KindDoubleSign        = "doubleSign"
+	KindFinalityViolation = "finalityViolation"
+)
+
+// API exposes the evidence journal.
+type API struct {
+	s *Service
+}
+
+// Evidence is the RPC representation of a slashing evidence.
+type Evidence struct {
+	Kind   string         `json:"kind"`
+	Hash   common.Hash    `json:"hash"`
+	Number hexutil.Uint64 `json:"number"`
+
+	// double sign
+	Validator *common.Address `json:"validator,omitempty"`
+	Header1   hexutil.Bytes   `json:"header1,omitempty"` // rlp encoded header
+	Header2   hexutil.Bytes   `json:"header2,omitempty"`
+
+	// finality violation
+	VoteAddress hexutil.Bytes       `json:"voteAddress,omitempty"`
+	VoteA       *types.VoteEnvelope `json:"voteA,omitempty"`
+	VoteB       *types.VoteEnvelope `json:"voteB,omitempty"`
+
+	DetectedAt    hexutil.Uint64 `json:"detectedAt"`
+	TxHash        *common.Hash   `json:"txHash,omitempty"`
+	SubmittedAt   hexutil.Uint64 `json:"submittedAt,omitempty"`
+	IncludedBlock hexutil.Uint64 `json:"includedBlock,omitempty"`
+	Reverts       hexutil.Uint64 `json:"reverts,omitempty"`
+}
+
+// PendingEvidences returns the evidences which haven't been submitted.
+func (api *API) PendingEvidences() ([]*Evidence, error) {
+	return api.evidences(false)
+}
+
+// SubmittedEvidences returns the evidences which have been submitted.
+func (api *API) SubmittedEvidences() ([]*Evidence, error) {
+	return api.evidences(true)
+}
+
+func (api *API) evidences(submitted bool) ([]*Evidence, error) {
+	results := make([]*Evidence, 0)
+	for _, evidence := range rawdb.ReadAllDoubleSignEvidences(api.s.db) {
+		if evidence.Submitted() != submitted {
+			continue
+		}
+		result, err := newDoubleSignEvidence(evidence)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	for _, evidence := range rawdb.ReadAllFinalityViolationEvidences(api.s.db) {
+		if evidence.Submitted() == submitted {
+			results = append(results, newFinalityViolationEvidence(evidence))
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Number < results[j].Number
+	})
+	return results, nil
+}
+
+func newDoubleSignEvidence(evidence *types.DoubleSignEvidence) (*Evidence, error) {
+	header1, err := rlp.EncodeToBytes(evidence.Header1)
+	if err != nil {
+		return nil, err
+	}
+	header2, err := rlp.EncodeToBytes(evidence.Header2)
+	if err != nil {
+		return nil, err
+	}
+	validator := evidence.Validator()
+	result := &Evidence{
+		Kind:          KindDoubleSign,
+		Hash:          evidence.Hash(),
+		Number:        hexutil.Uint64(evidence.Number()),
+		Validator:     &validator,
+		Header1:       header1,
+		Header2:       header2,
+		DetectedAt:    hexutil.Uint64(evidence.DetectedAt),
+		SubmittedAt:   hexutil.Uint64(evidence.SubmittedAt),
+		IncludedBlock: hexutil.Uint64(evidence.IncludedBlock),
+		Reverts:       hexutil.Uint64(evidence.Reverts),
+	}
+	if evidence.Submitted() {
+		txHash := evidence.TxHash
+		result.TxHash = &txHash
+	}
+	return result, nil
+}
+
+func newFinalityViolationEvidence(evidence *types.FinalityViolationEvidence) *Evidence {
+	result := &Evidence{
+		Kind:          KindFinalityViolation,
+		Hash:          evidence.Hash(),
+		Number:        hexutil.Uint64(evidence.Number()),
+		VoteAddress:   evidence.VoteAddress().Bytes(),
+		VoteA:         evidence.VoteA,
+		VoteB:         evidence.VoteB,
+		DetectedAt:    hexutil.Uint64(evidence.DetectedAt),
+		SubmittedAt:   hexutil.Uint64(evidence.SubmittedAt),
+		IncludedBlock: hexutil.Uint64(evidence.IncludedBlock),
+		Reverts:       hexutil.Uint64(evidence.Reverts),
+	}
+	if evidence.Submitted() {
+		txHash := evidence.TxHash
+		result.TxHash = &txHash
+	}
+	return result
+}
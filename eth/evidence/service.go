@@ -0,0 +1,508 @@
+// Package evidence implements the slashing evidence service, which watches the
+// chain headers and the votes for double signs and finality violations, keeps
+// a journal of the evidences in the database and optionally submits them to
+// the SlashIndicator system contract.
+package evidence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/monitor"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/systemcontracts"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vote"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// DefaultGasLimit is the default gas limit of the evidence submission txs
+	DefaultGasLimit = 1_000_000
+
+	// retryInterval is the interval to retry the evidences failed to be submitted
+	// and to check the inclusion of the submitted ones
+	retryInterval = time.Minute
+
+	// inclusionTimeout is the number of blocks after which a submission tx which
+	// isn't included is considered lost, and replaced by a new one
+	inclusionTimeout = 100
+
+	// replacementBump is the gas price bump in percent of a submission tx replacing
+	// one still stuck in the txpool
+	replacementBump = 25
+
+	// maxReverts is the number of reverted submission txs after which an evidence
+	// isn't submitted anymore
+	maxReverts = 3
+
+	chainHeadChanSize = 10
+	voteChanSize      = 256
+)
+
+// slashIndicatorABI is the ABI of the SlashIndicator evidence submission methods.
+const slashIndicatorABI = `[
+{"type":"function","name":"submitDoubleSignEvidence","inputs":[{"name":"header1","type":"bytes","internalType":"bytes"},{"name":"header2","type":"bytes","internalType":"bytes"}],"outputs":[],"stateMutability":"nonpayable"},
+{"type":"function","name":"submitFinalityViolationEvidence","inputs":[{"name":"_evidence","type":"tuple","internalType":"struct SlashIndicator.FinalityEvidence","components":[
+{"name":"voteA","type":"tuple","internalType":"struct SlashIndicator.VoteData","components":[{"name":"srcNum","type":"uint256","internalType":"uint256"},{"name":"srcHash","type":"bytes32","internalType":"bytes32"},{"name":"tarNum","type":"uint256","internalType":"uint256"},{"name":"tarHash","type":"bytes32","internalType":"bytes32"},{"name":"sig","type":"bytes","internalType":"bytes"}]},
+{"name":"voteB","type":"tuple","internalType":"struct SlashIndicator.VoteData","components":[{"name":"srcNum","type":"uint256","internalType":"uint256"},{"name":"srcHash","type":"bytes32","internalType":"bytes32"},{"name":"tarNum","type":"uint256","internalType":"uint256"},{"name":"tarHash","type":"bytes32","internalType":"bytes32"},{"name":"sig","type":"bytes","internalType":"bytes"}]},
+{"name":"voteAddr","type":"bytes","internalType":"bytes"}]}],"outputs":[],"stateMutability":"nonpayable"}
+]`
+
+// Config is the configuration of the evidence service.
+type Config struct {
+	DoubleSign    bool // watch the chain headers for double signs
+	MaliciousVote bool // watch the votes for finality violations
+
+	Reporter common.Address // account submitting the evidences, no submission if empty
+	GasLimit uint64         // gas limit of the submission txs, DefaultGasLimit if 0
+	GasPrice *big.Int       // gas price of the submission txs, the suggested gas tip if nil
+	DryRun   bool           // sign the submission txs without sending them
+}
+
+// Service watches the chain headers and the votes, and records the slashing
+// evidences found by the monitors.
+type Service struct {
+	config Config
+
+	chain    *core.BlockChain
+	votePool *vote.VotePool
+	db       ethdb.Database
+	txPool   *txpool.TxPool
+	accounts *accounts.Manager
+	tipCap   func(ctx context.Context) (*big.Int, error)
+	abi      abi.ABI
+
+	doubleSign    *monitor.DoubleSignMonitor
+	maliciousVote *monitor.MaliciousVoteMonitor
+
+	lock sync.Mutex // protects the evidence journal and serializes the submissions
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates the evidence service and registers it in the node.
+func New(stack *node.Node, backend *eth.Ethereum, config Config) (*Service, error) {
+	if config.MaliciousVote && backend.VotePool() == nil {
+		log.Warn("Malicious vote monitor disabled, no vote pool")
+		config.MaliciousVote = false
+	}
+	s, err := newService(backend.ChainDb(), config)
+	if err != nil {
+		return nil, err
+	}
+	s.chain = backend.BlockChain()
+	s.votePool = backend.VotePool()
+	s.txPool = backend.TxPool()
+	s.accounts = backend.AccountManager()
+	s.tipCap = backend.APIBackend.SuggestGasTipCap
+
+	stack.RegisterAPIs([]rpc.API{{
+		Namespace: "monitor",
+		Service:   &API{s},
+	}})
+	stack.RegisterLifecycle(s)
+	return s, nil
+}
+
+func newService(db ethdb.Database, config Config) (*Service, error) {
+	slashABI, err := abi.JSON(strings.NewReader(slashIndicatorABI))
+	if err != nil {
+		return nil, err
+	}
+	if config.GasLimit == 0 {
+		config.GasLimit = DefaultGasLimit
+	}
+	s := &Service{
+		config: config,
+		db:     db,
+		abi:    slashABI,
+		quit:   make(chan struct{}),
+	}
+	if config.DoubleSign {
+		s.doubleSign = monitor.NewDoubleSignMonitor()
+	}
+	if config.MaliciousVote {
+		s.maliciousVote = monitor.NewMaliciousVoteMonitor()
+	}
+	return s, nil
+}
+
+// Start implements node.Lifecycle, starting the monitors.
+func (s *Service) Start() error {
+	s.wg.Add(1)
+	go s.loop()
+
+	log.Info("Started slashing evidence service", "doubleSign", s.config.DoubleSign, "maliciousVote", s.config.MaliciousVote,
+		"reporter", s.config.Reporter, "dryRun", s.config.DryRun)
+	return nil
+}
+
+// Stop implements node.Lifecycle, terminating the monitors.
+func (s *Service) Stop() error {
+	close(s.quit)
+	s.wg.Wait()
+	return nil
+}
+
+func (s *Service) loop() {
+	defer s.wg.Done()
+
+	var (
+		headCh  = make(chan core.ChainHeadEvent, chainHeadChanSize)
+		headSub event.Subscription
+		voteCh  = make(chan core.NewVoteEvent, voteChanSize)
+		voteSub event.Subscription
+	)
+	if s.doubleSign != nil {
+		headSub = s.chain.SubscribeChainHeadEvent(headCh)
+		defer headSub.Unsubscribe()
+	}
+	if s.maliciousVote != nil {
+		voteSub = s.votePool.SubscribeNewVoteEvent(voteCh)
+		defer voteSub.Unsubscribe()
+	}
+	retry := time.NewTicker(retryInterval)
+	defer retry.Stop()
+
+	// submit the evidences left from the previous run
+	s.submitPending()
+
+	for {
+		select {
+		case ev := <-headCh:
+			if evidence := s.doubleSign.Verify(ev.Block.Header()); evidence != nil {
+				s.addDoubleSign(evidence)
+			}
+		case ev := <-voteCh:
+			pendingBlockNumber := s.chain.CurrentHeader().Number.Uint64() + 1
+			if prev := s.maliciousVote.Detect(ev.Vote, pendingBlockNumber); prev != nil {
+				s.addFinalityViolation(types.NewFinalityViolationEvidence(prev, ev.Vote, uint64(time.Now().Unix())))
+			}
+		case <-retry.C:
+			head := s.chain.CurrentHeader().Number.Uint64()
+			s.checkSubmitted(head, s.chain.Config())
+			s.submitPending()
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// addDoubleSign records a new double sign evidence and submits it, it's a noop if the
+// evidence is already known.
+func (s *Service) addDoubleSign(evidence *types.DoubleSignEvidence) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if rawdb.ReadDoubleSignEvidence(s.db, evidence.Hash()) != nil {
+		return
+	}
+	log.Warn("Found double sign evidence", "validator", evidence.Validator(), "number", evidence.Number(), "evidence", evidence.Hash())
+	rawdb.WriteDoubleSignEvidence(s.db, evidence)
+	s.submitDoubleSign(evidence, common.Hash{})
+}
+
+// addFinalityViolation records a new finality violation evidence and submits it, it's
+// a noop if the evidence is already known.
+func (s *Service) addFinalityViolation(evidence *types.FinalityViolationEvidence) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if rawdb.ReadFinalityViolationEvidence(s.db, evidence.Hash()) != nil {
+		return
+	}
+	log.Warn("Found finality violation evidence", "voteAddress", common.Bytes2Hex(evidence.VoteAddress().Bytes()),
+		"number", evidence.Number(), "evidence", evidence.Hash())
+	rawdb.WriteFinalityViolationEvidence(s.db, evidence)
+	s.submitFinalityViolation(evidence, common.Hash{})
+}
+
+// submitPending submits all the recorded evidences which haven't been submitted yet.
+// Dry runs are only done once, when an evidence is found.
+func (s *Service) submitPending() {
+	if s.config.DryRun {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, evidence := range rawdb.ReadAllDoubleSignEvidences(s.db) {
+		if !evidence.Submitted() && evidence.Reverts < maxReverts {
+			s.submitDoubleSign(evidence, common.Hash{})
+		}
+	}
+	for _, evidence := range rawdb.ReadAllFinalityViolationEvidences(s.db) {
+		if !evidence.Submitted() && evidence.Reverts < maxReverts {
+			s.submitFinalityViolation(evidence, common.Hash{})
+		}
+	}
+}
+
+// submission is the inclusion status of a submission tx.
+type submission int
+
+const (
+	submissionPending  submission = iota // not included yet, within the timeout
+	submissionIncluded                   // included successfully
+	submissionReverted                   // included but reverted
+	submissionLost                       // not included within the timeout
+)
+
+// inclusion looks up the receipt of a submission tx sent at block submitted.
+func (s *Service) inclusion(txHash common.Hash, submitted, head uint64, config *params.ChainConfig) (submission, uint64) {
+	receipt, _, number, _ := rawdb.ReadReceipt(s.db, txHash, config)
+	switch {
+	case receipt != nil && receipt.Status == types.ReceiptStatusSuccessful:
+		return submissionIncluded, number
+	case receipt != nil:
+		return submissionReverted, number
+	case head >= submitted+inclusionTimeout:
+		return submissionLost, 0
+	default:
+		return submissionPending, 0
+	}
+}
+
+// checkSubmitted tracks the inclusion of the submitted evidences by the receipts of
+// their txs. Reverted submissions are retried up to maxReverts times, the txs not
+// included within inclusionTimeout blocks are replaced.
+func (s *Service) checkSubmitted(head uint64, config *params.ChainConfig) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, evidence := range rawdb.ReadAllDoubleSignEvidences(s.db) {
+		if !evidence.Submitted() || evidence.Included() {
+			continue
+		}
+		status, number := s.inclusion(evidence.TxHash, evidence.SubmittedBlock, head, config)
+		if status == submissionPending {
+			continue
+		}
+		lost := evidence.TxHash
+		switch status {
+		case submissionIncluded:
+			evidence.IncludedBlock = number
+			log.Info("Double sign evidence included", "evidence", evidence.Hash(), "tx", evidence.TxHash, "number", number)
+		case submissionReverted:
+			evidence.Reverts++
+			log.Warn("Double sign evidence submission reverted", "evidence", evidence.Hash(), "tx", evidence.TxHash, "number", number, "reverts", evidence.Reverts)
+		case submissionLost:
+			log.Warn("Double sign evidence submission not included, resubmitting", "evidence", evidence.Hash(), "tx", evidence.TxHash)
+		}
+		if !evidence.Included() {
+			evidence.TxHash, evidence.SubmittedAt, evidence.SubmittedBlock = common.Hash{}, 0, 0
+		}
+		rawdb.WriteDoubleSignEvidence(s.db, evidence)
+
+		switch {
+		case status == submissionLost:
+			s.submitDoubleSign(evidence, lost)
+		case status == submissionReverted && evidence.Reverts < maxReverts:
+			s.submitDoubleSign(evidence, common.Hash{})
+		case status == submissionReverted:
+			log.Error("Giving up double sign evidence submission", "evidence", evidence.Hash(), "reverts", evidence.Reverts)
+		}
+	}
+	for _, evidence := range rawdb.ReadAllFinalityViolationEvidences(s.db) {
+		if !evidence.Submitted() || evidence.Included() {
+			continue
+		}
+		status, number := s.inclusion(evidence.TxHash, evidence.SubmittedBlock, head, config)
+		if status == submissionPending {
+			continue
+		}
+		lost := evidence.TxHash
+		switch status {
+		case submissionIncluded:
+			evidence.IncludedBlock = number
+			log.Info("Finality violation evidence included", "evidence", evidence.Hash(), "tx", evidence.TxHash, "number", number)
+		case submissionReverted:
+			evidence.Reverts++
+			log.Warn("Finality violation evidence submission reverted", "evidence", evidence.Hash(), "tx", evidence.TxHash, "number", number, "reverts", evidence.Reverts)
+		case submissionLost:
+			log.Warn("Finality violation evidence submission not included, resubmitting", "evidence", evidence.Hash(), "tx", evidence.TxHash)
+		}
+		if !evidence.Included() {
+			evidence.TxHash, evidence.SubmittedAt, evidence.SubmittedBlock = common.Hash{}, 0, 0
+		}
+		rawdb.WriteFinalityViolationEvidence(s.db, evidence)
+
+		switch {
+		case status == submissionLost:
+			s.submitFinalityViolation(evidence, lost)
+		case status == submissionReverted && evidence.Reverts < maxReverts:
+			s.submitFinalityViolation(evidence, common.Hash{})
+		case status == submissionReverted:
+			log.Error("Giving up finality violation evidence submission", "evidence", evidence.Hash(), "reverts", evidence.Reverts)
+		}
+	}
+}
+
+// submitDoubleSign submits the evidence, replacing the tx replace if it's still in the txpool.
+func (s *Service) submitDoubleSign(evidence *types.DoubleSignEvidence, replace common.Hash) {
+	data, err := packDoubleSignEvidence(s.abi, evidence)
+	if err == nil {
+		evidence.TxHash, err = s.submit(data, replace)
+	}
+	if err != nil {
+		log.Error("Failed to submit double sign evidence", "evidence", evidence.Hash(), "err", err)
+		return
+	}
+	if evidence.Submitted() {
+		evidence.SubmittedAt = uint64(time.Now().Unix())
+		evidence.SubmittedBlock = s.chain.CurrentHeader().Number.Uint64()
+		rawdb.WriteDoubleSignEvidence(s.db, evidence)
+		log.Info("Submitted double sign evidence", "evidence", evidence.Hash(), "tx", evidence.TxHash)
+	}
+}
+
+// submitFinalityViolation submits the evidence, replacing the tx replace if it's still in the txpool.
+func (s *Service) submitFinalityViolation(evidence *types.FinalityViolationEvidence, replace common.Hash) {
+	data, err := packFinalityViolationEvidence(s.abi, evidence)
+	if err == nil {
+		evidence.TxHash, err = s.submit(data, replace)
+	}
+	if err != nil {
+		log.Error("Failed to submit finality violation evidence", "evidence", evidence.Hash(), "err", err)
+		return
+	}
+	if evidence.Submitted() {
+		evidence.SubmittedAt = uint64(time.Now().Unix())
+		evidence.SubmittedBlock = s.chain.CurrentHeader().Number.Uint64()
+		rawdb.WriteFinalityViolationEvidence(s.db, evidence)
+		log.Info("Submitted finality violation evidence", "evidence", evidence.Hash(), "tx", evidence.TxHash)
+	}
+}
+
+// submit signs a tx calling the SlashIndicator contract with data and adds it to the txpool.
+// If the tx replace is still in the txpool, the new tx replaces it with a bumped gas price.
+// It returns an empty hash if no reporter is configured or in dry run mode.
+func (s *Service) submit(data []byte, replace common.Hash) (common.Hash, error) {
+	if s.config.Reporter == (common.Address{}) {
+		return common.Hash{}, nil
+	}
+	var stale *types.Transaction
+	if replace != (common.Hash{}) {
+		stale = s.txPool.Get(replace)
+	}
+	tx, err := s.signTx(data, stale)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if s.config.DryRun {
+		raw, _ := tx.MarshalBinary()
+		log.Info("Dry run of the evidence submission", "tx", tx.Hash(), "raw", hexutil.Encode(raw))
+		return common.Hash{}, nil
+	}
+	if err := s.txPool.Add([]*types.Transaction{tx}, true, false)[0]; err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
+
+// signTx signs a submission tx, with the nonce of stale and a bumped gas price if set.
+func (s *Service) signTx(data []byte, stale *types.Transaction) (*types.Transaction, error) {
+	account := accounts.Account{Address: s.config.Reporter}
+	wallet, err := s.accounts.Find(account)
+	if err != nil {
+		return nil, err
+	}
+	gasPrice := s.config.GasPrice
+	if gasPrice == nil {
+		if gasPrice, err = s.tipCap(context.Background()); err != nil {
+			return nil, err
+		}
+		if head := s.chain.CurrentBlock(); head != nil && head.BaseFee != nil {
+			gasPrice = new(big.Int).Add(gasPrice, head.BaseFee)
+		}
+	}
+	chainID := s.chain.Config().ChainID
+	if chainID == nil {
+		return nil, errors.New("missing chain id")
+	}
+	nonce := s.txPool.Nonce(s.config.Reporter)
+	if stale != nil {
+		nonce = stale.Nonce()
+		bumped := new(big.Int).Mul(stale.GasPrice(), big.NewInt(100+replacementBump))
+		if bumped.Div(bumped, big.NewInt(100)); bumped.Cmp(gasPrice) > 0 {
+			gasPrice = bumped
+		}
+	}
+	to := common.HexToAddress(systemcontracts.SlashContract)
+	tx := types.NewTx(&types.LegacyTx{
+		Nonce:    nonce,
+		GasPrice: gasPrice,
+		Gas:      s.config.GasLimit,
+		To:       &to,
+		Data:     data,
+	})
+	return wallet.SignTx(account, tx, chainID)
+}
+
+func packDoubleSignEvidence(slashABI abi.ABI, evidence *types.DoubleSignEvidence) ([]byte, error) {
+	header1, err := rlp.EncodeToBytes(evidence.Header1)
+	if err != nil {
+		return nil, err
+	}
+	header2, err := rlp.EncodeToBytes(evidence.Header2)
+	if err != nil {
+		return nil, err
+	}
+	return slashABI.Pack("submitDoubleSignEvidence", header1, header2)
+}
+
+// voteData is the SlashIndicator.VoteData struct.
+type voteData struct {
+	SrcNum  *big.Int
+	SrcHash [32]byte
+	TarNum  *big.Int
+	TarHash [32]byte
+	Sig     []byte
+}
+
+// finalityEvidence is the SlashIndicator.FinalityEvidence struct.
+type finalityEvidence struct {
+	VoteA    voteData
+	VoteB    voteData
+	VoteAddr []byte
+}
+
+func newVoteData(vote *types.VoteEnvelope) voteData {
+	return voteData{
+		SrcNum:  new(big.Int).SetUint64(vote.Data.SourceNumber),
+		SrcHash: vote.Data.SourceHash,
+		TarNum:  new(big.Int).SetUint64(vote.Data.TargetNumber),
+		TarHash: vote.Data.TargetHash,
+		Sig:     common.CopyBytes(vote.Signature[:]),
+	}
+}
+
+func packFinalityViolationEvidence(slashABI abi.ABI, evidence *types.FinalityViolationEvidence) ([]byte, error) {
+	if evidence.VoteA.Data == nil || evidence.VoteB.Data == nil {
+		return nil, fmt.Errorf("missing vote data in evidence %v", evidence.Hash())
+	}
+	return slashABI.Pack("submitFinalityViolationEvidence", finalityEvidence{
+		VoteA:    newVoteData(evidence.VoteA),
+		VoteB:    newVoteData(evidence.VoteB),
+		VoteAddr: evidence.VoteAddress().Bytes(),
+	})
+}
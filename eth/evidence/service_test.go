@@ -0,0 +1,141 @@
+package evidence
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func newTestVote(target uint64, targetHash common.Hash) *types.VoteEnvelope {
+	return &types.VoteEnvelope{
+		VoteAddress: types.BLSPublicKey{0x01},
+		Signature:   types.BLSSignature{0x02},
+		Data: &types.VoteData{
+			SourceNumber: target - 1,
+			SourceHash:   common.Hash{0x03},
+			TargetNumber: target,
+			TargetHash:   targetHash,
+		},
+	}
+}
+
+func TestServiceJournal(t *testing.T) {
+	s, err := newService(rawdb.NewMemoryDatabase(), Config{DoubleSign: true, MaliciousVote: true})
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	api := &API{s}
+
+	h1 := &types.Header{Number: big.NewInt(10), Coinbase: common.Address{0x01}, Difficulty: big.NewInt(2), Time: 1}
+	h2 := &types.Header{Number: big.NewInt(10), Coinbase: common.Address{0x01}, Difficulty: big.NewInt(2), Time: 2}
+	s.addDoubleSign(types.NewDoubleSignEvidence(h1, h2, 1))
+	s.addDoubleSign(types.NewDoubleSignEvidence(h2, h1, 2)) // the same pair in the other order
+
+	vote1, vote2 := newTestVote(20, common.Hash{0x04}), newTestVote(20, common.Hash{0x05})
+	s.addFinalityViolation(types.NewFinalityViolationEvidence(vote1, vote2, 3))
+	s.addFinalityViolation(types.NewFinalityViolationEvidence(vote2, vote1, 4))
+
+	pending, err := api.PendingEvidences()
+	if err != nil {
+		t.Fatalf("failed to list pending evidences: %v", err)
+	}
+	if len(pending) != 2 {
+		t.Fatalf("pending evidences mismatch: have %d, want 2", len(pending))
+	}
+	if pending[0].Kind != KindDoubleSign || pending[0].DetectedAt != 1 || *pending[0].Validator != h1.Coinbase {
+		t.Fatalf("double sign evidence mismatch: %+v", pending[0])
+	}
+	if pending[1].Kind != KindFinalityViolation || pending[1].DetectedAt != 3 || pending[1].Number != 20 {
+		t.Fatalf("finality violation evidence mismatch: %+v", pending[1])
+	}
+	if submitted, _ := api.SubmittedEvidences(); len(submitted) != 0 {
+		t.Fatalf("unexpected submitted evidences: %d", len(submitted))
+	}
+}
+
+func TestPackEvidences(t *testing.T) {
+	s, err := newService(rawdb.NewMemoryDatabase(), Config{})
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	h1 := &types.Header{Number: big.NewInt(10), Difficulty: big.NewInt(2), Time: 1}
+	h2 := &types.Header{Number: big.NewInt(10), Difficulty: big.NewInt(2), Time: 2}
+	data, err := packDoubleSignEvidence(s.abi, types.NewDoubleSignEvidence(h1, h2, 0))
+	if err != nil {
+		t.Fatalf("failed to pack double sign evidence: %v", err)
+	}
+	if method, err := s.abi.MethodById(data[:4]); err != nil || method.Name != "submitDoubleSignEvidence" {
+		t.Fatalf("double sign method mismatch: %v", err)
+	}
+
+	evidence := types.NewFinalityViolationEvidence(newTestVote(20, common.Hash{0x04}), newTestVote(20, common.Hash{0x05}), 0)
+	data, err = packFinalityViolationEvidence(s.abi, evidence)
+	if err != nil {
+		t.Fatalf("failed to pack finality violation evidence: %v", err)
+	}
+	method, err := s.abi.MethodById(data[:4])
+	if err != nil || method.Name != "submitFinalityViolationEvidence" {
+		t.Fatalf("finality violation method mismatch: %v", err)
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil || len(args) != 1 {
+		t.Fatalf("failed to unpack finality violation evidence: %v", err)
+	}
+}
+
+func TestServiceInclusion(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	s, err := newService(db, Config{DoubleSign: true})
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	// a block including a successful and a reverted submission tx
+	to := common.Address{0x01}
+	included := types.NewTx(&types.LegacyTx{Nonce: 0, To: &to, Gas: 21000, GasPrice: big.NewInt(1)})
+	reverted := types.NewTx(&types.LegacyTx{Nonce: 1, To: &to, Gas: 21000, GasPrice: big.NewInt(1)})
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(60), Difficulty: big.NewInt(2)}).WithBody([]*types.Transaction{included, reverted}, nil)
+	rawdb.WriteBlock(db, block)
+	rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+	rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), types.Receipts{
+		{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 21000, Logs: []*types.Log{}},
+		{Status: types.ReceiptStatusFailed, CumulativeGasUsed: 42000, Logs: []*types.Log{}},
+	})
+	rawdb.WriteTxLookupEntriesByBlock(db, block)
+
+	newEvidence := func(time uint64, txHash common.Hash, submitted uint64) *types.DoubleSignEvidence {
+		h1 := &types.Header{Number: big.NewInt(10), Coinbase: common.Address{0x01}, Difficulty: big.NewInt(2), Time: time}
+		h2 := &types.Header{Number: big.NewInt(10), Coinbase: common.Address{0x01}, Difficulty: big.NewInt(2), Time: time + 1}
+		evidence := types.NewDoubleSignEvidence(h1, h2, 0)
+		evidence.TxHash, evidence.SubmittedAt, evidence.SubmittedBlock = txHash, 1, submitted
+		rawdb.WriteDoubleSignEvidence(db, evidence)
+		return evidence
+	}
+	var (
+		succeeded = newEvidence(1, included.Hash(), 50)
+		failed    = newEvidence(3, reverted.Hash(), 50)
+		lost      = newEvidence(5, common.Hash{0xaa}, 50)
+		pending   = newEvidence(7, common.Hash{0xbb}, 100)
+	)
+	s.checkSubmitted(150, params.TestChainConfig)
+
+	if evidence := rawdb.ReadDoubleSignEvidence(db, succeeded.Hash()); !evidence.Included() || evidence.IncludedBlock != 60 || evidence.TxHash != included.Hash() {
+		t.Errorf("included evidence mismatch: %+v", evidence)
+	}
+	if evidence := rawdb.ReadDoubleSignEvidence(db, failed.Hash()); evidence.Submitted() || evidence.Included() || evidence.Reverts != 1 {
+		t.Errorf("reverted evidence mismatch: %+v", evidence)
+	}
+	if evidence := rawdb.ReadDoubleSignEvidence(db, lost.Hash()); evidence.Submitted() || evidence.Reverts != 0 {
+		t.Errorf("lost evidence not reset: %+v", evidence)
+	}
+	if evidence := rawdb.ReadDoubleSignEvidence(db, pending.Hash()); !evidence.Submitted() || evidence.Included() {
+		t.Errorf("pending evidence mismatch: %+v", evidence)
+	}
+	// the evidences are pending again, except the included one
+	if submitted, _ := (&API{s}).SubmittedEvidences(); len(submitted) != 2 {
+		t.Errorf("submitted evidences mismatch: have %d, want 2", len(submitted))
+	}
+}
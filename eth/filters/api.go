@@ -29,6 +29,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/gopool"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -193,6 +194,47 @@ func (api *FilterAPI) NewPendingTransactions(ctx context.Context, fullTx *bool)
 	return rpcSub, nil
 }
 
+// txPoolEvent is the notification of a transaction leaving, or being refused by,
+// the transaction pool.
+type txPoolEvent struct {
+	Hash       common.Hash            `json:"hash"`
+	Reason     core.TxPoolEventReason `json:"reason"`
+	ReplacedBy *common.Hash           `json:"replacedBy,omitempty"`
+}
+
+// TxpoolEvents creates a subscription that is triggered each time a transaction
+// leaves, or is refused by, the transaction pool, notifying the reason along
+// with the replacing transaction if replaced.
+func (api *FilterAPI) TxpoolEvents(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	gopool.Submit(func() {
+		events := make(chan []core.TxPoolEvent, 128)
+		eventSub := api.events.SubscribeTxPoolEvents(events)
+		defer eventSub.Unsubscribe()
+
+		for {
+			select {
+			case events := <-events:
+				for _, ev := range events {
+					notifier.Notify(rpcSub.ID, &txPoolEvent{Hash: ev.Hash, Reason: ev.Reason, ReplacedBy: ev.ReplacedBy})
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	})
+
+	return rpcSub, nil
+}
+
 // NewVotesFilter creates a filter that fetches votes that entered the vote pool.
 // It is part of the filter package since polling goes with eth_getFilterChanges.
 func (api *FilterAPI) NewVotesFilter() rpc.ID {
@@ -28,6 +28,7 @@ import (
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -65,7 +66,9 @@ type Backend interface {
 
 	CurrentHeader() *types.Header
 	ChainConfig() *params.ChainConfig
+	Engine() consensus.Engine
 	SubscribeNewTxsEvent(chan<- core.NewTxsEvent) event.Subscription
+	SubscribeTxPoolEvents(chan<- []core.TxPoolEvent) event.Subscription
 	SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription
 	SubscribeFinalizedHeaderEvent(ch chan<- core.FinalizedHeaderEvent) event.Subscription
 	SubscribeRemovedLogsEvent(ch chan<- core.RemovedLogsEvent) event.Subscription
@@ -166,6 +169,9 @@ const (
 	VotesSubscription
 	// FinalizedHeadersSubscription queries hashes for finalized headers that are reached
 	FinalizedHeadersSubscription
+	// TxPoolEventsSubscription queries for transactions leaving, or refused by,
+	// the transaction pool
+	TxPoolEventsSubscription
 	// LastIndexSubscription keeps track of the last index
 	LastIndexSubscription
 )
@@ -185,6 +191,8 @@ const (
 	// voteChanSize is the size of channel listening to NewVoteEvent.
 	// The number is referenced from the size of vote pool.
 	voteChanSize = 256
+	// txPoolEventChanSize is the size of channel listening to TxPoolEvent batches.
+	txPoolEventChanSize = 64
 )
 
 type subscription struct {
@@ -196,6 +204,7 @@ type subscription struct {
 	txs       chan []*types.Transaction
 	headers   chan *types.Header
 	votes     chan *types.VoteEnvelope
+	txEvents  chan []core.TxPoolEvent
 	installed chan struct{} // closed when the filter is installed
 	err       chan error    // closed when the filter is uninstalled
 }
@@ -214,6 +223,7 @@ type EventSystem struct {
 	chainSub           event.Subscription // Subscription for new chain event
 	finalizedHeaderSub event.Subscription // Subscription for new finalized header
 	voteSub            event.Subscription // Subscription for new vote event
+	txPoolEventSub     event.Subscription // Subscription for transaction pool event
 
 	// Channels
 	install           chan *subscription             // install filter for event notification
@@ -225,6 +235,7 @@ type EventSystem struct {
 	chainCh           chan core.ChainEvent           // Channel to receive new chain event
 	finalizedHeaderCh chan core.FinalizedHeaderEvent // Channel to receive new finalized header event
 	voteCh            chan core.NewVoteEvent         // Channel to receive new vote event
+	txPoolEventCh     chan []core.TxPoolEvent        // Channel to receive transaction pool event
 }
 
 // NewEventSystem creates a new manager that listens for event on the given mux,
@@ -246,6 +257,7 @@ func NewEventSystem(sys *FilterSystem) *EventSystem {
 		chainCh:           make(chan core.ChainEvent, chainEvChanSize),
 		finalizedHeaderCh: make(chan core.FinalizedHeaderEvent, finalizedHeaderEvChanSize),
 		voteCh:            make(chan core.NewVoteEvent, voteChanSize),
+		txPoolEventCh:     make(chan []core.TxPoolEvent, txPoolEventChanSize),
 	}
 
 	// Subscribe events
@@ -256,6 +268,7 @@ func NewEventSystem(sys *FilterSystem) *EventSystem {
 	m.pendingLogsSub = m.backend.SubscribePendingLogsEvent(m.pendingLogsCh)
 	m.finalizedHeaderSub = m.backend.SubscribeFinalizedHeaderEvent(m.finalizedHeaderCh)
 	m.voteSub = m.backend.SubscribeNewVoteEvent(m.voteCh)
+	m.txPoolEventSub = m.backend.SubscribeTxPoolEvents(m.txPoolEventCh)
 
 	// Make sure none of the subscriptions are empty
 	if m.txsSub == nil || m.logsSub == nil || m.rmLogsSub == nil || m.chainSub == nil || m.pendingLogsSub == nil {
@@ -264,6 +277,9 @@ func NewEventSystem(sys *FilterSystem) *EventSystem {
 	if m.voteSub == nil || m.finalizedHeaderSub == nil {
 		log.Warn("Subscribe for vote or finalized header event failed")
 	}
+	if m.txPoolEventSub == nil {
+		log.Warn("Subscribe for transaction pool event failed")
+	}
 
 	go m.eventLoop()
 	return m
@@ -298,6 +314,7 @@ func (sub *Subscription) Unsubscribe() {
 			case <-sub.f.txs:
 			case <-sub.f.headers:
 			case <-sub.f.votes:
+			case <-sub.f.txEvents:
 			}
 		}
 
@@ -369,6 +386,7 @@ func (es *EventSystem) subscribeMinedPendingLogs(crit ethereum.FilterQuery, logs
 		txs:       make(chan []*types.Transaction),
 		headers:   make(chan *types.Header),
 		votes:     make(chan *types.VoteEnvelope),
+		txEvents:  make(chan []core.TxPoolEvent),
 		installed: make(chan struct{}),
 		err:       make(chan error),
 	}
@@ -387,6 +405,7 @@ func (es *EventSystem) subscribeLogs(crit ethereum.FilterQuery, logs chan []*typ
 		txs:       make(chan []*types.Transaction),
 		headers:   make(chan *types.Header),
 		votes:     make(chan *types.VoteEnvelope),
+		txEvents:  make(chan []core.TxPoolEvent),
 		installed: make(chan struct{}),
 		err:       make(chan error),
 	}
@@ -405,6 +424,7 @@ func (es *EventSystem) subscribePendingLogs(crit ethereum.FilterQuery, logs chan
 		txs:       make(chan []*types.Transaction),
 		headers:   make(chan *types.Header),
 		votes:     make(chan *types.VoteEnvelope),
+		txEvents:  make(chan []core.TxPoolEvent),
 		installed: make(chan struct{}),
 		err:       make(chan error),
 	}
@@ -422,6 +442,7 @@ func (es *EventSystem) SubscribeNewHeads(headers chan *types.Header) *Subscripti
 		txs:       make(chan []*types.Transaction),
 		headers:   headers,
 		votes:     make(chan *types.VoteEnvelope),
+		txEvents:  make(chan []core.TxPoolEvent),
 		installed: make(chan struct{}),
 		err:       make(chan error),
 	}
@@ -439,6 +460,7 @@ func (es *EventSystem) SubscribeNewFinalizedHeaders(headers chan *types.Header)
 		txs:       make(chan []*types.Transaction),
 		headers:   headers,
 		votes:     make(chan *types.VoteEnvelope),
+		txEvents:  make(chan []core.TxPoolEvent),
 		installed: make(chan struct{}),
 		err:       make(chan error),
 	}
@@ -456,6 +478,25 @@ func (es *EventSystem) SubscribePendingTxs(txs chan []*types.Transaction) *Subsc
 		txs:       txs,
 		headers:   make(chan *types.Header),
 		votes:     make(chan *types.VoteEnvelope),
+		txEvents:  make(chan []core.TxPoolEvent),
+		installed: make(chan struct{}),
+		err:       make(chan error),
+	}
+	return es.subscribe(sub)
+}
+
+// SubscribeTxPoolEvents creates a subscription that writes the events of
+// transactions leaving, or refused by, the transaction pool.
+func (es *EventSystem) SubscribeTxPoolEvents(events chan []core.TxPoolEvent) *Subscription {
+	sub := &subscription{
+		id:        rpc.NewID(),
+		typ:       TxPoolEventsSubscription,
+		created:   time.Now(),
+		logs:      make(chan []*types.Log),
+		txs:       make(chan []*types.Transaction),
+		headers:   make(chan *types.Header),
+		votes:     make(chan *types.VoteEnvelope),
+		txEvents:  events,
 		installed: make(chan struct{}),
 		err:       make(chan error),
 	}
@@ -473,6 +514,7 @@ func (es *EventSystem) SubscribeNewVotes(votes chan *types.VoteEnvelope) *Subscr
 		txs:       make(chan []*types.Transaction),
 		headers:   make(chan *types.Header),
 		votes:     votes,
+		txEvents:  make(chan []core.TxPoolEvent),
 		installed: make(chan struct{}),
 		err:       make(chan error),
 	}
@@ -511,6 +553,12 @@ func (es *EventSystem) handleTxsEvent(filters filterIndex, ev core.NewTxsEvent)
 	}
 }
 
+func (es *EventSystem) handleTxPoolEvent(filters filterIndex, ev []core.TxPoolEvent) {
+	for _, f := range filters[TxPoolEventsSubscription] {
+		f.txEvents <- ev
+	}
+}
+
 func (es *EventSystem) handleVoteEvent(filters filterIndex, ev core.NewVoteEvent) {
 	for _, f := range filters[VotesSubscription] {
 		f.votes <- ev.Vote
@@ -542,6 +590,9 @@ func (es *EventSystem) eventLoop() {
 		if es.voteSub != nil {
 			es.voteSub.Unsubscribe()
 		}
+		if es.txPoolEventSub != nil {
+			es.txPoolEventSub.Unsubscribe()
+		}
 	}()
 
 	index := make(filterIndex)
@@ -549,10 +600,13 @@ func (es *EventSystem) eventLoop() {
 		index[i] = make(map[rpc.ID]*subscription)
 	}
 
-	var voteSubErr <-chan error
+	var voteSubErr, txPoolEventSubErr <-chan error
 	if es.voteSub != nil {
 		voteSubErr = es.voteSub.Err()
 	}
+	if es.txPoolEventSub != nil {
+		txPoolEventSubErr = es.txPoolEventSub.Err()
+	}
 	for {
 		select {
 		case ev := <-es.txsCh:
@@ -569,6 +623,8 @@ func (es *EventSystem) eventLoop() {
 			es.handleFinalizedHeaderEvent(index, ev)
 		case ev := <-es.voteCh:
 			es.handleVoteEvent(index, ev)
+		case ev := <-es.txPoolEventCh:
+			es.handleTxPoolEvent(index, ev)
 
 		case f := <-es.install:
 			if f.typ == MinedAndPendingLogsSubscription {
@@ -603,6 +659,8 @@ func (es *EventSystem) eventLoop() {
 			return
 		case <-voteSubErr:
 			return
+		case <-txPoolEventSubErr:
+			return
 		}
 	}
 }
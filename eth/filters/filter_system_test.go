@@ -29,6 +29,7 @@ import (
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
@@ -51,6 +52,7 @@ type testBackend struct {
 	chainFeed           event.Feed
 	finalizedHeaderFeed event.Feed
 	voteFeed            event.Feed
+	txPoolEventFeed     event.Feed
 	pendingBlock        *types.Block
 	pendingReceipts     types.Receipts
 }
@@ -59,6 +61,10 @@ func (b *testBackend) ChainConfig() *params.ChainConfig {
 	return params.TestChainConfig
 }
 
+func (b *testBackend) Engine() consensus.Engine {
+	return ethash.NewFaker()
+}
+
 func (b *testBackend) CurrentHeader() *types.Header {
 	hdr, _ := b.HeaderByNumber(context.TODO(), rpc.LatestBlockNumber)
 	return hdr
@@ -129,6 +135,10 @@ func (b *testBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Sub
 	return b.txFeed.Subscribe(ch)
 }
 
+func (b *testBackend) SubscribeTxPoolEvents(ch chan<- []core.TxPoolEvent) event.Subscription {
+	return b.txPoolEventFeed.Subscribe(ch)
+}
+
 func (b *testBackend) SubscribeRemovedLogsEvent(ch chan<- core.RemovedLogsEvent) event.Subscription {
 	return b.rmLogsFeed.Subscribe(ch)
 }
@@ -918,3 +928,33 @@ func TestVoteSubscription(t *testing.T) {
 
 	<-sub0.Err()
 }
+
+// TestTxPoolEventsSubscription tests whether the events of transactions leaving
+// the pool are delivered to the subscribers.
+func TestTxPoolEventsSubscription(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db           = rawdb.NewMemoryDatabase()
+		backend, sys = newTestFilterSystem(t, db, Config{})
+		api          = NewFilterAPI(sys, false)
+		replacement  = common.HexToHash("0x02")
+		events       = []core.TxPoolEvent{
+			{Hash: common.HexToHash("0x01"), Reason: core.TxReplaced, ReplacedBy: &replacement},
+			{Hash: common.HexToHash("0x03"), Reason: core.TxMined},
+		}
+	)
+	chan0 := make(chan []core.TxPoolEvent)
+	sub0 := api.events.SubscribeTxPoolEvents(chan0)
+	defer sub0.Unsubscribe()
+
+	backend.txPoolEventFeed.Send(events)
+	select {
+	case have := <-chan0:
+		if !reflect.DeepEqual(have, events) {
+			t.Fatalf("invalid events, want %v, got %v", events, have)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for transaction pool events")
+	}
+}
@@ -0,0 +1,130 @@
+package filters
+
+import (
+	"context"
+	"errors"
+	"math/bits"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/gopool"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// maxAttestationLookback is the number of blocks searched backwards from the head
+// for the attestation which justified or finalized a block.
+const maxAttestationLookback = 32
+
+var errFinalityUnsupported = errors.New("fast finality is not supported by the consensus engine")
+
+// Types of the finality events.
+const (
+	FinalityJustified = "justified"
+	FinalityFinalized = "finalized"
+)
+
+// RPCAttestation is the RPC representation of a vote attestation.
+type RPCAttestation struct {
+	BlockNumber    hexutil.Uint64 `json:"blockNumber"` // block including the attestation
+	BlockHash      common.Hash    `json:"blockHash"`
+	VoteAddressSet hexutil.Uint64 `json:"voteAddressSet"` // bitset of the voted validators
+	Votes          int            `json:"votes"`
+	SourceNumber   hexutil.Uint64 `json:"sourceNumber"`
+	SourceHash     common.Hash    `json:"sourceHash"`
+	TargetNumber   hexutil.Uint64 `json:"targetNumber"`
+	TargetHash     common.Hash    `json:"targetHash"`
+}
+
+// FinalityEvent is sent to the finality subscribers once the justified or the
+// finalized block changes.
+type FinalityEvent struct {
+	Type        string          `json:"type"`
+	Number      hexutil.Uint64  `json:"number"`
+	Hash        common.Hash     `json:"hash"`
+	Head        hexutil.Uint64  `json:"head"`
+	Attestation *RPCAttestation `json:"attestation,omitempty"`
+}
+
+// Finality sends a notification each time the justified or the finalized block
+// changes, along with the attestation which caused it.
+func (api *FilterAPI) Finality(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	posa, ok := api.sys.backend.Engine().(consensus.PoSA)
+	if !ok {
+		return &rpc.Subscription{}, errFinalityUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	// Follow the heads before returning, so no change is missed after subscribing
+	headers := make(chan *types.Header)
+	headersSub := api.events.SubscribeNewHeads(headers)
+
+	var justified, finalized common.Hash
+	if header, _ := api.sys.backend.HeaderByNumber(context.Background(), rpc.SafeBlockNumber); header != nil {
+		justified = header.Hash()
+	}
+	if header, _ := api.sys.backend.HeaderByNumber(context.Background(), rpc.FinalizedBlockNumber); header != nil {
+		finalized = header.Hash()
+	}
+	gopool.Submit(func() {
+		defer headersSub.Unsubscribe()
+
+		for {
+			select {
+			case head := <-headers:
+				if header, _ := api.sys.backend.HeaderByNumber(context.Background(), rpc.SafeBlockNumber); header != nil && header.Hash() != justified {
+					justified = header.Hash()
+					notifier.Notify(rpcSub.ID, api.newFinalityEvent(posa, FinalityJustified, head, header, func(data *types.VoteData) bool {
+						return data.TargetHash == header.Hash()
+					}))
+				}
+				if header, _ := api.sys.backend.HeaderByNumber(context.Background(), rpc.FinalizedBlockNumber); header != nil && header.Hash() != finalized {
+					finalized = header.Hash()
+					notifier.Notify(rpcSub.ID, api.newFinalityEvent(posa, FinalityFinalized, head, header, func(data *types.VoteData) bool {
+						return data.SourceHash == header.Hash()
+					}))
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	})
+
+	return rpcSub, nil
+}
+
+// newFinalityEvent creates a finality event, looking for the latest attestation
+// matching the block in the recent ancestors of the head.
+func (api *FilterAPI) newFinalityEvent(posa consensus.PoSA, typ string, head, header *types.Header, match func(*types.VoteData) bool) *FinalityEvent {
+	event := &FinalityEvent{
+		Type:   typ,
+		Number: hexutil.Uint64(header.Number.Uint64()),
+		Hash:   header.Hash(),
+		Head:   hexutil.Uint64(head.Number.Uint64()),
+	}
+	ancestor := head
+	for i := 0; i < maxAttestationLookback && ancestor != nil && ancestor.Number.Cmp(header.Number) > 0; i++ {
+		if attestation, err := posa.GetVoteAttestation(ancestor); err == nil && attestation != nil && match(attestation.Data) {
+			event.Attestation = &RPCAttestation{
+				BlockNumber:    hexutil.Uint64(ancestor.Number.Uint64()),
+				BlockHash:      ancestor.Hash(),
+				VoteAddressSet: hexutil.Uint64(attestation.VoteAddressSet),
+				Votes:          bits.OnesCount64(uint64(attestation.VoteAddressSet)),
+				SourceNumber:   hexutil.Uint64(attestation.Data.SourceNumber),
+				SourceHash:     attestation.Data.SourceHash,
+				TargetNumber:   hexutil.Uint64(attestation.Data.TargetNumber),
+				TargetHash:     attestation.Data.TargetHash,
+			}
+			break
+		}
+		ancestor, _ = api.sys.backend.HeaderByHash(context.Background(), ancestor.ParentHash)
+	}
+	return event
+}
@@ -0,0 +1,161 @@
+package filters
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// finalityTestBackend is a test backend with a fast finality engine, whose safe
+// and finalized blocks are set by the test.
+type finalityTestBackend struct {
+	*testBackend
+	engine *finalityTestEngine
+
+	lock      sync.Mutex
+	safe      *types.Header
+	finalized *types.Header
+}
+
+func (b *finalityTestBackend) Engine() consensus.Engine {
+	return b.engine
+}
+
+func (b *finalityTestBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	switch number {
+	case rpc.SafeBlockNumber:
+		return b.safe, nil
+	case rpc.FinalizedBlockNumber:
+		return b.finalized, nil
+	}
+	return b.testBackend.HeaderByNumber(ctx, number)
+}
+
+func (b *finalityTestBackend) setFinality(safe, finalized *types.Header) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.safe, b.finalized = safe, finalized
+}
+
+// finalityTestEngine is a fast finality engine serving the given attestations only.
+type finalityTestEngine struct {
+	consensus.PoSA
+	attestations map[uint64]*types.VoteAttestation
+}
+
+func (e *finalityTestEngine) GetVoteAttestation(header *types.Header) (*types.VoteAttestation, error) {
+	return e.attestations[header.Number.Uint64()], nil
+}
+
+func TestFinalitySubscription(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		genesis = &core.Genesis{
+			Config:  params.TestChainConfig,
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		_, chain, _ = core.GenerateChainWithGenesis(genesis, ethash.NewFaker(), 5, func(i int, gen *core.BlockGen) {})
+	)
+	for _, block := range chain {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+	}
+	// Block 3 justifies block 2 and finalizes block 1
+	attestation := &types.VoteAttestation{
+		VoteAddressSet: 0b101,
+		Data: &types.VoteData{
+			SourceNumber: 1,
+			SourceHash:   chain[0].Hash(),
+			TargetNumber: 2,
+			TargetHash:   chain[1].Hash(),
+		},
+	}
+	backend := &finalityTestBackend{
+		testBackend: &testBackend{db: db},
+		engine:      &finalityTestEngine{attestations: map[uint64]*types.VoteAttestation{3: attestation}},
+	}
+	api := NewFilterAPI(NewFilterSystem(backend, Config{}), false)
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("eth", api); err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	events := make(chan *FinalityEvent)
+	sub, err := client.Subscribe(context.Background(), "eth", events, "finality")
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	next := func() *FinalityEvent {
+		select {
+		case event := <-events:
+			return event
+		case err := <-sub.Err():
+			t.Fatalf("subscription failed: %v", err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("finality event timeout")
+		}
+		return nil
+	}
+	head := func(number int) {
+		backend.chainFeed.Send(core.ChainEvent{Block: chain[number-1], Hash: chain[number-1].Hash()})
+	}
+	// Both blocks changing should be notified, along with the attestation
+	backend.setFinality(chain[1].Header(), chain[0].Header())
+	head(3)
+
+	event := next()
+	if event.Type != FinalityJustified || event.Number != 2 || event.Hash != chain[1].Hash() || event.Head != 3 {
+		t.Fatalf("justified event mismatch: %+v", event)
+	}
+	if event.Attestation == nil || event.Attestation.BlockNumber != 3 || event.Attestation.Votes != 2 || event.Attestation.TargetHash != chain[1].Hash() {
+		t.Fatalf("justified attestation mismatch: %+v", event.Attestation)
+	}
+	event = next()
+	if event.Type != FinalityFinalized || event.Number != 1 || event.Hash != chain[0].Hash() {
+		t.Fatalf("finalized event mismatch: %+v", event)
+	}
+	if event.Attestation == nil || event.Attestation.BlockNumber != 3 || event.Attestation.SourceHash != chain[0].Hash() {
+		t.Fatalf("finalized attestation mismatch: %+v", event.Attestation)
+	}
+
+	// Only the changed block should be notified, even without an attestation
+	backend.setFinality(chain[3].Header(), chain[0].Header())
+	head(5)
+
+	event = next()
+	if event.Type != FinalityJustified || event.Number != 4 || event.Head != 5 {
+		t.Fatalf("justified event mismatch: %+v", event)
+	}
+	if event.Attestation != nil {
+		t.Fatalf("unexpected attestation: %+v", event.Attestation)
+	}
+	// Heads leaving the finality unchanged shouldn't be notified
+	head(5)
+	select {
+	case event := <-events:
+		t.Fatalf("unexpected event: %+v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
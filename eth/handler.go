@@ -30,7 +30,6 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/beacon"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/forkid"
-	"github.com/ethereum/go-ethereum/core/monitor"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -95,6 +94,10 @@ type txPool interface {
 	// SubscribeReannoTxsEvent should return an event subscription of
 	// ReannoTxsEvent and send events to the given channel.
 	SubscribeReannoTxsEvent(chan<- core.ReannoTxsEvent) event.Subscription
+
+	// IsPrivate returns whether the transaction with the given hash was
+	// submitted privately, so must never be propagated to peers.
+	IsPrivate(hash common.Hash) bool
 }
 
 // votePool defines the methods needed from a votes pool implementation to
@@ -135,15 +138,14 @@ type handler struct {
 	synced          atomic.Bool // Flag whether we're considered synchronised (enables transaction processing)
 	directBroadcast bool
 
-	database             ethdb.Database
-	txpool               txPool
-	votepool             votePool
-	maliciousVoteMonitor *monitor.MaliciousVoteMonitor
-	chain                *core.BlockChain
-	maxPeers             int
-	maxPeersPerIP        int
-	peersPerIP           map[string]int
-	peerPerIPLock        sync.Mutex
+	database      ethdb.Database
+	txpool        txPool
+	votepool      votePool
+	chain         *core.BlockChain
+	maxPeers      int
+	maxPeersPerIP int
+	peersPerIP    map[string]int
+	peerPerIPLock sync.Mutex
 
 	downloader   *downloader.Downloader
 	blockFetcher *fetcher.BlockFetcher
@@ -151,15 +153,14 @@ type handler struct {
 	peers        *peerSet
 	merger       *consensus.Merger
 
-	eventMux       *event.TypeMux
-	txsCh          chan core.NewTxsEvent
-	txsSub         event.Subscription
-	reannoTxsCh    chan core.ReannoTxsEvent
-	reannoTxsSub   event.Subscription
-	minedBlockSub  *event.TypeMuxSubscription
-	voteCh         chan core.NewVoteEvent
-	votesSub       event.Subscription
-	voteMonitorSub event.Subscription
+	eventMux      *event.TypeMux
+	txsCh         chan core.NewTxsEvent
+	txsSub        event.Subscription
+	reannoTxsCh   chan core.ReannoTxsEvent
+	reannoTxsSub  event.Subscription
+	minedBlockSub *event.TypeMuxSubscription
+	voteCh        chan core.NewVoteEvent
+	votesSub      event.Subscription
 
 	requiredBlocks map[uint64]common.Hash
 
@@ -715,10 +716,6 @@ func (h *handler) Start(maxPeers int, maxPeersPerIP int) {
 		h.votesSub = h.votepool.SubscribeNewVoteEvent(h.voteCh)
 		go h.voteBroadcastLoop()
 
-		if h.maliciousVoteMonitor != nil {
-			h.wg.Add(1)
-			go h.startMaliciousVoteMonitor()
-		}
 	}
 
 	// announce local pending transactions again
@@ -741,32 +738,12 @@ func (h *handler) Start(maxPeers int, maxPeersPerIP int) {
 	go h.protoTracker()
 }
 
-func (h *handler) startMaliciousVoteMonitor() {
-	defer h.wg.Done()
-	voteCh := make(chan core.NewVoteEvent, voteChanSize)
-	h.voteMonitorSub = h.votepool.SubscribeNewVoteEvent(voteCh)
-	for {
-		select {
-		case event := <-voteCh:
-			pendingBlockNumber := h.chain.CurrentHeader().Number.Uint64() + 1
-			h.maliciousVoteMonitor.ConflictDetect(event.Vote, pendingBlockNumber)
-		case <-h.voteMonitorSub.Err():
-			return
-		case <-h.stopCh:
-			return
-		}
-	}
-}
-
 func (h *handler) Stop() {
 	h.txsSub.Unsubscribe()        // quits txBroadcastLoop
 	h.reannoTxsSub.Unsubscribe()  // quits txReannounceLoop
 	h.minedBlockSub.Unsubscribe() // quits blockBroadcastLoop
 	if h.votepool != nil {
 		h.votesSub.Unsubscribe() // quits voteBroadcastLoop
-		if h.maliciousVoteMonitor != nil {
-			h.voteMonitorSub.Unsubscribe()
-		}
 	}
 	close(h.stopCh)
 	// Quit chainSync and txsync64.
@@ -853,6 +830,9 @@ func (h *handler) BroadcastTransactions(txs types.Transactions) {
 	)
 	// Broadcast transactions to a batch of peers not knowing about it
 	for _, tx := range txs {
+		if h.txpool.IsPrivate(tx.Hash()) {
+			continue
+		}
 		peers := h.peers.peersWithoutTransaction(tx.Hash())
 
 		var numDirect int
@@ -892,8 +872,14 @@ func (h *handler) BroadcastTransactions(txs types.Transactions) {
 func (h *handler) ReannounceTransactions(txs types.Transactions) {
 	hashes := make([]common.Hash, 0, txs.Len())
 	for _, tx := range txs {
+		if h.txpool.IsPrivate(tx.Hash()) {
+			continue
+		}
 		hashes = append(hashes, tx.Hash())
 	}
+	if len(hashes) == 0 {
+		return
+	}
 
 	// Announce transactions hash to a batch of peers
 	peersCount := uint(math.Sqrt(float64(h.peers.len())))
@@ -34,7 +34,18 @@ import (
 type ethHandler handler
 
 func (h *ethHandler) Chain() *core.BlockChain { return h.chain }
-func (h *ethHandler) TxPool() eth.TxPool      { return h.txpool }
+func (h *ethHandler) TxPool() eth.TxPool      { return publicTxPool{h.txpool} }
+
+// publicTxPool hides the private transactions of the pool from the peers.
+type publicTxPool struct{ txPool }
+
+// Get retrieves a transaction from the pool unless it's a private one.
+func (p publicTxPool) Get(hash common.Hash) *types.Transaction {
+	if p.IsPrivate(hash) {
+		return nil
+	}
+	return p.txPool.Get(hash)
+}
 
 // RunPeer is invoked when a peer joins on the `eth` protocol.
 func (h *ethHandler) RunPeer(peer *eth.Peer, hand eth.Handler) error {
@@ -536,6 +536,82 @@ func testTransactionPropagation(t *testing.T, protocol uint) {
 	}
 }
 
+// Tests that private transactions are neither broadcast nor announced to peers,
+// while the public ones submitted after them still propagate.
+func TestPrivateTransactionNoPropagation(t *testing.T) {
+	t.Parallel()
+
+	source := newTestHandler()
+	source.handler.snapSync.Store(false) // Avoid requiring snap, otherwise some will be dropped below
+	defer source.close()
+
+	sinks := make([]*testHandler, 4)
+	for i := 0; i < len(sinks); i++ {
+		sinks[i] = newTestHandler()
+		defer sinks[i].close()
+
+		sinks[i].handler.synced.Store(true) // mark synced to accept transactions
+	}
+	for i, sink := range sinks {
+		sink := sink // Closure for gorotuine below
+
+		sourcePipe, sinkPipe := p2p.MsgPipe()
+		defer sourcePipe.Close()
+		defer sinkPipe.Close()
+
+		sourcePeer := eth.NewPeer(eth.ETH68, p2p.NewPeerPipe(enode.ID{byte(i + 1)}, "", nil, sourcePipe), sourcePipe, source.txpool)
+		sinkPeer := eth.NewPeer(eth.ETH68, p2p.NewPeerPipe(enode.ID{0}, "", nil, sinkPipe), sinkPipe, sink.txpool)
+		defer sourcePeer.Close()
+		defer sinkPeer.Close()
+
+		go source.handler.runEthPeer(sourcePeer, func(peer *eth.Peer) error {
+			return eth.Handle((*ethHandler)(source.handler), peer)
+		})
+		go sink.handler.runEthPeer(sinkPeer, func(peer *eth.Peer) error {
+			return eth.Handle((*ethHandler)(sink.handler), peer)
+		})
+	}
+	txChs := make([]chan core.NewTxsEvent, len(sinks))
+	for i := 0; i < len(sinks); i++ {
+		txChs[i] = make(chan core.NewTxsEvent, 1024)
+
+		sub := sinks[i].txpool.SubscribeTransactions(txChs[i], false)
+		defer sub.Unsubscribe()
+	}
+	txs := make([]*types.Transaction, 64)
+	for nonce := range txs {
+		tx := types.NewTransaction(uint64(nonce), common.Address{}, big.NewInt(0), 100000, big.NewInt(0), nil)
+		tx, _ = types.SignTx(tx, types.HomesteadSigner{}, testKey)
+		txs[nonce] = tx
+	}
+	private, public := txs[:32], txs[32:]
+	source.txpool.AddPrivate(private)
+	source.txpool.Add(public, false, false)
+
+	// Wait for the public transactions, none of the private ones may show up
+	for i := range sinks {
+		for arrived, timeout := 0, false; arrived < len(public) && !timeout; {
+			select {
+			case event := <-txChs[i]:
+				for _, tx := range event.Txs {
+					if source.txpool.IsPrivate(tx.Hash()) {
+						t.Fatalf("sink %d: private transaction %x propagated", i, tx.Hash())
+					}
+				}
+				arrived += len(event.Txs)
+			case <-time.After(2 * time.Second):
+				t.Errorf("sink %d: transaction propagation timed out: have %d, want %d", i, arrived, len(public))
+				timeout = true
+			}
+		}
+		for _, tx := range private {
+			if sinks[i].txpool.Has(tx.Hash()) {
+				t.Errorf("sink %d: private transaction %x known", i, tx.Hash())
+			}
+		}
+	}
+}
+
 // Tests that local pending transactions get propagated to peers.
 func TestTransactionPendingReannounce(t *testing.T) {
 	t.Parallel()
@@ -58,6 +58,8 @@ var (
 type testTxPool struct {
 	pool map[common.Hash]*types.Transaction // Hash map of collected transactions
 
+	private map[common.Hash]struct{} // Hashes of the transactions submitted privately
+
 	txFeed       event.Feed   // Notification feed to allow waiting for inclusion
 	reannoTxFeed event.Feed   // Notification feed to trigger reannouce
 	lock         sync.RWMutex // Protects the transaction pool
@@ -66,7 +68,8 @@ type testTxPool struct {
 // newTestTxPool creates a mock transaction pool.
 func newTestTxPool() *testTxPool {
 	return &testTxPool{
-		pool: make(map[common.Hash]*types.Transaction),
+		pool:    make(map[common.Hash]*types.Transaction),
+		private: make(map[common.Hash]struct{}),
 	}
 }
 
@@ -100,6 +103,27 @@ func (p *testTxPool) Add(txs []*types.Transaction, local bool, sync bool) []erro
 	return make([]error, len(txs))
 }
 
+// AddPrivate appends a batch of transactions to the pool like Add does, marking
+// them private.
+func (p *testTxPool) AddPrivate(txs []*types.Transaction) []error {
+	p.lock.Lock()
+	for _, tx := range txs {
+		p.private[tx.Hash()] = struct{}{}
+	}
+	p.lock.Unlock()
+
+	return p.Add(txs, false, false)
+}
+
+// IsPrivate returns whether the transaction was submitted privately.
+func (p *testTxPool) IsPrivate(hash common.Hash) bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	_, ok := p.private[hash]
+	return ok
+}
+
 // ReannouceTransactions announce the transactions to some peers.
 func (p *testTxPool) ReannouceTransactions(txs []*types.Transaction) []error {
 	p.lock.Lock()
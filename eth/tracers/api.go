@@ -95,6 +95,7 @@ type Backend interface {
 // API is the collection of tracing APIs exposed over the private debugging endpoint.
 type API struct {
 	backend Backend
+	cache   *TraceCache // cache of the block traces, nil if disabled
 }
 
 // NewAPI creates a new API definition for the tracing methods of the Ethereum service.
@@ -466,7 +467,9 @@ func (api *API) TraceBlockByNumber(ctx context.Context, number rpc.BlockNumber,
 	if err != nil {
 		return nil, err
 	}
-	return api.traceBlock(ctx, block, config)
+	return api.cache.traceBlock(block, config, func() ([]*txTraceResult, error) {
+		return api.traceBlock(ctx, block, config)
+	})
 }
 
 // TraceBlockByHash returns the structured logs created during the execution of
@@ -476,7 +479,9 @@ func (api *API) TraceBlockByHash(ctx context.Context, hash common.Hash, config *
 	if err != nil {
 		return nil, err
 	}
-	return api.traceBlock(ctx, block, config)
+	return api.cache.traceBlock(block, config, func() ([]*txTraceResult, error) {
+		return api.traceBlock(ctx, block, config)
+	})
 }
 
 // TraceBlock returns the structured logs created during the execution of EVM
@@ -622,29 +627,12 @@ func (api *API) StandardTraceBadBlockToFile(ctx context.Context, hash common.Has
 // executes all the transactions contained within. The return value will be one item
 // per transaction, dependent on the requested tracer.
 func (api *API) traceBlock(ctx context.Context, block *types.Block, config *TraceConfig) ([]*txTraceResult, error) {
-	if block.NumberU64() == 0 {
-		return nil, errors.New("genesis is not traceable")
-	}
-	// Prepare base state
-	parent, err := api.blockByNumberAndHash(ctx, rpc.BlockNumber(block.NumberU64()-1), block.ParentHash())
-	if err != nil {
-		return nil, err
-	}
-	reexec := defaultTraceReexec
-	if config != nil && config.Reexec != nil {
-		reexec = *config.Reexec
-	}
-	statedb, release, err := api.backend.StateAtBlock(ctx, parent, reexec, nil, true, false)
+	parent, statedb, release, err := api.blockState(ctx, block, config)
 	if err != nil {
 		return nil, err
 	}
 	defer release()
 
-	// upgrade build-in system contract before normal txs if Feynman is not enabled
-	if !api.backend.ChainConfig().IsFeynman(block.Number(), block.Time()) {
-		systemcontracts.UpgradeBuildInSystemContract(api.backend.ChainConfig(), block.Number(), parent.Time(), block.Time(), statedb)
-	}
-
 	// JS tracers have high overhead. In this case run a parallel
 	// process that generates states in one thread and traces txes
 	// in separate worker threads.
@@ -703,6 +691,32 @@ func (api *API) traceBlock(ctx context.Context, block *types.Block, config *Trac
 	return results, nil
 }
 
+// blockState returns the parent of the block along with its state, prepared to
+// execute the transactions of the block on top.
+func (api *API) blockState(ctx context.Context, block *types.Block, config *TraceConfig) (*types.Block, *state.StateDB, StateReleaseFunc, error) {
+	if block.NumberU64() == 0 {
+		return nil, nil, nil, errors.New("genesis is not traceable")
+	}
+	// Prepare base state
+	parent, err := api.blockByNumberAndHash(ctx, rpc.BlockNumber(block.NumberU64()-1), block.ParentHash())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	reexec := defaultTraceReexec
+	if config != nil && config.Reexec != nil {
+		reexec = *config.Reexec
+	}
+	statedb, release, err := api.backend.StateAtBlock(ctx, parent, reexec, nil, true, false)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	// upgrade build-in system contract before normal txs if Feynman is not enabled
+	if !api.backend.ChainConfig().IsFeynman(block.Number(), block.Time()) {
+		systemcontracts.UpgradeBuildInSystemContract(api.backend.ChainConfig(), block.Number(), parent.Time(), block.Time(), statedb)
+	}
+	return parent, statedb, release, nil
+}
+
 // traceBlockParallel is for tracers that have a high overhead (read JS tracers). One thread
 // runs along and executes txes without tracing enabled to generate their prestate.
 // Worker threads take the tasks and the prestate and trace them.
@@ -1135,13 +1149,21 @@ func (api *API) traceTx(ctx context.Context, message *core.Message, txctx *Conte
 	return tracer.GetResult()
 }
 
-// APIs return the collection of RPC services the tracer package offers.
-func APIs(backend Backend) []rpc.API {
+// APIs return the collection of RPC services the tracer package offers. The
+// block traces are served from the cache if not nil, except for trace_filter
+// which bypasses it and traces at most filterRange blocks, unless zero.
+func APIs(backend Backend, cache *TraceCache, filterRange uint64) []rpc.API {
+	api := &API{backend: backend, cache: cache}
+
 	// Append all the local APIs and return
 	return []rpc.API{
 		{
 			Namespace: "debug",
-			Service:   NewAPI(backend),
+			Service:   api,
+		},
+		{
+			Namespace: "trace",
+			Service:   &TraceAPI{api: api, filterRange: filterRange},
 		},
 	}
 }
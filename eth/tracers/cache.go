@@ -0,0 +1,367 @@
+package tracers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/tracers/logger"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	// traceCacheMemory is the memory allowance of the trace cache database, in MB.
+	traceCacheMemory = 16
+
+	// traceCacheHandles is the number of file handles of the trace cache database.
+	traceCacheHandles = 16
+
+	// chainEventChanSize is the size of the channels listening to the chain events.
+	chainEventChanSize = 16
+)
+
+var (
+	traceCacheHitMeter  = metrics.NewRegisteredMeter("eth/tracers/cache/hit", nil)
+	traceCacheMissMeter = metrics.NewRegisteredMeter("eth/tracers/cache/miss", nil)
+)
+
+// TraceCacheConfig is the configuration of the trace cache.
+type TraceCacheConfig struct {
+	Path    string   // Directory of the cache database, kept in memory if empty
+	Size    uint64   // Maximum size of the cached traces, in bytes
+	Tracers []string // Tracers run on the new heads in the background
+}
+
+// CacheBackend is the backend of the trace cache, notified of the chain events
+// to pre-trace the new heads and to drop the traces of the reorged blocks.
+type CacheBackend interface {
+	Backend
+	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
+	SubscribeChainSideEvent(ch chan<- core.ChainSideEvent) event.Subscription
+}
+
+// TraceCache persists the traces of blocks in a side database, keyed by the block
+// hash and the tracer configuration. The least recently used traces are evicted
+// beyond the size limit, the ones of the blocks reorged out of the chain dropped.
+type TraceCache struct {
+	db      ethdb.Database
+	backend CacheBackend
+	api     *API // uncached tracer of the pre-traced blocks
+	config  TraceCacheConfig
+
+	lock  sync.Mutex
+	index lru.BasicLRU[string, uint64] // sizes of the cached traces, by recency
+	size  uint64                       // total size of the cached traces
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewTraceCache opens the trace cache, indexing the traces kept from the previous
+// runs.
+func NewTraceCache(backend CacheBackend, config TraceCacheConfig) (*TraceCache, error) {
+	db := rawdb.NewMemoryDatabase()
+	if config.Path != "" {
+		var err error
+		if db, err = rawdb.NewPebbleDBDatabase(config.Path, traceCacheMemory, traceCacheHandles, "eth/db/tracecache/", false, false); err != nil {
+			return nil, err
+		}
+	}
+	c := &TraceCache{
+		db:      db,
+		backend: backend,
+		api:     NewAPI(backend),
+		config:  config,
+		index:   lru.NewBasicLRU[string, uint64](math.MaxInt),
+		quit:    make(chan struct{}),
+	}
+	it := db.NewIterator(nil, nil)
+	for it.Next() {
+		size := uint64(len(it.Key()) + len(it.Value()))
+		c.index.Add(string(it.Key()), size)
+		c.size += size
+	}
+	it.Release()
+	if err := it.Error(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	c.lock.Lock()
+	c.evict()
+	c.lock.Unlock()
+
+	log.Info("Opened trace cache", "path", config.Path, "traces", c.index.Len(), "size", common.StorageSize(c.size), "limit", common.StorageSize(config.Size))
+	return c, nil
+}
+
+// Start starts pre-tracing the new heads and dropping the reorged blocks in the
+// background.
+func (c *TraceCache) Start() error {
+	var (
+		headCh = make(chan core.ChainHeadEvent, chainEventChanSize)
+		sideCh = make(chan core.ChainSideEvent, chainEventChanSize)
+	)
+	c.wg.Add(1)
+	go c.loop(headCh, c.backend.SubscribeChainHeadEvent(headCh), sideCh, c.backend.SubscribeChainSideEvent(sideCh))
+	return nil
+}
+
+// Stop stops the background tracing and closes the database.
+func (c *TraceCache) Stop() error {
+	close(c.quit)
+	c.wg.Wait()
+	return c.db.Close()
+}
+
+// traceCacheKey returns the key of the traces of a block made with the config,
+// prefixed with the block hash to drop all the traces of a block together.
+func traceCacheKey(hash common.Hash, config *TraceConfig) ([]byte, error) {
+	var id struct {
+		Tracer       string
+		Config       *logger.Config
+		TracerConfig json.RawMessage
+	}
+	if config != nil {
+		if config.Tracer != nil {
+			id.Tracer = *config.Tracer
+		}
+		id.Config = config.Config
+		if len(config.TracerConfig) > 0 {
+			buf := new(bytes.Buffer)
+			if err := json.Compact(buf, config.TracerConfig); err != nil {
+				return nil, err
+			}
+			id.TracerConfig = buf.Bytes()
+		}
+	}
+	blob, err := json.Marshal(id)
+	if err != nil {
+		return nil, err
+	}
+	return append(hash.Bytes(), crypto.Keccak256(blob)...), nil
+}
+
+// labelsStorage reports whether the tracer labels the storage slots, e.g. with
+// the labelStorage option of the prestate tracer. The labels depend on storage
+// layouts which the cache key doesn't cover and may change across runs.
+func labelsStorage(config *TraceConfig) bool {
+	if config == nil || len(config.TracerConfig) == 0 {
+		return false
+	}
+	var options struct {
+		LabelStorage bool `json:"labelStorage"`
+	}
+	if err := json.Unmarshal(config.TracerConfig, &options); err != nil {
+		return false
+	}
+	return options.LabelStorage
+}
+
+// traceBlock returns the cached traces of the block, tracing it with the given
+// function and caching the result otherwise. Only the traces of blocks whose
+// transactions were all traced successfully are cached, and never the ones with
+// labelled storage slots.
+func (c *TraceCache) traceBlock(block *types.Block, config *TraceConfig, trace func() ([]*txTraceResult, error)) ([]*txTraceResult, error) {
+	if c == nil || labelsStorage(config) {
+		return trace()
+	}
+	key, err := traceCacheKey(block.Hash(), config)
+	if err != nil {
+		return nil, err
+	}
+	if results, ok := c.get(key); ok {
+		traceCacheHitMeter.Mark(1)
+		return results, nil
+	}
+	traceCacheMissMeter.Mark(1)
+
+	results, err := trace()
+	if err != nil {
+		return nil, err
+	}
+	c.put(key, results)
+	return results, nil
+}
+
+// get returns the cached traces of the key.
+func (c *TraceCache) get(key []byte) ([]*txTraceResult, bool) {
+	blob, err := c.db.Get(key)
+	if err != nil {
+		return nil, false
+	}
+	var cached []struct {
+		TxHash common.Hash     `json:"txHash"`
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(blob, &cached); err != nil {
+		log.Warn("Invalid cached traces", "key", common.Bytes2Hex(key), "err", err)
+		return nil, false
+	}
+	c.lock.Lock()
+	c.index.Get(string(key))
+	c.lock.Unlock()
+
+	results := make([]*txTraceResult, len(cached))
+	for i, res := range cached {
+		results[i] = &txTraceResult{TxHash: res.TxHash, Result: res.Result}
+	}
+	return results, true
+}
+
+// put caches the traces of the key, evicting the least recently used ones if
+// beyond the size limit.
+func (c *TraceCache) put(key []byte, results []*txTraceResult) {
+	for _, res := range results {
+		if res == nil || res.Error != "" {
+			return
+		}
+	}
+	blob, err := json.Marshal(results)
+	if err != nil {
+		return
+	}
+	size := uint64(len(key) + len(blob))
+	if size > c.config.Size {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.db.Put(key, blob); err != nil {
+		log.Warn("Failed to cache traces", "key", common.Bytes2Hex(key), "err", err)
+		return
+	}
+	if old, ok := c.index.Peek(string(key)); ok {
+		c.size -= old
+	}
+	c.index.Add(string(key), size)
+	c.size += size
+	c.evict()
+}
+
+// evict deletes the least recently used traces until the cache fits its limit.
+// The caller must hold the lock.
+func (c *TraceCache) evict() {
+	for c.size > c.config.Size {
+		key, size, ok := c.index.RemoveOldest()
+		if !ok {
+			return
+		}
+		if err := c.db.Delete([]byte(key)); err != nil {
+			log.Warn("Failed to evict cached traces", "key", common.Bytes2Hex([]byte(key)), "err", err)
+		}
+		c.size -= size
+	}
+}
+
+// drop deletes all the cached traces of the block.
+func (c *TraceCache) drop(hash common.Hash) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var (
+		batch   = c.db.NewBatch()
+		dropped int
+	)
+	it := c.db.NewIterator(hash.Bytes(), nil)
+	for it.Next() {
+		key := string(it.Key())
+		if size, ok := c.index.Peek(key); ok {
+			c.index.Remove(key)
+			c.size -= size
+		}
+		batch.Delete([]byte(key))
+		dropped++
+	}
+	it.Release()
+	if dropped == 0 {
+		return
+	}
+	if err := batch.Write(); err != nil {
+		log.Warn("Failed to drop cached traces", "hash", hash, "err", err)
+	}
+}
+
+// pretrace traces the block with all the configured tracers, unless cached.
+func (c *TraceCache) pretrace(ctx context.Context, block *types.Block) {
+	for _, name := range c.config.Tracers {
+		name := name
+		config := &TraceConfig{Tracer: &name}
+		_, err := c.traceBlock(block, config, func() ([]*txTraceResult, error) {
+			return c.api.traceBlock(ctx, block, config)
+		})
+		if err != nil {
+			log.Debug("Failed to pre-trace block", "number", block.Number(), "hash", block.Hash(), "tracer", name, "err", err)
+		}
+	}
+}
+
+func (c *TraceCache) loop(headCh <-chan core.ChainHeadEvent, headSub event.Subscription, sideCh <-chan core.ChainSideEvent, sideSub event.Subscription) {
+	defer c.wg.Done()
+
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		done        = make(chan struct{})
+		running     bool
+		pending     *types.Block // latest head to trace once the running trace is done
+	)
+	defer headSub.Unsubscribe()
+	defer sideSub.Unsubscribe()
+
+	// Pre-trace the heads in the background, skipping the ones superseded while
+	// tracing to not hold up the chain events
+	pretrace := func(block *types.Block) {
+		running = true
+		go func() {
+			c.pretrace(ctx, block)
+			done <- struct{}{}
+		}()
+	}
+	defer func() {
+		cancel()
+		if running {
+			<-done
+		}
+	}()
+	for {
+		select {
+		case ev := <-headCh:
+			if len(c.config.Tracers) == 0 {
+				continue
+			}
+			if running {
+				pending = ev.Block
+				continue
+			}
+			pretrace(ev.Block)
+
+		case <-done:
+			running = false
+			if pending != nil {
+				pretrace(pending)
+				pending = nil
+			}
+
+		case ev := <-sideCh:
+			c.drop(ev.Block.Hash())
+
+		case <-headSub.Err():
+			return
+		case <-sideSub.Err():
+			return
+		case <-c.quit:
+			return
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package tracers
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// cacheTestBackend is a test backend notifying the chain events of its feeds.
+type cacheTestBackend struct {
+	*testBackend
+	headFeed event.Feed
+	sideFeed event.Feed
+}
+
+func (b *cacheTestBackend) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return b.headFeed.Subscribe(ch)
+}
+
+func (b *cacheTestBackend) SubscribeChainSideEvent(ch chan<- core.ChainSideEvent) event.Subscription {
+	return b.sideFeed.Subscribe(ch)
+}
+
+// cached reports whether the traces of the block made with the config are cached.
+func (c *TraceCache) cached(t *testing.T, block *types.Block, config *TraceConfig) bool {
+	key, err := traceCacheKey(block.Hash(), config)
+	if err != nil {
+		t.Fatalf("failed to derive cache key: %v", err)
+	}
+	_, ok := c.get(key)
+	return ok
+}
+
+func TestTraceCache(t *testing.T) {
+	t.Parallel()
+
+	accounts := newAccounts(2)
+	genesis := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc: types.GenesisAlloc{
+			accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+		},
+	}
+	signer := types.HomesteadSigner{}
+	backend := &cacheTestBackend{testBackend: newTestBackend(t, 5, genesis, func(i int, b *core.BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(uint64(i), accounts[1].addr, big.NewInt(1000), params.TxGas, b.BaseFee(), nil), signer, accounts[0].key)
+		b.AddTx(tx)
+	})}
+	defer backend.chain.Stop()
+
+	var (
+		dir    = t.TempDir()
+		ctx    = context.Background()
+		blocks = make([]*types.Block, 6)
+	)
+	for i := range blocks {
+		blocks[i] = backend.chain.GetBlockByNumber(uint64(i))
+	}
+	cache, err := NewTraceCache(backend, TraceCacheConfig{Path: dir, Size: 1024 * 1024})
+	if err != nil {
+		t.Fatalf("failed to open trace cache: %v", err)
+	}
+	api := &API{backend: backend, cache: cache}
+
+	// Trace a block twice, the second trace being served from the cache
+	config := &TraceConfig{TracerConfig: json.RawMessage(`{ "a": 1 }`)}
+	want, err := api.TraceBlockByNumber(ctx, rpc.BlockNumber(1), config)
+	if err != nil {
+		t.Fatalf("failed to trace block: %v", err)
+	}
+	if !cache.cached(t, blocks[1], &TraceConfig{TracerConfig: json.RawMessage(`{"a":1}`)}) {
+		t.Fatal("block traces not cached")
+	}
+	if cache.cached(t, blocks[1], &TraceConfig{TracerConfig: json.RawMessage(`{"a":2}`)}) {
+		t.Fatal("block traces cached for another config")
+	}
+	// Storage labels depend on layouts the key doesn't cover, they aren't cached
+	prestate := "prestateTracer"
+	labelled := &TraceConfig{Tracer: &prestate, TracerConfig: json.RawMessage(`{"diffMode": true, "labelStorage": true}`)}
+	if _, err := api.TraceBlockByNumber(ctx, rpc.BlockNumber(1), labelled); err != nil {
+		t.Fatalf("failed to trace block with storage labels: %v", err)
+	}
+	if cache.cached(t, blocks[1], labelled) {
+		t.Fatal("block traces with storage labels cached")
+	}
+	have, err := api.TraceBlockByHash(ctx, blocks[1].Hash(), config)
+	if err != nil {
+		t.Fatalf("failed to trace block: %v", err)
+	}
+	haveBlob, _ := json.Marshal(have)
+	wantBlob, _ := json.Marshal(want)
+	if string(haveBlob) != string(wantBlob) {
+		t.Fatalf("cached traces mismatch\nhave %s\nwant %s", haveBlob, wantBlob)
+	}
+
+	// Reopen the cache, the traces being kept across runs
+	if err := cache.Stop(); err != nil {
+		t.Fatalf("failed to close trace cache: %v", err)
+	}
+	size := cache.size
+	cache, err = NewTraceCache(backend, TraceCacheConfig{Path: dir, Size: size * 2, Tracers: []string{flatTracer}})
+	if err != nil {
+		t.Fatalf("failed to reopen trace cache: %v", err)
+	}
+	if cache.size != size || cache.index.Len() != 1 || !cache.cached(t, blocks[1], config) {
+		t.Fatalf("traces not kept across runs, size %d want %d", cache.size, size)
+	}
+	// Trace more blocks than the cache can hold, evicting the least recently used
+	api = &API{backend: backend, cache: cache}
+	for _, n := range []int{2, 1, 3} {
+		if _, err := api.TraceBlockByNumber(ctx, rpc.BlockNumber(n), config); err != nil {
+			t.Fatalf("failed to trace block %d: %v", n, err)
+		}
+	}
+	if cache.cached(t, blocks[2], config) || !cache.cached(t, blocks[1], config) || !cache.cached(t, blocks[3], config) {
+		t.Fatal("least recently used traces not evicted")
+	}
+	if cache.size > size*2 {
+		t.Fatalf("cache size %d beyond limit %d", cache.size, size*2)
+	}
+
+	// Pre-trace the new heads and drop the traces of the reorged blocks
+	cache.config.Size = 1024 * 1024
+	if err := cache.Start(); err != nil {
+		t.Fatalf("failed to start trace cache: %v", err)
+	}
+	defer cache.Stop()
+
+	waitCached := func(block *types.Block, config *TraceConfig, want bool) {
+		t.Helper()
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+			if cache.cached(t, block, config) == want {
+				return
+			}
+		}
+		t.Fatalf("block %d cached state mismatch, want %v", block.NumberU64(), want)
+	}
+	backend.headFeed.Send(core.ChainHeadEvent{Block: blocks[4]})
+	waitCached(blocks[4], flatConfig(), true)
+
+	backend.sideFeed.Send(core.ChainSideEvent{Block: blocks[4]})
+	waitCached(blocks[4], flatConfig(), false)
+
+	backend.sideFeed.Send(core.ChainSideEvent{Block: blocks[3]})
+	waitCached(blocks[3], config, false)
+	if !cache.cached(t, blocks[1], config) {
+		t.Fatal("traces of another block dropped")
+	}
+	if _, err := api.TraceBlockByNumber(ctx, rpc.BlockNumber(0), config); err == nil {
+		t.Fatal("expected genesis tracing to fail")
+	}
+	if cache.cached(t, blocks[0], config) {
+		t.Fatal("failed trace cached")
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"math/big"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -30,6 +31,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/tests"
 )
@@ -150,3 +152,86 @@ func testPrestateDiffTracer(tracerName string, dirPath string, t *testing.T) {
 		})
 	}
 }
+
+func TestPrestateTracerLabelStorage(t *testing.T) {
+	var (
+		pair     = common.HexToAddress("0x00000000000000000000000000000000deadbeef")
+		reserves = new(big.Int).Or(big.NewInt(5), new(big.Int).Lsh(big.NewInt(7), 112))
+		layout   = `{
+			"storage": [
+				{"label": "balanceOf", "offset": 0, "slot": "1", "type": "t_mapping(t_address,t_uint256)"},
+				{"label": "reserve0", "offset": 0, "slot": "8", "type": "t_uint112"},
+				{"label": "reserve1", "offset": 14, "slot": "8", "type": "t_uint112"},
+				{"label": "blockTimestampLast", "offset": 28, "slot": "8", "type": "t_uint32"}
+			],
+			"types": {
+				"t_address": {"encoding": "inplace", "label": "address", "numberOfBytes": "20"},
+				"t_mapping(t_address,t_uint256)": {"encoding": "mapping", "key": "t_address", "label": "mapping(address => uint256)", "numberOfBytes": "32", "value": "t_uint256"},
+				"t_uint112": {"encoding": "inplace", "label": "uint112", "numberOfBytes": "14"},
+				"t_uint256": {"encoding": "inplace", "label": "uint256", "numberOfBytes": "32"},
+				"t_uint32": {"encoding": "inplace", "label": "uint32", "numberOfBytes": "4"}
+			}
+		}`
+		// Updates the reserves, then sets the balance of 0xaa
+		code = append(append([]byte{byte(vm.PUSH32)}, common.BigToHash(reserves).Bytes()...),
+			byte(vm.PUSH1), 0x8, byte(vm.SSTORE),
+			byte(vm.PUSH1), 0xaa, byte(vm.PUSH1), 0x0, byte(vm.MSTORE),
+			byte(vm.PUSH1), 0x1, byte(vm.PUSH1), 0x20, byte(vm.MSTORE),
+			byte(vm.PUSH1), 0x64, byte(vm.PUSH1), 0x40, byte(vm.PUSH1), 0x0, byte(vm.KECCAK256), byte(vm.SSTORE),
+		)
+	)
+	if _, err := tracers.DefaultDirectory.New("prestateTracer", nil, json.RawMessage(`{"labelStorage": true}`)); err == nil {
+		t.Fatal("storage labelled without diff mode")
+	}
+	// Types containing themselves are refused, not to recurse endlessly
+	cyclic := `{
+		"storage": [{"label": "node", "offset": 0, "slot": "0", "type": "t_struct(Node)"}],
+		"types": {
+			"t_struct(Node)": {"encoding": "inplace", "label": "struct Node", "numberOfBytes": "64", "members": [
+				{"label": "value", "offset": 0, "slot": "0", "type": "t_uint256"},
+				{"label": "next", "offset": 0, "slot": "1", "type": "t_struct(Node)"}
+			]},
+			"t_uint256": {"encoding": "inplace", "label": "uint256", "numberOfBytes": "32"}
+		}
+	}`
+	if _, err := tracers.DefaultDirectory.New("prestateTracer", nil, json.RawMessage(`{"diffMode": true, "labelStorage": true, "storageLayouts": {"`+pair.Hex()+`": `+cyclic+`}}`)); err == nil {
+		t.Fatal("recursive storage layout accepted")
+	}
+	config := `{"diffMode": true, "labelStorage": true, "storageLayouts": {"` + pair.Hex() + `": ` + layout + `}}`
+	tracer, err := tracers.DefaultDirectory.New("prestateTracer", nil, json.RawMessage(config))
+	if err != nil {
+		t.Fatalf("failed to create prestate tracer: %v", err)
+	}
+	res := traceMessage(t, tracer, types.GenesisAlloc{pair: {Code: code, Storage: map[common.Hash]common.Hash{{31: 8}: {31: 1}}}}, pair, new(big.Int))
+
+	type label struct {
+		Label  string      `json:"label"`
+		Type   string      `json:"type"`
+		Offset uint64      `json:"offset"`
+		Pre    interface{} `json:"pre"`
+		Post   interface{} `json:"post"`
+	}
+	var result struct {
+		Labels map[common.Address]map[common.Hash][]label `json:"labels"`
+	}
+	if err := json.Unmarshal(res, &result); err != nil {
+		t.Fatal(err)
+	}
+	var (
+		slots      = result.Labels[pair]
+		balanceKey = crypto.Keccak256Hash(common.LeftPadBytes([]byte{0xaa}, 32), common.LeftPadBytes([]byte{0x1}, 32))
+		want       = map[common.Hash][]label{
+			{31: 8}: {
+				{Label: "reserve0", Type: "uint112", Pre: "0x1", Post: "0x5"},
+				{Label: "reserve1", Type: "uint112", Offset: 14, Pre: "0x0", Post: "0x7"},
+				{Label: "blockTimestampLast", Type: "uint32", Offset: 28, Pre: "0x0", Post: "0x0"},
+			},
+			balanceKey: {
+				{Label: "balanceOf[0x00000000000000000000000000000000000000AA]", Type: "uint256", Pre: "0x0", Post: "0x64"},
+			},
+		}
+	)
+	if !reflect.DeepEqual(slots, want) {
+		t.Errorf("labels mismatch\n have: %+v\n want: %+v", slots, want)
+	}
+}
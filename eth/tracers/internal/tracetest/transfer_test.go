@@ -0,0 +1,60 @@
+package tracetest
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+)
+
+// emitTransfer returns the code emitting a Transfer event of the given amount.
+func emitTransfer(from, to byte, amount byte) []byte {
+	code := []byte{
+		byte(vm.PUSH1), amount,
+		byte(vm.PUSH1), 0x0,
+		byte(vm.MSTORE),
+		byte(vm.PUSH1), to,
+		byte(vm.PUSH1), from,
+		byte(vm.PUSH32),
+	}
+	code = append(code, crypto.Keccak256([]byte("Transfer(address,address,uint256)"))...)
+	return append(code, byte(vm.PUSH1), 0x20, byte(vm.PUSH1), 0x0, byte(vm.LOG3))
+}
+
+func TestTransferTracer(t *testing.T) {
+	var (
+		to       = common.HexToAddress("0x00000000000000000000000000000000deadbeef")
+		reverter = common.HexToAddress("0x00000000000000000000000000000000000000ee")
+
+		// Emits a transfer, then sends 1 wei to a contract emitting a transfer
+		// and reverting
+		code = append(emitTransfer(0xaa, 0xbb, 5),
+			byte(vm.PUSH1), 0x0, byte(vm.DUP1), byte(vm.DUP1), byte(vm.DUP1), // in and outs zero
+			byte(vm.PUSH1), 0x1, byte(vm.PUSH1), 0xee, byte(vm.GAS), // value=1,address=0xee, gas=GAS
+			byte(vm.CALL),
+		)
+		revertCode = append(emitTransfer(0xcc, 0xdd, 3), byte(vm.PUSH1), 0x0, byte(vm.DUP1), byte(vm.REVERT))
+	)
+	tracer, err := tracers.DefaultDirectory.New("transferTracer", nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create transfer tracer: %v", err)
+	}
+	res := traceMessage(t, tracer, types.GenesisAlloc{
+		to:       types.Account{Code: code},
+		reverter: types.Account{Code: revertCode},
+	}, to, big.NewInt(10))
+
+	want := `[` +
+		`{"token":"0x0000000000000000000000000000000000000000","from":"0x000000000000000000000000000000000000feed","to":"0x00000000000000000000000000000000deadbeef","amount":"0xa","callDepth":0},` +
+		`{"token":"0x00000000000000000000000000000000deadbeef","from":"0x00000000000000000000000000000000000000aa","to":"0x00000000000000000000000000000000000000bb","amount":"0x5","callDepth":0},` +
+		`{"token":"0x0000000000000000000000000000000000000000","from":"0x00000000000000000000000000000000deadbeef","to":"0x00000000000000000000000000000000000000ee","amount":"0x1","callDepth":1,"reverted":true},` +
+		`{"token":"0x00000000000000000000000000000000000000ee","from":"0x00000000000000000000000000000000000000cc","to":"0x00000000000000000000000000000000000000dd","amount":"0x3","callDepth":1,"reverted":true}` +
+		`]`
+	if string(res) != want {
+		t.Errorf("trace mismatch\n have: %v\n want: %v\n", string(res), want)
+	}
+}
@@ -19,6 +19,8 @@ package native
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"math/big"
 	"sync/atomic"
 
@@ -68,10 +70,14 @@ type prestateTracer struct {
 	reason    error       // Textual reason for the interruption
 	created   map[common.Address]bool
 	deleted   map[common.Address]bool
+	preimages map[common.Hash][]byte // SHA3 preimages resolving the labelled slots
+	labels    map[common.Address]map[common.Hash][]storageLabel
 }
 
 type prestateTracerConfig struct {
-	DiffMode bool `json:"diffMode"` // If true, this tracer will return state modifications
+	DiffMode       bool                              `json:"diffMode"`       // If true, this tracer will return state modifications
+	LabelStorage   bool                              `json:"labelStorage"`   // If true, the changed slots of known contracts are labelled, requires diffMode
+	StorageLayouts map[common.Address]*StorageLayout `json:"storageLayouts"` // Storage layouts used on top of the registered ones
 }
 
 func newPrestateTracer(ctx *tracers.Context, cfg json.RawMessage) (tracers.Tracer, error) {
@@ -81,13 +87,26 @@ func newPrestateTracer(ctx *tracers.Context, cfg json.RawMessage) (tracers.Trace
 			return nil, err
 		}
 	}
-	return &prestateTracer{
+	t := &prestateTracer{
 		pre:     state{},
 		post:    state{},
 		config:  config,
 		created: make(map[common.Address]bool),
 		deleted: make(map[common.Address]bool),
-	}, nil
+	}
+	if config.LabelStorage {
+		if !config.DiffMode {
+			return nil, errors.New("labelStorage requires diffMode")
+		}
+		for addr, layout := range config.StorageLayouts {
+			if err := layout.validate(); err != nil {
+				return nil, fmt.Errorf("invalid storage layout of %s: %w", addr, err)
+			}
+		}
+		t.preimages = make(map[common.Hash][]byte)
+		t.labels = make(map[common.Address]map[common.Hash][]storageLabel)
+	}
+	return t, nil
 }
 
 // CaptureStart implements the EVMLogger interface to initialize the tracing operation.
@@ -153,6 +172,17 @@ func (t *prestateTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64,
 	stackLen := len(stackData)
 	caller := scope.Contract.Address()
 	switch {
+	case stackLen >= 2 && op == vm.KECCAK256 && t.config.LabelStorage:
+		offset, size := stackData[stackLen-1], stackData[stackLen-2]
+		if !size.IsUint64() || size.Uint64() > maxPreimageSize {
+			return
+		}
+		data, err := tracers.GetMemoryCopyPadded(scope.Memory, int64(offset.Uint64()), int64(size.Uint64()))
+		if err != nil {
+			log.Warn("failed to copy SHA3 input", "err", err, "tracer", "prestateTracer", "offset", offset, "size", size)
+			return
+		}
+		recordPreimage(t.preimages, data)
 	case stackLen >= 1 && (op == vm.SLOAD || op == vm.SSTORE):
 		slot := common.Hash(stackData[stackLen-1].Bytes32())
 		t.lookupStorage(caller, slot)
@@ -251,6 +281,57 @@ func (t *prestateTracer) CaptureTxEnd(restGas uint64) {
 			delete(t.pre, a)
 		}
 	}
+	if t.config.LabelStorage {
+		t.labelStorage()
+	}
+}
+
+// labelStorage decodes the changed slots of the contracts with a known storage
+// layout into their variables.
+func (t *prestateTracer) labelStorage() {
+	for addr, post := range t.post {
+		layout := t.config.StorageLayouts[addr]
+		if layout == nil {
+			layout = lookupStorageLayout(addr)
+		}
+		if layout == nil {
+			continue
+		}
+		var (
+			labeler = &storageLabeler{layout: layout, preimages: t.preimages}
+			slots   = make(map[common.Hash]struct{})
+			labels  = make(map[common.Hash][]storageLabel)
+		)
+		for slot := range post.Storage {
+			slots[slot] = struct{}{}
+		}
+		if pre := t.pre[addr]; pre != nil {
+			for slot := range pre.Storage {
+				slots[slot] = struct{}{}
+			}
+		}
+		for slot := range slots {
+			var (
+				preVal  common.Hash
+				postVal = t.env.StateDB.GetState(addr, slot)
+			)
+			if pre := t.pre[addr]; pre != nil {
+				preVal = pre.Storage[slot]
+			}
+			for _, field := range labeler.fields(slot) {
+				labels[slot] = append(labels[slot], storageLabel{
+					Label:  field.label,
+					Type:   field.typ.Label,
+					Offset: field.offset,
+					Pre:    field.decode(preVal),
+					Post:   field.decode(postVal),
+				})
+			}
+		}
+		if len(labels) > 0 {
+			t.labels[addr] = labels
+		}
+	}
 }
 
 func (t *prestateTracer) CaptureSystemTxEnd(intrinsicGas uint64) {}
@@ -262,9 +343,10 @@ func (t *prestateTracer) GetResult() (json.RawMessage, error) {
 	var err error
 	if t.config.DiffMode {
 		res, err = json.Marshal(struct {
-			Post state `json:"post"`
-			Pre  state `json:"pre"`
-		}{t.post, t.pre})
+			Post   state                                             `json:"post"`
+			Pre    state                                             `json:"pre"`
+			Labels map[common.Address]map[common.Hash][]storageLabel `json:"labels,omitempty"`
+		}{t.post, t.pre, t.labels})
 	} else {
 		res, err = json.Marshal(t.pre)
 	}
@@ -0,0 +1,382 @@
+package native
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// maxLabelDepth is the maximum nesting of mappings, arrays and structs
+	// resolved when labelling a slot.
+	maxLabelDepth = 16
+
+	// maxPreimageSize is the maximum size of the SHA3 inputs kept to resolve the
+	// mapping keys.
+	maxPreimageSize = 256
+
+	// maxDataSlots is the maximum distance of a slot from the hash of a mapping
+	// entry or the data of an array to be resolved as part of it.
+	maxDataSlots = 1 << 32
+)
+
+// StorageLayout is the storage layout of a contract, as given by solc with the
+// storageLayout output selection.
+type StorageLayout struct {
+	Storage []StorageVariable       `json:"storage"`
+	Types   map[string]*StorageType `json:"types"`
+}
+
+// StorageVariable is a state variable or struct member of a storage layout.
+type StorageVariable struct {
+	Label  string `json:"label"`
+	Offset uint64 `json:"offset"`
+	Slot   string `json:"slot"`
+	Type   string `json:"type"`
+}
+
+// StorageType is a type of a storage layout.
+type StorageType struct {
+	Encoding      string            `json:"encoding"`
+	Label         string            `json:"label"`
+	NumberOfBytes string            `json:"numberOfBytes"`
+	Key           string            `json:"key,omitempty"`
+	Value         string            `json:"value,omitempty"`
+	Base          string            `json:"base,omitempty"`
+	Members       []StorageVariable `json:"members,omitempty"`
+}
+
+// size returns the number of bytes of the type.
+func (t *StorageType) size() uint64 {
+	n, _ := strconv.ParseUint(t.NumberOfBytes, 10, 64)
+	return n
+}
+
+// slots returns the number of slots used by a value of the type at the offset.
+func (t *StorageType) slots(offset uint64) uint64 {
+	return (offset + t.size() + 31) / 32
+}
+
+var (
+	storageLayouts   = make(map[common.Address]*StorageLayout)
+	storageLayoutsMu sync.RWMutex
+)
+
+// RegisterStorageLayout registers the storage layout of the contract at the
+// address, used by the prestate tracer to label the storage slots.
+func RegisterStorageLayout(addr common.Address, layout *StorageLayout) {
+	storageLayoutsMu.Lock()
+	defer storageLayoutsMu.Unlock()
+
+	storageLayouts[addr] = layout
+}
+
+// LoadStorageLayouts registers the storage layouts of a directory, named after
+// the address of their contract, e.g. 0x16b9a82891338f9ba80e2d6970fdda79d1eb0dae.json.
+// The files hold either the storage layout itself or the solc output of the
+// contract including it. The number of layouts loaded is returned.
+func LoadStorageLayouts(dir string) (int, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return 0, err
+	}
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".json")
+		if !common.IsHexAddress(name) {
+			return 0, fmt.Errorf("storage layout %s not named after a contract address", file)
+		}
+		blob, err := os.ReadFile(file)
+		if err != nil {
+			return 0, err
+		}
+		// Take the layout from the solc output of the contract, if so
+		var output struct {
+			StorageLayout *StorageLayout `json:"storageLayout"`
+		}
+		if err := json.Unmarshal(blob, &output); err != nil {
+			return 0, fmt.Errorf("invalid storage layout %s: %w", file, err)
+		}
+		layout := output.StorageLayout
+		if layout == nil {
+			layout = new(StorageLayout)
+			if err := json.Unmarshal(blob, layout); err != nil {
+				return 0, fmt.Errorf("invalid storage layout %s: %w", file, err)
+			}
+		}
+		if err := layout.validate(); err != nil {
+			return 0, fmt.Errorf("invalid storage layout %s: %w", file, err)
+		}
+		RegisterStorageLayout(common.HexToAddress(name), layout)
+	}
+	return len(files), nil
+}
+
+// lookupStorageLayout returns the registered storage layout of the contract.
+func lookupStorageLayout(addr common.Address) *StorageLayout {
+	storageLayoutsMu.RLock()
+	defer storageLayoutsMu.RUnlock()
+
+	return storageLayouts[addr]
+}
+
+// validate checks that all the types used by the layout are defined, and that
+// no type contains itself in place, through its members or elements.
+func (l *StorageLayout) validate() error {
+	if len(l.Storage) == 0 {
+		return errors.New("no storage variable")
+	}
+	var (
+		visiting = make(map[string]bool) // types being checked, down the current path
+		checked  = make(map[string]bool)
+
+		check     func(vars []StorageVariable) error
+		checkType func(id, label string) error
+	)
+	check = func(vars []StorageVariable) error {
+		for _, v := range vars {
+			if _, ok := new(big.Int).SetString(v.Slot, 10); !ok {
+				return fmt.Errorf("invalid slot %q of %s", v.Slot, v.Label)
+			}
+			if err := checkType(v.Type, v.Label); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	checkType = func(id, label string) error {
+		if checked[id] {
+			return nil
+		}
+		if visiting[id] {
+			return fmt.Errorf("recursive type %s of %s", id, label)
+		}
+		typ, ok := l.Types[id]
+		if !ok {
+			return fmt.Errorf("undefined type %s of %s", id, label)
+		}
+		visiting[id] = true
+		if err := check(typ.Members); err != nil {
+			return err
+		}
+		if typ.Encoding == "inplace" && typ.Base != "" {
+			if err := checkType(typ.Base, label); err != nil {
+				return err
+			}
+		}
+		visiting[id], checked[id] = false, true
+		return nil
+	}
+	return check(l.Storage)
+}
+
+// storageField is a variable, or part of it, stored in a slot.
+type storageField struct {
+	label  string
+	typ    *StorageType
+	offset uint64
+}
+
+// decode extracts the value of the field from the slot content.
+func (f *storageField) decode(value common.Hash) interface{} {
+	size := f.typ.size()
+	if f.typ.Encoding != "inplace" || size == 0 || size > 32 || f.offset+size > 32 {
+		return value
+	}
+	data := value[32-f.offset-size : 32-f.offset]
+	switch label := f.typ.Label; {
+	case label == "bool":
+		return data[0] != 0
+	case label == "address" || strings.HasPrefix(label, "address ") || strings.HasPrefix(label, "contract "):
+		return common.BytesToAddress(data)
+	case strings.HasPrefix(label, "uint") || strings.HasPrefix(label, "enum "):
+		return (*hexutil.Big)(new(big.Int).SetBytes(data))
+	case strings.HasPrefix(label, "int"):
+		n := new(big.Int).SetBytes(data)
+		if data[0]&0x80 != 0 {
+			n.Sub(n, new(big.Int).Lsh(common.Big1, uint(size*8)))
+		}
+		return (*hexutil.Big)(n)
+	}
+	return hexutil.Bytes(data)
+}
+
+// storageLabeler decodes the slots of a contract into its variables. The slots
+// of the mapping entries and dynamic arrays are resolved from the preimages of
+// the SHA3 hashes computed during the execution.
+type storageLabeler struct {
+	layout    *StorageLayout
+	preimages map[common.Hash][]byte
+}
+
+// fields returns the variables stored in the slot.
+func (l *storageLabeler) fields(slot common.Hash) []storageField {
+	return l.locate(new(big.Int).SetBytes(slot[:]), 0)
+}
+
+func (l *storageLabeler) locate(slot *big.Int, depth int) []storageField {
+	if depth > maxLabelDepth {
+		return nil
+	}
+	// Look for the slot in the static variables first
+	var fields []storageField
+	for _, v := range l.layout.Storage {
+		base, _ := new(big.Int).SetString(v.Slot, 10)
+		fields = append(fields, l.descend(v.Label, v.Type, v.Offset, base, slot, depth)...)
+	}
+	if len(fields) > 0 {
+		return fields
+	}
+	// Look for a mapping entry or an array element, whose location is derived
+	// from the hash of its key or the slot of the array
+	for hash, preimage := range l.preimages {
+		start := new(big.Int).SetBytes(hash[:])
+		rel := new(big.Int).Sub(slot, start)
+		if rel.Sign() < 0 || rel.Cmp(big.NewInt(maxDataSlots)) >= 0 {
+			continue
+		}
+		if len(preimage) == 32 {
+			// Data of a dynamic array or of a long bytes or string
+			owner, typ := l.owner(new(big.Int).SetBytes(preimage), depth)
+			if typ == nil {
+				continue
+			}
+			switch typ.Encoding {
+			case "dynamic_array":
+				fields = append(fields, l.elements(owner, typ.Base, start, slot, depth)...)
+			case "bytes":
+				fields = append(fields, storageField{label: owner + ".data", typ: &StorageType{Encoding: "bytes", Label: typ.Label}})
+			}
+			continue
+		}
+		// Entry of a mapping, keyed by the beginning of the preimage
+		var (
+			key  = preimage[:len(preimage)-32]
+			base = new(big.Int).SetBytes(preimage[len(preimage)-32:])
+		)
+		owner, typ := l.owner(base, depth)
+		if typ == nil || typ.Encoding != "mapping" {
+			continue
+		}
+		label := fmt.Sprintf("%s[%s]", owner, l.formatKey(key, typ.Key))
+		fields = append(fields, l.descend(label, typ.Value, 0, start, slot, depth)...)
+	}
+	return fields
+}
+
+// owner returns the mapping, dynamic array or bytes variable stored at the slot.
+func (l *storageLabeler) owner(slot *big.Int, depth int) (string, *StorageType) {
+	fields := l.locate(slot, depth+1)
+	if len(fields) != 1 {
+		return "", nil
+	}
+	switch fields[0].typ.Encoding {
+	case "mapping", "dynamic_array", "bytes":
+		return fields[0].label, fields[0].typ
+	}
+	return "", nil
+}
+
+// descend returns the parts of the variable of the given type at the base slot
+// which are stored in the slot.
+func (l *storageLabeler) descend(label, typeID string, offset uint64, base, slot *big.Int, depth int) []storageField {
+	typ := l.layout.Types[typeID]
+	if typ == nil || depth > maxLabelDepth {
+		return nil
+	}
+	rel := new(big.Int).Sub(slot, base)
+	if rel.Sign() < 0 || rel.Cmp(new(big.Int).SetUint64(typ.slots(offset))) >= 0 {
+		return nil
+	}
+	switch {
+	case typ.Encoding != "inplace":
+		return []storageField{{label: label, typ: typ}}
+
+	case len(typ.Members) > 0:
+		var fields []storageField
+		for _, m := range typ.Members {
+			mslot, _ := new(big.Int).SetString(m.Slot, 10)
+			fields = append(fields, l.descend(label+"."+m.Label, m.Type, m.Offset, mslot.Add(mslot, base), slot, depth+1)...)
+		}
+		return fields
+
+	case typ.Base != "":
+		return l.elements(label, typ.Base, base, slot, depth+1)
+	}
+	return []storageField{{label: label, typ: typ, offset: offset}}
+}
+
+// elements returns the elements of the array starting at the base slot which
+// are stored in the slot. Elements smaller than a slot are packed.
+func (l *storageLabeler) elements(label, typeID string, base, slot *big.Int, depth int) []storageField {
+	typ := l.layout.Types[typeID]
+	if typ == nil || typ.size() == 0 {
+		return nil
+	}
+	rel := new(big.Int).Sub(slot, base).Uint64()
+	if size := typ.size(); size > 16 {
+		per := typ.slots(0)
+		index, start := rel/per, new(big.Int).Add(base, new(big.Int).SetUint64(rel/per*per))
+		return l.descend(fmt.Sprintf("%s[%d]", label, index), typeID, 0, start, slot, depth)
+	}
+	var (
+		per    = 32 / typ.size()
+		fields = make([]storageField, 0, per)
+	)
+	for i := uint64(0); i < per; i++ {
+		fields = append(fields, storageField{
+			label:  fmt.Sprintf("%s[%d]", label, rel*per+i),
+			typ:    typ,
+			offset: i * typ.size(),
+		})
+	}
+	return fields
+}
+
+// formatKey formats a mapping key according to its type.
+func (l *storageLabeler) formatKey(key []byte, typeID string) string {
+	var label string
+	if typ := l.layout.Types[typeID]; typ != nil {
+		label = typ.Label
+	}
+	switch {
+	case label == "string":
+		return strconv.Quote(string(key))
+	case len(key) != 32:
+		return hexutil.Encode(key)
+	case label == "address" || strings.HasPrefix(label, "contract "):
+		return common.BytesToAddress(key).Hex()
+	case strings.HasPrefix(label, "uint") || strings.HasPrefix(label, "enum "):
+		return new(big.Int).SetBytes(key).String()
+	case label == "bool":
+		return strconv.FormatBool(key[31] != 0)
+	}
+	return hexutil.Encode(key)
+}
+
+// storageLabel is a labelled variable of a changed slot.
+type storageLabel struct {
+	Label  string      `json:"label"`
+	Type   string      `json:"type"`
+	Offset uint64      `json:"offset,omitempty"`
+	Pre    interface{} `json:"pre"`
+	Post   interface{} `json:"post"`
+}
+
+// recordPreimage keeps the preimage of a SHA3 hash resolving the mapping keys
+// and the array locations.
+func recordPreimage(preimages map[common.Hash][]byte, data []byte) {
+	if len(data) < 32 || len(data) > maxPreimageSize {
+		return
+	}
+	preimages[crypto.Keccak256Hash(data)] = data
+}
@@ -0,0 +1,174 @@
+package native
+
+import (
+	"encoding/json"
+	"math/big"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+func init() {
+	tracers.DefaultDirectory.Register("transferTracer", newTransferTracer, false)
+}
+
+// transferTopic is the topic of the ERC-20/BEP-20 Transfer event.
+var transferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+// transfer is a token or native value transfer. The token of native transfers
+// is the zero address.
+type transfer struct {
+	Token     common.Address `json:"token"`
+	From      common.Address `json:"from"`
+	To        common.Address `json:"to"`
+	Amount    *hexutil.Big   `json:"amount"`
+	CallDepth int            `json:"callDepth"`
+	Reverted  bool           `json:"reverted,omitempty"`
+}
+
+// transferTracer collects the value flow of a transaction: the ERC-20/BEP-20
+// Transfer events and the native value transfers of all the calls, including
+// the internal ones. The transfers of reverted calls are kept, marked as such.
+//
+// Example:
+//
+//	> debug.traceTransaction("0x...", {tracer: "transferTracer"})
+//	[
+//	  {token: "0x0000000000000000000000000000000000000000", from: "0x...", to: "0x...", amount: "0xde0b6b3a7640000", callDepth: 0},
+//	  {token: "0x55d398326f99059ff775485246999027b3197955", from: "0x...", to: "0x...", amount: "0x1", callDepth: 1, reverted: true}
+//	]
+type transferTracer struct {
+	noopTracer
+	transfers []transfer
+	callstack [][]int     // indexes of the transfers of every open call frame
+	interrupt atomic.Bool // Atomic flag to signal execution interruption
+	reason    error       // Textual reason for the interruption
+}
+
+// newTransferTracer returns a native go tracer which collects the transfers of
+// a tx, and implements vm.EVMLogger.
+func newTransferTracer(ctx *tracers.Context, _ json.RawMessage) (tracers.Tracer, error) {
+	return &transferTracer{transfers: make([]transfer, 0)}, nil
+}
+
+// record adds a transfer made by the innermost call frame.
+func (t *transferTracer) record(token, from, to common.Address, amount *big.Int) {
+	depth := len(t.callstack) - 1
+	t.callstack[depth] = append(t.callstack[depth], len(t.transfers))
+	t.transfers = append(t.transfers, transfer{
+		Token:     token,
+		From:      from,
+		To:        to,
+		Amount:    (*hexutil.Big)(new(big.Int).Set(amount)),
+		CallDepth: depth,
+	})
+}
+
+// revert marks the transfers of the given indexes as reverted.
+func (t *transferTracer) revert(indexes []int) {
+	for _, i := range indexes {
+		t.transfers[i].Reverted = true
+	}
+}
+
+// CaptureStart implements the EVMLogger interface to initialize the tracing operation.
+func (t *transferTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.callstack = [][]int{nil}
+	if value != nil && value.Sign() > 0 {
+		t.record(common.Address{}, from, to, value)
+	}
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *transferTracer) CaptureEnd(output []byte, gasUsed uint64, err error) {
+	if err != nil && len(t.callstack) > 0 {
+		t.revert(t.callstack[0])
+	}
+}
+
+// CaptureState implements the EVMLogger interface to trace a single step of VM execution.
+func (t *transferTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	// skip if the previous op caused an error
+	if err != nil || op != vm.LOG3 {
+		return
+	}
+	// Skip if tracing was interrupted
+	if t.interrupt.Load() {
+		return
+	}
+	// Transfer events have the sender and the recipient as indexed topics, and
+	// the amount as data, tell them apart from the ERC-721 ones by the topics
+	stackData := scope.Stack.Data()
+	if common.Hash(stackData[len(stackData)-3].Bytes32()) != transferTopic {
+		return
+	}
+	mStart, mSize := stackData[len(stackData)-1], stackData[len(stackData)-2]
+	if !mSize.IsUint64() || mSize.Uint64() != 32 {
+		return
+	}
+	data, err := tracers.GetMemoryCopyPadded(scope.Memory, int64(mStart.Uint64()), 32)
+	if err != nil {
+		log.Warn("failed to copy Transfer data", "err", err, "tracer", "transferTracer", "offset", mStart)
+		return
+	}
+	var (
+		from = common.Address(stackData[len(stackData)-4].Bytes20())
+		to   = common.Address(stackData[len(stackData)-5].Bytes20())
+	)
+	t.record(scope.Contract.Address(), from, to, new(big.Int).SetBytes(data))
+}
+
+// CaptureEnter is called when EVM enters a new scope (via call, create or selfdestruct).
+func (t *transferTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	t.callstack = append(t.callstack, nil)
+
+	// Skip if tracing was interrupted
+	if t.interrupt.Load() {
+		return
+	}
+	// Delegate and code calls keep the value in the calling contract
+	if typ == vm.DELEGATECALL || typ == vm.CALLCODE || typ == vm.STATICCALL {
+		return
+	}
+	if value != nil && value.Sign() > 0 {
+		t.record(common.Address{}, from, to, value)
+	}
+}
+
+// CaptureExit is called when EVM exits a scope, even if the scope didn't
+// execute any code.
+func (t *transferTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	size := len(t.callstack)
+	if size <= 1 {
+		return
+	}
+	// pop call
+	indexes := t.callstack[size-1]
+	t.callstack = t.callstack[:size-1]
+
+	if err != nil {
+		t.revert(indexes)
+	}
+	t.callstack[size-2] = append(t.callstack[size-2], indexes...)
+}
+
+// GetResult returns the json-encoded list of transfers, and any error arising
+// from the encoding or forceful termination (via `Stop`).
+func (t *transferTracer) GetResult() (json.RawMessage, error) {
+	res, err := json.Marshal(t.transfers)
+	if err != nil {
+		return nil, err
+	}
+	return res, t.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *transferTracer) Stop(err error) {
+	t.reason = err
+	t.interrupt.Store(true)
+}
@@ -0,0 +1,320 @@
+package tracers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// flatTracer is the tracer reporting the calls in the format of the trace APIs.
+const flatTracer = "flatCallTracer"
+
+// TraceAPI is the collection of the Parity-style tracing APIs, reporting the
+// calls of the transactions as flat lists of traces. The block traces are
+// served from the trace cache, except for trace_filter which always traces its
+// range. Only the "trace" type of trace_replayBlockTransactions is supported.
+type TraceAPI struct {
+	api         *API
+	filterRange uint64 // maximum number of blocks traced by Filter, unlimited if zero
+}
+
+// TraceFilterArgs are the arguments of trace_filter. The traces match if their
+// sender is one of the from addresses and their recipient one of the to
+// addresses, either list matching everything when empty. The range defaults
+// to the latest block.
+type TraceFilterArgs struct {
+	FromBlock   *rpc.BlockNumber `json:"fromBlock"`
+	ToBlock     *rpc.BlockNumber `json:"toBlock"`
+	FromAddress []common.Address `json:"fromAddress"`
+	ToAddress   []common.Address `json:"toAddress"`
+	After       *uint64          `json:"after"` // Number of matching traces to skip
+	Count       *uint64          `json:"count"` // Maximum number of traces to return
+}
+
+// replayResult is the replay of a transaction by trace_replayBlockTransactions.
+type replayResult struct {
+	Output          hexutil.Bytes     `json:"output"`
+	StateDiff       interface{}       `json:"stateDiff"`
+	Trace           []json.RawMessage `json:"trace"`
+	VmTrace         interface{}       `json:"vmTrace"`
+	TransactionHash common.Hash       `json:"transactionHash"`
+}
+
+// flatTraceView is the part of a flat trace needed to filter and replay it.
+type flatTraceView struct {
+	Action struct {
+		From          *common.Address `json:"from"`
+		To            *common.Address `json:"to"`
+		Address       *common.Address `json:"address"`
+		RefundAddress *common.Address `json:"refundAddress"`
+	} `json:"action"`
+	Result *struct {
+		Address *common.Address `json:"address"`
+		Output  hexutil.Bytes   `json:"output"`
+	} `json:"result"`
+}
+
+// sender returns the account initiating the traced action.
+func (v *flatTraceView) sender() *common.Address {
+	if v.Action.From != nil {
+		return v.Action.From
+	}
+	return v.Action.Address // self-destructed contract
+}
+
+// recipient returns the account receiving the traced action.
+func (v *flatTraceView) recipient() *common.Address {
+	switch {
+	case v.Action.To != nil:
+		return v.Action.To
+	case v.Action.RefundAddress != nil:
+		return v.Action.RefundAddress // beneficiary of a self-destruct
+	case v.Result != nil:
+		return v.Result.Address // created contract
+	}
+	return nil
+}
+
+// flatConfig returns the configuration tracing the transactions into flat traces.
+func flatConfig() *TraceConfig {
+	tracer := flatTracer
+	return &TraceConfig{Tracer: &tracer}
+}
+
+// flatTraces splits the result of a transaction trace into its flat traces.
+func flatTraces(res *txTraceResult) ([]json.RawMessage, error) {
+	if res.Error != "" {
+		return nil, fmt.Errorf("failed to trace transaction %#x: %s", res.TxHash, res.Error)
+	}
+	blob, ok := res.Result.(json.RawMessage)
+	if !ok {
+		return nil, fmt.Errorf("unexpected trace result of transaction %#x", res.TxHash)
+	}
+	var traces []json.RawMessage
+	if err := json.Unmarshal(blob, &traces); err != nil {
+		return nil, err
+	}
+	return traces, nil
+}
+
+// traceBlock traces all the transactions of the block concurrently, unless
+// cached.
+func (api *TraceAPI) traceBlock(ctx context.Context, block *types.Block) ([]*txTraceResult, error) {
+	config := flatConfig()
+	return api.api.cache.traceBlock(block, config, func() ([]*txTraceResult, error) {
+		_, statedb, release, err := api.api.blockState(ctx, block, config)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+
+		return api.api.traceBlockParallel(ctx, block, statedb, config)
+	})
+}
+
+// Block returns the traces of all the transactions of the block.
+func (api *TraceAPI) Block(ctx context.Context, number rpc.BlockNumber) ([]json.RawMessage, error) {
+	block, err := api.api.blockByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	results, err := api.traceBlock(ctx, block)
+	if err != nil {
+		return nil, err
+	}
+	traces := make([]json.RawMessage, 0)
+	for _, res := range results {
+		txTraces, err := flatTraces(res)
+		if err != nil {
+			return nil, err
+		}
+		traces = append(traces, txTraces...)
+	}
+	return traces, nil
+}
+
+// Transaction returns the traces of the transaction.
+func (api *TraceAPI) Transaction(ctx context.Context, hash common.Hash) ([]json.RawMessage, error) {
+	res, err := api.api.TraceTransaction(ctx, hash, flatConfig())
+	if err != nil {
+		return nil, err
+	}
+	return flatTraces(&txTraceResult{TxHash: hash, Result: res})
+}
+
+// ReplayBlockTransactions replays all the transactions of the block, returning
+// their output along with the requested trace types. Only the "trace" type is
+// supported.
+func (api *TraceAPI) ReplayBlockTransactions(ctx context.Context, number rpc.BlockNumber, traceTypes []string) ([]*replayResult, error) {
+	var withTraces bool
+	for _, typ := range traceTypes {
+		switch typ {
+		case "trace":
+			withTraces = true
+		default:
+			return nil, fmt.Errorf("unsupported trace type %q", typ)
+		}
+	}
+	block, err := api.api.blockByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	results, err := api.traceBlock(ctx, block)
+	if err != nil {
+		return nil, err
+	}
+	replays := make([]*replayResult, len(results))
+	for i, res := range results {
+		traces, err := flatTraces(res)
+		if err != nil {
+			return nil, err
+		}
+		replay := &replayResult{
+			Output:          hexutil.Bytes{},
+			Trace:           make([]json.RawMessage, 0),
+			TransactionHash: res.TxHash,
+		}
+		// The output of the transaction is the one of its top call
+		if len(traces) > 0 {
+			var top flatTraceView
+			if err := json.Unmarshal(traces[0], &top); err != nil {
+				return nil, err
+			}
+			if top.Result != nil && top.Result.Output != nil {
+				replay.Output = top.Result.Output
+			}
+		}
+		if withTraces {
+			replay.Trace = traces
+		}
+		replays[i] = replay
+	}
+	return replays, nil
+}
+
+// Filter returns the traces of the block range matching the addresses of the
+// filter. The blocks are traced concurrently, streaming their traces until
+// enough are collected to fill the requested page. The range is traced by the
+// chain tracer, bypassing the trace cache.
+func (api *TraceAPI) Filter(ctx context.Context, args TraceFilterArgs) ([]json.RawMessage, error) {
+	from, to := rpc.LatestBlockNumber, rpc.LatestBlockNumber
+	if args.FromBlock != nil {
+		from = *args.FromBlock
+	}
+	if args.ToBlock != nil {
+		to = *args.ToBlock
+	}
+	start, err := api.api.blockByNumber(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	end, err := api.api.blockByNumber(ctx, to)
+	if err != nil {
+		return nil, err
+	}
+	if start.NumberU64() > end.NumberU64() {
+		return nil, fmt.Errorf("end block (#%d) needs to come after start block (#%d)", end.NumberU64(), start.NumberU64())
+	}
+	if blocks := end.NumberU64() - start.NumberU64() + 1; api.filterRange > 0 && blocks > api.filterRange {
+		return nil, fmt.Errorf("block range too large: %d blocks, max %d", blocks, api.filterRange)
+	}
+	traces := make([]json.RawMessage, 0)
+	if end.NumberU64() == 0 || (args.Count != nil && *args.Count == 0) {
+		return traces, nil
+	}
+	// The chain tracer excludes its first block, start from the parent of the
+	// range. The genesis is not traceable, so skip it instead.
+	parent := start
+	if start.NumberU64() > 0 {
+		if parent, err = api.api.blockByNumberAndHash(ctx, rpc.BlockNumber(start.NumberU64()-1), start.ParentHash()); err != nil {
+			return nil, err
+		}
+	}
+	var (
+		senders    = make(map[common.Address]struct{})
+		recipients = make(map[common.Address]struct{})
+		skip       uint64
+	)
+	for _, addr := range args.FromAddress {
+		senders[addr] = struct{}{}
+	}
+	for _, addr := range args.ToAddress {
+		recipients[addr] = struct{}{}
+	}
+	if args.After != nil {
+		skip = *args.After
+	}
+	matches := func(addr *common.Address, set map[common.Address]struct{}) bool {
+		if len(set) == 0 {
+			return true
+		}
+		if addr == nil {
+			return false
+		}
+		_, ok := set[*addr]
+		return ok
+	}
+	// Stream the traces of the range, stopping the tracing once the page is full
+	var (
+		closed = make(chan interface{})
+		resCh  = api.api.traceChain(parent, end, flatConfig(), closed)
+		last   uint64
+	)
+	defer func() {
+		close(closed)
+		for range resCh {
+		}
+	}()
+	for {
+		var (
+			res *blockTraceResult
+			ok  bool
+		)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case res, ok = <-resCh:
+		}
+		if !ok {
+			break
+		}
+		last = uint64(res.Block)
+		for _, txRes := range res.Traces {
+			if txRes == nil {
+				continue
+			}
+			txTraces, err := flatTraces(txRes)
+			if err != nil {
+				return nil, err
+			}
+			for _, trace := range txTraces {
+				var view flatTraceView
+				if err := json.Unmarshal(trace, &view); err != nil {
+					return nil, err
+				}
+				if !matches(view.sender(), senders) || !matches(view.recipient(), recipients) {
+					continue
+				}
+				if skip > 0 {
+					skip--
+					continue
+				}
+				traces = append(traces, trace)
+				if args.Count != nil && uint64(len(traces)) == *args.Count {
+					return traces, nil
+				}
+			}
+		}
+	}
+	// The last block is always delivered, unless the tracing failed midway
+	if last != end.NumberU64() {
+		return nil, errors.New("chain tracing aborted")
+	}
+	return traces, nil
+}
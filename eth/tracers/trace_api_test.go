@@ -0,0 +1,211 @@
+package tracers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func init() {
+	// The native tracers can't be imported from the tracers package, stand in
+	// for the flat call tracer with a tracer reporting the top call only
+	DefaultDirectory.Register(flatTracer, newTopCallTracer, false)
+}
+
+// topCallTracer reports the top call of a transaction as a flat trace.
+type topCallTracer struct {
+	ctx   *Context
+	trace map[string]interface{}
+}
+
+func newTopCallTracer(ctx *Context, _ json.RawMessage) (Tracer, error) {
+	return &topCallTracer{ctx: ctx}, nil
+}
+
+func (t *topCallTracer) CaptureTxStart(gasLimit uint64)                {}
+func (t *topCallTracer) CaptureTxEnd(restGas uint64)                   {}
+func (t *topCallTracer) CaptureSystemTxEnd(intrinsicGas uint64)        {}
+func (t *topCallTracer) CaptureEnd(output []byte, _ uint64, err error) {}
+func (t *topCallTracer) CaptureEnter(vm.OpCode, common.Address, common.Address, []byte, uint64, *big.Int) {
+}
+func (t *topCallTracer) CaptureExit([]byte, uint64, error) {}
+func (t *topCallTracer) CaptureState(uint64, vm.OpCode, uint64, uint64, *vm.ScopeContext, []byte, int, error) {
+}
+func (t *topCallTracer) CaptureFault(uint64, vm.OpCode, uint64, uint64, *vm.ScopeContext, int, error) {
+}
+func (t *topCallTracer) Stop(err error) {}
+
+func (t *topCallTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.trace = map[string]interface{}{
+		"action":              map[string]interface{}{"from": from, "to": to},
+		"blockNumber":         t.ctx.BlockNumber.Uint64(),
+		"result":              map[string]interface{}{"output": "0x01"},
+		"transactionHash":     t.ctx.TxHash,
+		"transactionPosition": t.ctx.TxIndex,
+	}
+}
+
+func (t *topCallTracer) GetResult() (json.RawMessage, error) {
+	return json.Marshal([]interface{}{t.trace})
+}
+
+// traceTestView is the part of the traces checked by the tests.
+type traceTestView struct {
+	Action struct {
+		From common.Address `json:"from"`
+		To   common.Address `json:"to"`
+	} `json:"action"`
+	BlockNumber         uint64      `json:"blockNumber"`
+	TransactionHash     common.Hash `json:"transactionHash"`
+	TransactionPosition int         `json:"transactionPosition"`
+}
+
+func decodeTraces(t *testing.T, traces []json.RawMessage) []traceTestView {
+	views := make([]traceTestView, len(traces))
+	for i, trace := range traces {
+		if err := json.Unmarshal(trace, &views[i]); err != nil {
+			t.Fatalf("failed to decode trace %d: %v", i, err)
+		}
+	}
+	return views
+}
+
+func TestTraceAPI(t *testing.T) {
+	t.Parallel()
+
+	// Every block transfers from account[0] to account[1], then from account[1]
+	// to account[2]
+	accounts := newAccounts(3)
+	genesis := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc: types.GenesisAlloc{
+			accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+			accounts[1].addr: {Balance: big.NewInt(params.Ether)},
+			accounts[2].addr: {Balance: big.NewInt(params.Ether)},
+		},
+	}
+	var (
+		genBlocks = 10
+		signer    = types.HomesteadSigner{}
+		hashes    []common.Hash
+	)
+	backend := newTestBackend(t, genBlocks, genesis, func(i int, b *core.BlockGen) {
+		for j := 0; j < 2; j++ {
+			tx, _ := types.SignTx(types.NewTransaction(uint64(i), accounts[j+1].addr, big.NewInt(1000), params.TxGas, b.BaseFee(), nil), signer, accounts[j].key)
+			b.AddTx(tx)
+			hashes = append(hashes, tx.Hash())
+		}
+	})
+	defer backend.chain.Stop()
+	api := &TraceAPI{api: NewAPI(backend)}
+	ctx := context.Background()
+
+	// Trace a block and one of its transactions
+	traces, err := api.Block(ctx, rpc.BlockNumber(5))
+	if err != nil {
+		t.Fatalf("failed to trace block: %v", err)
+	}
+	views := decodeTraces(t, traces)
+	if len(views) != 2 || views[0].TransactionHash != hashes[8] || views[1].TransactionHash != hashes[9] {
+		t.Fatalf("unexpected block traces: %+v", views)
+	}
+	traces, err = api.Transaction(ctx, hashes[9])
+	if err != nil {
+		t.Fatalf("failed to trace transaction: %v", err)
+	}
+	if views = decodeTraces(t, traces); len(views) != 1 || views[0].Action.From != accounts[1].addr || views[0].TransactionPosition != 1 {
+		t.Fatalf("unexpected transaction traces: %+v", views)
+	}
+	if _, err := api.Block(ctx, rpc.BlockNumber(0)); err == nil {
+		t.Fatal("expected genesis tracing to fail")
+	}
+
+	// Replay the transactions of a block
+	replays, err := api.ReplayBlockTransactions(ctx, rpc.BlockNumber(3), []string{"trace"})
+	if err != nil {
+		t.Fatalf("failed to replay block: %v", err)
+	}
+	if len(replays) != 2 || replays[1].TransactionHash != hashes[5] || len(replays[1].Trace) != 1 || replays[1].Output.String() != "0x01" {
+		t.Fatalf("unexpected replay: %+v", replays)
+	}
+	replays, err = api.ReplayBlockTransactions(ctx, rpc.BlockNumber(3), nil)
+	if err != nil {
+		t.Fatalf("failed to replay block: %v", err)
+	}
+	if len(replays) != 2 || len(replays[0].Trace) != 0 || replays[0].Output.String() != "0x01" {
+		t.Fatalf("unexpected replay without traces: %+v", replays)
+	}
+	if _, err := api.ReplayBlockTransactions(ctx, rpc.BlockNumber(3), []string{"vmTrace"}); err == nil {
+		t.Fatal("expected unsupported trace type to fail")
+	}
+
+	// Filter the traces of block ranges
+	number := func(n rpc.BlockNumber) *rpc.BlockNumber { return &n }
+	count := func(n uint64) *uint64 { return &n }
+	var cases = []struct {
+		args  TraceFilterArgs
+		want  []int // indexes of the expected transactions
+		error bool
+	}{
+		// the entire chain, the genesis being skipped
+		{args: TraceFilterArgs{FromBlock: number(0), ToBlock: number(10)}, want: []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19}},
+		// the latest block by default
+		{args: TraceFilterArgs{}, want: []int{18, 19}},
+		// a single block
+		{args: TraceFilterArgs{FromBlock: number(4), ToBlock: number(4)}, want: []int{6, 7}},
+		// by sender
+		{args: TraceFilterArgs{FromBlock: number(3), ToBlock: number(5), FromAddress: []common.Address{accounts[1].addr}}, want: []int{5, 7, 9}},
+		// by recipient
+		{args: TraceFilterArgs{FromBlock: number(3), ToBlock: number(5), ToAddress: []common.Address{accounts[1].addr}}, want: []int{4, 6, 8}},
+		// by sender and recipient
+		{args: TraceFilterArgs{FromBlock: number(3), ToBlock: number(5), FromAddress: []common.Address{accounts[0].addr}, ToAddress: []common.Address{accounts[2].addr}}, want: []int{}},
+		{args: TraceFilterArgs{FromBlock: number(3), ToBlock: number(5), FromAddress: []common.Address{accounts[0].addr, accounts[1].addr}, ToAddress: []common.Address{accounts[2].addr}}, want: []int{5, 7, 9}},
+		// paginated
+		{args: TraceFilterArgs{FromBlock: number(1), ToBlock: number(10), After: count(3), Count: count(4)}, want: []int{3, 4, 5, 6}},
+		{args: TraceFilterArgs{FromBlock: number(1), ToBlock: number(10), After: count(18), Count: count(4)}, want: []int{18, 19}},
+		{args: TraceFilterArgs{FromBlock: number(1), ToBlock: number(10), Count: count(0)}, want: []int{}},
+		// invalid ranges
+		{args: TraceFilterArgs{FromBlock: number(5), ToBlock: number(4)}, error: true},
+		{args: TraceFilterArgs{FromBlock: number(5), ToBlock: number(11)}, error: true},
+	}
+	for i, c := range cases {
+		traces, err := api.Filter(ctx, c.args)
+		if c.error {
+			if err == nil {
+				t.Errorf("case %d: expected error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("case %d: failed to filter traces: %v", i, err)
+			continue
+		}
+		var have, want []string
+		for _, view := range decodeTraces(t, traces) {
+			have = append(have, view.TransactionHash.Hex())
+		}
+		for _, index := range c.want {
+			want = append(want, hashes[index].Hex())
+		}
+		if fmt.Sprint(have) != fmt.Sprint(want) {
+			t.Errorf("case %d: unexpected traces\nhave %v\nwant %v", i, have, want)
+		}
+	}
+	// Ranges longer than the configured limit are refused
+	api.filterRange = 3
+	if _, err := api.Filter(ctx, TraceFilterArgs{FromBlock: number(3), ToBlock: number(5)}); err != nil {
+		t.Errorf("failed to filter traces within the range limit: %v", err)
+	}
+	if _, err := api.Filter(ctx, TraceFilterArgs{FromBlock: number(3), ToBlock: number(6)}); err == nil {
+		t.Error("expected range over the limit to fail")
+	}
+}
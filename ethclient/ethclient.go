@@ -789,6 +789,13 @@ func (ec *Client) MevParams(ctx context.Context) (*types.MevParams, error) {
 	return &params, err
 }
 
+// BuilderStats returns the health and latency statistics of the registered builders
+func (ec *Client) BuilderStats(ctx context.Context) ([]*types.BuilderStats, error) {
+	var stats []*types.BuilderStats
+	err := ec.c.CallContext(ctx, &stats, "mev_builderStats")
+	return stats, err
+}
+
 func toBlockNumArg(number *big.Int) string {
 	if number == nil {
 		return "latest"
@@ -241,13 +241,35 @@ func (s *TxPoolAPI) ContentFrom(addr common.Address) map[string]map[string]*RPCT
 	return content
 }
 
-// Status returns the number of pending and queued transaction in the pool.
-func (s *TxPoolAPI) Status() map[string]hexutil.Uint {
+// txPoolLaneStatus is the usage of a transaction pool priority lane.
+type txPoolLaneStatus struct {
+	Slots    hexutil.Uint64 `json:"slots"`
+	Reserved hexutil.Uint64 `json:"reserved"`
+	Pending  hexutil.Uint   `json:"pending"`
+	Queued   hexutil.Uint   `json:"queued"`
+}
+
+// Status returns the number of pending and queued transaction in the pool, along
+// with the usage of the priority lanes if any.
+func (s *TxPoolAPI) Status() map[string]interface{} {
 	pending, queue := s.b.Stats()
-	return map[string]hexutil.Uint{
+	status := map[string]interface{}{
 		"pending": hexutil.Uint(pending),
 		"queued":  hexutil.Uint(queue),
 	}
+	if lanes := s.b.TxPoolLanes(); len(lanes) > 0 {
+		stats := make(map[string]txPoolLaneStatus, len(lanes))
+		for _, lane := range lanes {
+			stats[lane.Name] = txPoolLaneStatus{
+				Slots:    lane.Slots,
+				Reserved: lane.Reserved,
+				Pending:  lane.Pending,
+				Queued:   lane.Queued,
+			}
+		}
+		status["lanes"] = stats
+	}
+	return status
 }
 
 // Inspect retrieves the content of the transaction pool and flattens it into an
@@ -1172,6 +1194,38 @@ func (diff *BlockOverrides) Apply(blockCtx *vm.BlockContext) {
 	}
 }
 
+// MakeHeader returns a new header object with the overridden fields.
+// Note: MakeHeader ignores BlobBaseFee if set. That's because the header
+// has no such field.
+func (diff *BlockOverrides) MakeHeader(header *types.Header) *types.Header {
+	if diff == nil {
+		return header
+	}
+	h := types.CopyHeader(header)
+	if diff.Number != nil {
+		h.Number = diff.Number.ToInt()
+	}
+	if diff.Difficulty != nil {
+		h.Difficulty = diff.Difficulty.ToInt()
+	}
+	if diff.Time != nil {
+		h.Time = uint64(*diff.Time)
+	}
+	if diff.GasLimit != nil {
+		h.GasLimit = uint64(*diff.GasLimit)
+	}
+	if diff.Coinbase != nil {
+		h.Coinbase = *diff.Coinbase
+	}
+	if diff.Random != nil {
+		h.MixDigest = *diff.Random
+	}
+	if diff.BaseFee != nil {
+		h.BaseFee = diff.BaseFee.ToInt()
+	}
+	return h
+}
+
 // ChainContextBackend provides methods required to implement ChainContext.
 type ChainContextBackend interface {
 	Engine() consensus.Engine
@@ -2016,37 +2070,9 @@ func (s *BlockChainAPI) CallBatch() (string, error) {
 		})
 		log.Info("降序排序rois成功", "rois", rois)
 
-		// 将排序后的rois去重过滤，保证每个pair只能出现一次，重复时将Profit较小的ROI都删除，只保留Profit最大的ROI
-		// 去重，保证 Pair0, Pair1, Pair2 中的值只出现一次
-		uniquePairs := make(map[string]bool)
-		var filteredROIs []ROI
-		for _, roi := range rois {
-			if uniquePairs[roi.Triangle.Pair0] || uniquePairs[roi.Triangle.Pair1] || uniquePairs[roi.Triangle.Pair2] {
-				// 如果任何一个 pair 已经出现过，跳过该结构体（删除）
-				continue
-			}
-
-			// 如果不存在，则将该结构体加入结果集，并标记 pairs 为已出现
-			filteredROIs = append(filteredROIs, roi)
-			uniquePairs[roi.Triangle.Pair0] = true
-			uniquePairs[roi.Triangle.Pair1] = true
-			uniquePairs[roi.Triangle.Pair2] = true
-		}
-		log.Info("排序去重获rois成功", "filteredROIs", filteredROIs)
-
-		// 计算预估总gas
-		var gasTotal hexutil.Uint64
-		for _, filteredROI := range filteredROIs {
-			decodeString, _ := hex.DecodeString(filteredROI.CallData)
-			bytes := hexutil.Bytes(decodeString)
-			args := TransactionArgs{From: &pair.From, To: &pair.To, Data: &bytes}
-			gas, err := s.EstimateGas(context.Background(), args, &pair.LatestBlockNumber, nil)
-			if err != nil {
-				log.Error("存在roi的预估gas计算异常", "err", err)
-			}
-			gasTotal = gasTotal + gas
-		}
-		log.Info("计算预估总gas成功", "gasTotal", gasTotal)
+		// 在pair冲突图上选出互不冲突且满足区块gas预算的利润最大组合
+		filteredROIs := s.selectROIs(rois)
+		log.Info("冲突图选择rois成功", "filteredROIs", filteredROIs)
 	}
 
 	totalSince := time.Since(start)
@@ -2069,6 +2095,109 @@ func (s *BlockChainAPI) CallBatch() (string, error) {
 	return "ok", nil
 }
 
+// maxConcurrentROIEstimates 同时执行的roi gas预估数量上限
+const maxConcurrentROIEstimates = 8
+
+// selectROIs 在pair冲突图上求解满足区块gas预算的最大权独立集，返回选中的rois（按利润降序），
+// 并记录因冲突或预算而放弃的利润。gas预估是按需进行的：先忽略gas在冲突图上选择，
+// 只预估选中的rois，若其gas总和在预算内则该选择即为最优解，否则再预估其余rois后按预算重新选择
+func (s *BlockChainAPI) selectROIs(rois []ROI) []ROI {
+	budget := pair.GasBudget
+	if budget == 0 {
+		budget = s.b.CurrentHeader().GasLimit
+	}
+	// 利润非正的roi不可能被选中，无需预估gas
+	profitable := make([]ROI, 0, len(rois))
+	for _, roi := range rois {
+		if roi.Profit.Sign() > 0 {
+			profitable = append(profitable, roi)
+		}
+	}
+	var (
+		gases  = make([]uint64, len(profitable))
+		failed = make([]bool, len(profitable))
+		done   = make([]bool, len(profitable))
+	)
+	candidates := make([]pairtypes.Candidate, len(profitable))
+	for i, roi := range profitable {
+		candidates[i] = pairtypes.TriangleCandidate(roi.Triangle, new(big.Int).Set(&roi.Profit), 0)
+	}
+	selection := pairtypes.SelectOpportunities(candidates, 0)
+	s.estimateROIs(profitable, selection.Selected, gases, failed, done)
+
+	fit, gasTotal := true, uint64(0)
+	for _, i := range selection.Selected {
+		if failed[i] || gasTotal+gases[i] < gasTotal || gasTotal+gases[i] > budget {
+			fit = false
+			break
+		}
+		gasTotal += gases[i]
+	}
+	if !fit {
+		// 选中的rois超出预算或预估失败，预估其余rois后按预算重新选择
+		var rest []int
+		for i := range profitable {
+			if !done[i] {
+				rest = append(rest, i)
+			}
+		}
+		s.estimateROIs(profitable, rest, gases, failed, done)
+
+		var (
+			estimated []int
+			usable    []pairtypes.Candidate
+		)
+		for i, roi := range profitable {
+			if failed[i] {
+				continue
+			}
+			estimated = append(estimated, i)
+			usable = append(usable, pairtypes.TriangleCandidate(roi.Triangle, new(big.Int).Set(&roi.Profit), gases[i]))
+		}
+		selection = pairtypes.SelectOpportunities(usable, budget)
+		for j, i := range selection.Selected {
+			selection.Selected[j] = estimated[i]
+		}
+	}
+	selected := make([]ROI, 0, len(selection.Selected))
+	for _, i := range selection.Selected {
+		selected = append(selected, profitable[i])
+	}
+	log.Info("冲突图选择rois完成", "candidates", len(profitable), "selected", len(selected), "exact", selection.Exact,
+		"profit", selection.Profit, "forgone", selection.Forgone, "gasBudget", budget, "withinBudget", fit)
+	return selected
+}
+
+// estimateROIs 并发预估指定下标rois的gas，并发数不超过 maxConcurrentROIEstimates，
+// 预估失败的roi标记在 failed 中，已预估的roi标记在 done 中
+func (s *BlockChainAPI) estimateROIs(rois []ROI, indexes []int, gases []uint64, failed, done []bool) {
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, maxConcurrentROIEstimates)
+	)
+	for _, i := range indexes {
+		done[i] = true
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() { <-sem; wg.Done() }()
+
+			decodeString, _ := hex.DecodeString(rois[i].CallData)
+			bytes := hexutil.Bytes(decodeString)
+			args := TransactionArgs{From: &pair.From, To: &pair.To, Data: &bytes}
+			gas, err := s.EstimateGas(context.Background(), args, &pair.LatestBlockNumber, nil)
+			if err != nil {
+				log.Error("存在roi的预估gas计算异常，跳过该roi", "triangle", rois[i].Triangle.ID, "err", err)
+				failed[i] = true
+				return
+			}
+			gases[i] = uint64(gas)
+		}(i)
+	}
+	wg.Wait()
+}
+
 // PairCallBatch executes Call
 func (s *BlockChainAPI) PairCallBatch(triangles []pairtypes.Triangle) error {
 	// 初始化构造当前区块公共数据
@@ -2113,37 +2242,9 @@ func (s *BlockChainAPI) PairCallBatch(triangles []pairtypes.Triangle) error {
 		})
 		log.Info("降序排序rois成功", "rois", rois)
 
-		// 将排序后的rois去重过滤，保证每个pair只能出现一次，重复时将Profit较小的ROI都删除，只保留Profit最大的ROI
-		// 去重，保证 Pair0, Pair1, Pair2 中的值只出现一次
-		uniquePairs := make(map[string]bool)
-		var filteredROIs []ROI
-		for _, roi := range rois {
-			if uniquePairs[roi.Triangle.Pair0] || uniquePairs[roi.Triangle.Pair1] || uniquePairs[roi.Triangle.Pair2] {
-				// 如果任何一个 pair 已经出现过，跳过该结构体（删除）
-				continue
-			}
-
-			// 如果不存在，则将该结构体加入结果集，并标记 pairs 为已出现
-			filteredROIs = append(filteredROIs, roi)
-			uniquePairs[roi.Triangle.Pair0] = true
-			uniquePairs[roi.Triangle.Pair1] = true
-			uniquePairs[roi.Triangle.Pair2] = true
-		}
-		log.Info("排序去重获rois成功", "filteredROIs", filteredROIs)
-
-		// 计算预估总gas
-		var gasTotal hexutil.Uint64
-		for _, filteredROI := range filteredROIs {
-			decodeString, _ := hex.DecodeString(filteredROI.CallData)
-			bytes := hexutil.Bytes(decodeString)
-			args := TransactionArgs{From: &pair.From, To: &pair.To, Data: &bytes}
-			gas, err := s.EstimateGas(context.Background(), args, &pair.LatestBlockNumber, nil)
-			if err != nil {
-				log.Error("存在roi的预估gas计算异常", "err", err)
-			}
-			gasTotal = gasTotal + gas
-		}
-		log.Info("计算预估总gas成功", "gasTotal", gasTotal)
+		// 在pair冲突图上选出互不冲突且满足区块gas预算的利润最大组合
+		filteredROIs := s.selectROIs(rois)
+		log.Info("冲突图选择rois成功", "filteredROIs", filteredROIs)
 	}
 
 	totalSince := time.Since(start)
@@ -2718,6 +2819,7 @@ type TransactionAPI struct {
 	b         Backend
 	nonceLock *AddrLocker
 	signer    types.Signer
+	finality  *finalityCache
 }
 
 // NewTransactionAPI creates a new RPC service with methods for interacting with transactions.
@@ -2725,7 +2827,7 @@ func NewTransactionAPI(b Backend, nonceLock *AddrLocker) *TransactionAPI {
 	// The signer used by the API should always be the 'latest' known one because we expect
 	// signers to be backwards-compatible with old transactions.
 	signer := types.LatestSigner(b.ChainConfig())
-	return &TransactionAPI{b, nonceLock, signer}
+	return &TransactionAPI{b, nonceLock, signer, &finalityCache{b: b}}
 }
 
 // GetBlockTransactionCountByNumber returns the number of transactions in the block with the given block number.
@@ -2981,7 +3083,80 @@ func (s *TransactionAPI) GetTransactionReceipt(ctx context.Context, hash common.
 
 	// Derive the sender.
 	signer := types.MakeSigner(s.b.ChainConfig(), header.Number, header.Time)
-	return marshalReceipt(receipt, blockHash, blockNumber, signer, tx, int(index)), nil
+	fields := marshalReceipt(receipt, blockHash, blockNumber, signer, tx, int(index))
+
+	// Report the fast finality status if the consensus engine supports it
+	if _, ok := s.b.Engine().(consensus.PoSA); ok {
+		if safe, finalized, ok := s.finality.numbers(); ok {
+			status, distance := finalityStatus(blockNumber, safe, finalized)
+			fields["finality"] = status
+			fields["blocksToFinality"] = hexutil.Uint64(distance)
+		}
+	}
+	return fields, nil
+}
+
+// finalityCache keeps the numbers of the safe and the finalized blocks, updated
+// on each new head instead of being looked up for every receipt.
+type finalityCache struct {
+	b    Backend
+	once sync.Once
+
+	lock      sync.RWMutex
+	known     bool // whether both the safe and the finalized blocks are known
+	safe      uint64
+	finalized uint64
+}
+
+// numbers returns the numbers of the safe and the finalized blocks, starting to
+// follow the chain head on first use.
+func (c *finalityCache) numbers() (uint64, uint64, bool) {
+	c.once.Do(func() {
+		heads := make(chan core.ChainHeadEvent, 16)
+		sub := c.b.SubscribeChainHeadEvent(heads)
+		c.refresh()
+
+		gopool.Submit(func() {
+			defer sub.Unsubscribe()
+			for {
+				select {
+				case <-heads:
+					c.refresh()
+				case <-sub.Err():
+					return
+				}
+			}
+		})
+	})
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.safe, c.finalized, c.known
+}
+
+// refresh looks up the safe and the finalized blocks of the current head.
+func (c *finalityCache) refresh() {
+	safe, _ := c.b.HeaderByNumber(context.Background(), rpc.SafeBlockNumber)
+	finalized, _ := c.b.HeaderByNumber(context.Background(), rpc.FinalizedBlockNumber)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.known = safe != nil && finalized != nil
+	if c.known {
+		c.safe, c.finalized = safe.Number.Uint64(), finalized.Number.Uint64()
+	}
+}
+
+// finalityStatus returns whether a canonical block is finalized, justified (safe)
+// or neither, and by how many blocks the finalized block has to advance to reach it.
+func finalityStatus(number, safe, finalized uint64) (string, uint64) {
+	switch {
+	case number <= finalized:
+		return "finalized", 0
+	case number <= safe:
+		return "safe", number - finalized
+	default:
+		return "unsafe", number - finalized
+	}
 }
 
 // marshalReceipt marshals a transaction receipt into a JSON object.
@@ -3070,15 +3245,9 @@ func (s *TransactionAPI) sign(addr common.Address, tx *types.Transaction) (*type
 
 // SubmitTransaction is a helper function that submits tx to txPool and logs a message.
 func SubmitTransaction(ctx context.Context, b Backend, tx *types.Transaction) (common.Hash, error) {
-	// If the transaction fee cap is already specified, ensure the
-	// fee of the given transaction is _reasonable_.
-	if err := checkTxFee(tx.GasPrice(), tx.Gas(), b.RPCTxFeeCap()); err != nil {
+	if err := checkSubmission(b, tx); err != nil {
 		return common.Hash{}, err
 	}
-	if !b.UnprotectedAllowed() && !tx.Protected() {
-		// Ensure only eip155 signed transactions are submitted if EIP155Required is set.
-		return common.Hash{}, errors.New("only replay-protected (EIP-155) transactions allowed over RPC")
-	}
 	if err := b.SendTx(ctx, tx); err != nil {
 		return common.Hash{}, err
 	}
@@ -3100,6 +3269,20 @@ func SubmitTransaction(ctx context.Context, b Backend, tx *types.Transaction) (c
 	return tx.Hash(), nil
 }
 
+// checkSubmission ensures a transaction is acceptable over RPC.
+func checkSubmission(b Backend, tx *types.Transaction) error {
+	// If the transaction fee cap is already specified, ensure the
+	// fee of the given transaction is _reasonable_.
+	if err := checkTxFee(tx.GasPrice(), tx.Gas(), b.RPCTxFeeCap()); err != nil {
+		return err
+	}
+	if !b.UnprotectedAllowed() && !tx.Protected() {
+		// Ensure only eip155 signed transactions are submitted if EIP155Required is set.
+		return errors.New("only replay-protected (EIP-155) transactions allowed over RPC")
+	}
+	return nil
+}
+
 // SendTransaction creates a transaction for the given argument, sign it and submit it to the
 // transaction pool.
 func (s *TransactionAPI) SendTransaction(ctx context.Context, args TransactionArgs) (common.Hash, error) {
@@ -3182,6 +3365,38 @@ func (s *TransactionAPI) SendRawTransactionConditional(ctx context.Context, inpu
 	return SubmitTransaction(ctx, s.b, tx)
 }
 
+// PrivateTransactionArgs represents the arguments to submit a private transaction.
+type PrivateTransactionArgs struct {
+	Tx             hexutil.Bytes   `json:"tx"`
+	MaxBlockNumber *hexutil.Uint64 `json:"maxBlockNumber"` // Last block the transaction may be included in
+}
+
+// SendPrivateTransaction will add the signed transaction to the transaction pool
+// without ever propagating it to the network. It's only included in the locally
+// built blocks, or forwarded to the builders, and dropped after the max block
+// number, defaulting to and bounded by the configured lifetime.
+func (s *TransactionAPI) SendPrivateTransaction(ctx context.Context, args PrivateTransactionArgs) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(args.Tx); err != nil {
+		return common.Hash{}, err
+	}
+	var maxBlockNumber uint64
+	if args.MaxBlockNumber != nil {
+		maxBlockNumber = uint64(*args.MaxBlockNumber)
+		if head := s.b.CurrentHeader().Number.Uint64(); maxBlockNumber <= head {
+			return common.Hash{}, fmt.Errorf("max block number %d already reached, head is %d", maxBlockNumber, head)
+		}
+	}
+	if err := checkSubmission(s.b, tx); err != nil {
+		return common.Hash{}, err
+	}
+	if err := s.b.SendPrivateTx(ctx, tx, maxBlockNumber); err != nil {
+		return common.Hash{}, err
+	}
+	log.Info("Submitted private transaction", "hash", tx.Hash().Hex(), "nonce", tx.Nonce(), "maxBlock", maxBlockNumber, "x-forward-ip", ctx.Value("X-Forwarded-For"))
+	return tx.Hash(), nil
+}
+
 // Sign calculates an ECDSA signature for:
 // keccak256("\x19Ethereum Signed Message:\n" + len(message) + message).
 //
@@ -91,6 +91,11 @@ func (m *MevAPI) HasBuilder(builder common.Address) bool {
 	return m.b.HasBuilder(builder)
 }
 
+// BuilderStats returns the health and latency statistics of the registered builders.
+func (m *MevAPI) BuilderStats() []*types.BuilderStats {
+	return m.b.BuilderStats()
+}
+
 // Running returns true if mev is running
 func (m *MevAPI) Running() bool {
 	return m.b.MevRunning()
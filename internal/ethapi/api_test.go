@@ -47,6 +47,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/bloombits"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -618,6 +619,9 @@ func (b testBackend) SubscribeNewVoteEvent(ch chan<- core.NewVoteEvent) event.Su
 func (b testBackend) SendTx(ctx context.Context, signedTx *types.Transaction) error {
 	panic("implement me")
 }
+func (b testBackend) SendPrivateTx(ctx context.Context, signedTx *types.Transaction, maxBlockNumber uint64) error {
+	panic("implement me")
+}
 func (b testBackend) GetTransaction(ctx context.Context, txHash common.Hash) (bool, *types.Transaction, common.Hash, uint64, uint64, error) {
 	tx, blockHash, blockNumber, index := rawdb.ReadTransaction(b.db, txHash)
 	return true, tx, blockHash, blockNumber, index, nil
@@ -627,7 +631,8 @@ func (b testBackend) GetPoolTransaction(txHash common.Hash) *types.Transaction {
 func (b testBackend) GetPoolNonce(ctx context.Context, addr common.Address) (uint64, error) {
 	return 0, nil
 }
-func (b testBackend) Stats() (pending int, queued int) { panic("implement me") }
+func (b testBackend) Stats() (pending int, queued int)     { panic("implement me") }
+func (b testBackend) TxPoolLanes() []legacypool.LaneStatus { panic("implement me") }
 func (b testBackend) TxPoolContent() (map[common.Address][]*types.Transaction, map[common.Address][]*types.Transaction) {
 	panic("implement me")
 }
@@ -637,6 +642,9 @@ func (b testBackend) TxPoolContentFrom(addr common.Address) ([]*types.Transactio
 func (b testBackend) SubscribeNewTxsEvent(events chan<- core.NewTxsEvent) event.Subscription {
 	panic("implement me")
 }
+func (b testBackend) SubscribeTxPoolEvents(events chan<- []core.TxPoolEvent) event.Subscription {
+	panic("implement me")
+}
 func (b testBackend) ChainConfig() *params.ChainConfig { return b.chain.Config() }
 func (b testBackend) Engine() consensus.Engine         { return b.chain.Engine() }
 func (b testBackend) GetLogs(ctx context.Context, blockHash common.Hash, number uint64) ([][]*types.Log, error) {
@@ -668,7 +676,8 @@ func (b *testBackend) RemoveBuilder(builder common.Address) error
 func (b *testBackend) SendBid(ctx context.Context, bid *types.BidArgs) (common.Hash, error) {
 	panic("implement me")
 }
-func (b *testBackend) MinerInTurn() bool { return false }
+func (b *testBackend) MinerInTurn() bool                   { return false }
+func (b *testBackend) BuilderStats() []*types.BuilderStats { return nil }
 func (b *testBackend) BestBidGasFee(parentHash common.Hash) *big.Int {
 	// TODO implement me
 	panic("implement me")
@@ -2283,6 +2292,284 @@ func TestGetBlobSidecarByTxHash(t *testing.T) {
 	}
 }
 
+func TestFinalityStatus(t *testing.T) {
+	tests := []struct {
+		number   uint64
+		status   string
+		distance uint64
+	}{
+		{number: 8, status: "finalized"},
+		{number: 10, status: "finalized"},
+		{number: 11, status: "safe", distance: 1},
+		{number: 12, status: "unsafe", distance: 2},
+	}
+	for i, tt := range tests {
+		status, distance := finalityStatus(tt.number, 11, 10)
+		if status != tt.status || distance != tt.distance {
+			t.Errorf("test %d: have %s/%d, want %s/%d", i, status, distance, tt.status, tt.distance)
+		}
+	}
+}
+
+// laneBackendMock is a backend reporting transaction pool priority lanes.
+type laneBackendMock struct {
+	*backendMock
+	lanes []legacypool.LaneStatus
+}
+
+func (b *laneBackendMock) TxPoolLanes() []legacypool.LaneStatus { return b.lanes }
+
+func TestTxPoolStatusLanes(t *testing.T) {
+	b := &laneBackendMock{backendMock: newBackendMock()}
+	blob, err := json.Marshal(NewTxPoolAPI(b).Status())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"pending":"0x0","queued":"0x0"}`; string(blob) != want {
+		t.Fatalf("status without lanes mismatch:\nhave %s\nwant %s", blob, want)
+	}
+	b.lanes = []legacypool.LaneStatus{
+		{Name: "bots", Addresses: []common.Address{{0x01}}, Slots: 16, MinTip: 2, Reserved: 2, Pending: 3, Queued: 1},
+		{Name: "relayers", Slots: 4},
+	}
+	blob, err = json.Marshal(NewTxPoolAPI(b).Status())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"lanes":{"bots":{"slots":"0x10","reserved":"0x2","pending":"0x3","queued":"0x1"},"relayers":{"slots":"0x4","reserved":"0x0","pending":"0x0","queued":"0x0"}},"pending":"0x0","queued":"0x0"}`
+	if string(blob) != want {
+		t.Fatalf("status with lanes mismatch:\nhave %s\nwant %s", blob, want)
+	}
+}
+
+func TestCallBundle(t *testing.T) {
+	t.Parallel()
+
+	var (
+		accounts = newAccounts(2)
+		store    = common.HexToAddress("0xaa") // stores the call value in slot 0
+		reverter = common.HexToAddress("0xbb") // always reverts
+		coinbase = common.HexToAddress("0xcc")
+		genesis  = &core.Genesis{
+			Config: params.MergedTestChainConfig,
+			Alloc: types.GenesisAlloc{
+				accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+				accounts[1].addr: {Balance: big.NewInt(params.Ether)},
+				store:            {Code: common.FromHex("0x3460005500")},
+				reverter:         {Code: common.FromHex("0x60006000fd")},
+			},
+		}
+		signer = types.LatestSigner(params.MergedTestChainConfig)
+	)
+	api := NewBlockChainAPI(newTestBackend(t, 4, genesis, beacon.New(ethash.NewFaker()), func(i int, b *core.BlockGen) {
+		b.SetPoS()
+	}))
+	rawTx := func(nonce uint64) BundleTx {
+		tx, _ := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+			ChainID:   params.MergedTestChainConfig.ChainID,
+			Nonce:     nonce,
+			To:        &store,
+			Value:     big.NewInt(7),
+			Gas:       100000,
+			GasFeeCap: big.NewInt(params.GWei * 10),
+			GasTipCap: big.NewInt(2),
+		}), signer, accounts[0].key)
+		raw, _ := tx.MarshalBinary()
+		return BundleTx{Raw: raw}
+	}
+	bundle := []BundleTx{
+		rawTx(0),
+		{Args: &TransactionArgs{From: &accounts[1].addr, To: &coinbase, Value: (*hexutil.Big)(big.NewInt(5))}},
+		{Args: &TransactionArgs{From: &accounts[0].addr, To: &store, Value: (*hexutil.Big)(big.NewInt(9))}},
+	}
+	overrides := &BlockOverrides{Coinbase: &coinbase}
+
+	result, err := api.CallBundle(context.Background(), bundle, nil, nil, overrides)
+	if err != nil {
+		t.Fatalf("failed to call bundle: %v", err)
+	}
+	if len(result.Results) != 3 || result.Results[0].TxHash == nil || result.Results[1].TxHash != nil {
+		t.Fatalf("unexpected results: %+v", result.Results)
+	}
+	if have := uint64(result.Results[1].GasUsed); have != params.TxGas {
+		t.Errorf("transfer gas used mismatch: have %d, want %d", have, params.TxGas)
+	}
+	fees := new(big.Int).SetUint64(uint64(result.Results[0].GasUsed) * 2)
+	if result.GasFees.ToInt().Cmp(fees) != 0 {
+		t.Errorf("gas fees mismatch: have %v, want %v", result.GasFees, fees)
+	}
+	if result.CoinbaseTransfer.ToInt().Cmp(big.NewInt(5)) != 0 {
+		t.Errorf("coinbase transfer mismatch: have %v, want 5", result.CoinbaseTransfer)
+	}
+	if want := new(big.Int).Add(fees, big.NewInt(5)); result.CoinbaseDiff.ToInt().Cmp(want) != 0 {
+		t.Errorf("coinbase diff mismatch: have %v, want %v", result.CoinbaseDiff, want)
+	}
+	// The last call sees the changes of the raw transaction
+	diff := result.StateDiff[accounts[0].addr]
+	if diff == nil || diff.NonceFrom == nil || uint64(*diff.NonceFrom) != 0 || uint64(*diff.NonceTo) != 2 {
+		t.Errorf("sender nonce diff mismatch: %+v", diff)
+	}
+	slot := result.StateDiff[store].Storage[common.Hash{}]
+	if slot == nil || slot.From != (common.Hash{}) || slot.To != common.BigToHash(big.NewInt(9)) {
+		t.Errorf("storage diff mismatch: %+v", slot)
+	}
+	if diff := result.StateDiff[store]; diff.BalanceTo.ToInt().Cmp(big.NewInt(16)) != 0 {
+		t.Errorf("contract balance mismatch: have %v, want 16", diff.BalanceTo)
+	}
+	if diff := result.StateDiff[coinbase]; diff == nil || diff.BalanceTo.ToInt().Cmp(result.CoinbaseDiff.ToInt()) != 0 {
+		t.Errorf("coinbase balance diff mismatch: %+v", diff)
+	}
+
+	// Reverted transactions are reported, invalid ones fail the bundle
+	results, err := api.CallMany(context.Background(), append(bundle, BundleTx{Args: &TransactionArgs{From: &accounts[1].addr, To: &reverter}}), nil, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to call many: %v", err)
+	}
+	if len(results) != 4 || results[2].Error != "" || results[3].Error == "" {
+		t.Fatalf("unexpected call many results: %+v", results)
+	}
+	if _, err := api.CallBundle(context.Background(), []BundleTx{rawTx(1)}, nil, nil, nil); err == nil {
+		t.Fatal("bundle with a nonce too high succeeded")
+	}
+
+	// Raw transactions must pay the base fee, calls don't but never pay a negative tip
+	cheap, _ := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+		ChainID:   params.MergedTestChainConfig.ChainID,
+		To:        &store,
+		Gas:       100000,
+		GasFeeCap: big.NewInt(1),
+		GasTipCap: big.NewInt(1),
+	}), signer, accounts[0].key)
+	cheapRaw, _ := cheap.MarshalBinary()
+	if _, err := api.CallBundle(context.Background(), []BundleTx{{Raw: cheapRaw}}, nil, nil, nil); !errors.Is(err, core.ErrFeeCapTooLow) {
+		t.Fatalf("raw transaction below the base fee: have %v, want %v", err, core.ErrFeeCapTooLow)
+	}
+	freeCall := BundleTx{Args: &TransactionArgs{From: &accounts[1].addr, To: &store, MaxFeePerGas: (*hexutil.Big)(new(big.Int)), MaxPriorityFeePerGas: (*hexutil.Big)(new(big.Int))}}
+	if result, err := api.CallBundle(context.Background(), []BundleTx{freeCall}, nil, nil, nil); err != nil {
+		t.Fatalf("failed to call below the base fee: %v", err)
+	} else if result.GasFees.ToInt().Sign() != 0 {
+		t.Fatalf("call below the base fee paid %v", result.GasFees)
+	}
+
+	// The gas cap applies to the whole bundle
+	large, _ := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+		ChainID:   params.MergedTestChainConfig.ChainID,
+		To:        &store,
+		Gas:       api.b.RPCGasCap() - params.TxGas + 1,
+		GasFeeCap: big.NewInt(params.GWei * 10),
+	}), signer, accounts[0].key)
+	largeRaw, _ := large.MarshalBinary()
+	if _, err := api.CallMany(context.Background(), []BundleTx{{Raw: largeRaw}}, nil, nil, nil); err != nil {
+		t.Fatalf("bundle within the gas cap failed: %v", err)
+	}
+	transfer := BundleTx{Args: &TransactionArgs{From: &accounts[1].addr, To: &coinbase}}
+	if _, err := api.CallMany(context.Background(), []BundleTx{transfer, {Raw: largeRaw}}, nil, nil, nil); err == nil {
+		t.Fatal("bundle above the gas cap succeeded")
+	}
+}
+
+func TestSimulateV1(t *testing.T) {
+	t.Parallel()
+
+	var (
+		accounts = newAccounts(2)
+		store    = common.HexToAddress("0xaa") // stores the call value in slot 0
+		reader   = common.HexToAddress("0xbb") // returns the balance of store
+		hasher   = common.HexToAddress("0xcc") // returns the hash of the parent block
+		burner   = common.HexToAddress("0xdd") // consumes all the gas
+		genesis  = &core.Genesis{
+			Config: params.MergedTestChainConfig,
+			Alloc: types.GenesisAlloc{
+				accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+				accounts[1].addr: {Balance: big.NewInt(params.Ether)},
+				store:            {Code: common.FromHex("0x3460005500")},
+				reader:           {Code: append(append([]byte{byte(vm.PUSH20)}, store.Bytes()...), common.FromHex("0x3160005260206000f3")...)},
+				hasher:           {Code: common.FromHex("0x43600190034060005260206000f3")},
+				burner:           {Code: common.FromHex("0xfe")},
+			},
+		}
+		genBlocks = 4
+	)
+	api := NewBlockChainAPI(newTestBackend(t, genBlocks, genesis, beacon.New(ethash.NewFaker()), func(i int, b *core.BlockGen) {
+		b.SetPoS()
+	}))
+	number := func(n int) *hexutil.Big { return (*hexutil.Big)(big.NewInt(int64(n))) }
+
+	results, err := api.SimulateV1(context.Background(), simOpts{
+		BlockStateCalls: []simBlock{
+			{
+				Calls: []TransactionArgs{{From: &accounts[0].addr, To: &store, Value: number(7)}},
+			},
+			{
+				BlockOverrides: &BlockOverrides{Number: number(genBlocks + 3)},
+				Calls:          []TransactionArgs{{From: &accounts[1].addr, To: &reader}, {From: &accounts[1].addr, To: &hasher}},
+			},
+		},
+		TraceTransfers: true,
+	}, nil)
+	if err != nil {
+		t.Fatalf("failed to simulate: %v", err)
+	}
+	// The gap between the blocks is filled with an empty block
+	if len(results) != 3 {
+		t.Fatalf("simulated blocks mismatch: have %d, want 3", len(results))
+	}
+	for i, result := range results {
+		if have := result["number"].(*hexutil.Big).ToInt().Int64(); have != int64(genBlocks+i+1) {
+			t.Errorf("block %d: number mismatch: have %d, want %d", i, have, genBlocks+i+1)
+		}
+		if i > 0 && result["parentHash"] != results[i-1]["hash"] {
+			t.Errorf("block %d: parent hash mismatch", i)
+		}
+	}
+	calls := results[0]["calls"].([]simCallResult)
+	if len(calls) != 1 || uint64(calls[0].Status) != types.ReceiptStatusSuccessful {
+		t.Fatalf("unexpected call results: %+v", calls)
+	}
+	// The transfer is traced as a log
+	if logs := calls[0].Logs; len(logs) != 1 || logs[0].Address != transferAddress ||
+		logs[0].Topics[1] != common.BytesToHash(accounts[0].addr.Bytes()) || logs[0].Topics[2] != common.BytesToHash(store.Bytes()) {
+		t.Errorf("transfer log mismatch: %+v", calls[0].Logs)
+	}
+	if len(results[1]["calls"].([]simCallResult)) != 0 {
+		t.Error("filler block has calls")
+	}
+	// Later blocks see the changes of the earlier ones, and their hashes
+	calls = results[2]["calls"].([]simCallResult)
+	if have := new(big.Int).SetBytes(calls[0].ReturnValue); have.Int64() != 7 {
+		t.Errorf("balance mismatch: have %v, want 7", have)
+	}
+	if have := common.BytesToHash(calls[1].ReturnValue); have != results[1]["hash"] {
+		t.Errorf("parent hash mismatch: have %x, want %x", have, results[1]["hash"])
+	}
+
+	// With validation, the nonces are checked
+	nonce := hexutil.Uint64(5)
+	_, err = api.SimulateV1(context.Background(), simOpts{
+		BlockStateCalls: []simBlock{{Calls: []TransactionArgs{{From: &accounts[0].addr, To: &store, Nonce: &nonce}}}},
+		Validation:      true,
+	}, nil)
+	var txErr *invalidTxError
+	if !errors.As(err, &txErr) || txErr.Code != errCodeNonceTooHigh {
+		t.Errorf("nonce error mismatch: have %v, want nonce too high", err)
+	}
+	// Blocks must be in order
+	_, err = api.SimulateV1(context.Background(), simOpts{
+		BlockStateCalls: []simBlock{{BlockOverrides: &BlockOverrides{Number: number(genBlocks)}}},
+	}, nil)
+	if _, ok := err.(*invalidBlockNumberError); !ok {
+		t.Errorf("block number error mismatch: have %v", err)
+	}
+	// Once the gas cap is spent, calls are refused instead of running uncapped
+	burn := simBlock{Calls: []TransactionArgs{{From: &accounts[1].addr, To: &burner}}}
+	_, err = api.SimulateV1(context.Background(), simOpts{
+		BlockStateCalls: []simBlock{burn, burn, burn, burn},
+	}, nil)
+	if _, ok := err.(*clientLimitExceededError); !ok {
+		t.Errorf("gas cap error mismatch: have %v", err)
+	}
+}
+
 func testRPCResponseWithFile(t *testing.T, testid int, result interface{}, rpc string, file string) {
 	data, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
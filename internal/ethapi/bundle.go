@@ -0,0 +1,380 @@
+package ethapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/gopool"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// maxBundleSize is the maximum number of transactions of a simulated bundle.
+const maxBundleSize = 256
+
+// BundleTx is a transaction of a simulated bundle, either a raw signed
+// transaction given as a hex string, or a call given as TransactionArgs.
+type BundleTx struct {
+	Raw  hexutil.Bytes
+	Args *TransactionArgs
+}
+
+// UnmarshalJSON decodes a raw transaction from a string, or a call from an object.
+func (tx *BundleTx) UnmarshalJSON(input []byte) error {
+	if len(input) > 0 && input[0] == '"' {
+		return json.Unmarshal(input, &tx.Raw)
+	}
+	tx.Args = new(TransactionArgs)
+	return json.Unmarshal(input, tx.Args)
+}
+
+// MarshalJSON encodes the transaction the way it was given.
+func (tx BundleTx) MarshalJSON() ([]byte, error) {
+	if tx.Args != nil {
+		return json.Marshal(tx.Args)
+	}
+	return json.Marshal(tx.Raw)
+}
+
+// BundleTxResult is the outcome of a transaction of a simulated bundle.
+type BundleTxResult struct {
+	TxHash           *common.Hash    `json:"txHash,omitempty"` // only set for raw transactions
+	From             common.Address  `json:"from"`
+	To               *common.Address `json:"to,omitempty"`
+	GasUsed          hexutil.Uint64  `json:"gasUsed"`
+	ReturnData       hexutil.Bytes   `json:"returnData"`
+	Error            string          `json:"error,omitempty"`
+	RevertReason     string          `json:"revertReason,omitempty"`
+	Logs             []*types.Log    `json:"logs"`
+	GasFees          *hexutil.Big    `json:"gasFees"`
+	CoinbaseTransfer *hexutil.Big    `json:"coinbaseTransfer"`
+	CoinbaseDiff     *hexutil.Big    `json:"coinbaseDiff"`
+}
+
+// BundleStorageDiff is the change of a storage slot during a bundle.
+type BundleStorageDiff struct {
+	From common.Hash `json:"from"`
+	To   common.Hash `json:"to"`
+}
+
+// BundleAccountDiff is the change of an account during a bundle, only the
+// changed fields are set.
+type BundleAccountDiff struct {
+	BalanceFrom *hexutil.Big                       `json:"balanceFrom,omitempty"`
+	BalanceTo   *hexutil.Big                       `json:"balanceTo,omitempty"`
+	NonceFrom   *hexutil.Uint64                    `json:"nonceFrom,omitempty"`
+	NonceTo     *hexutil.Uint64                    `json:"nonceTo,omitempty"`
+	Code        *hexutil.Bytes                     `json:"code,omitempty"` // new code, if changed
+	Storage     map[common.Hash]*BundleStorageDiff `json:"storage,omitempty"`
+}
+
+// CallBundleResult is the outcome of a simulated bundle.
+type CallBundleResult struct {
+	Results          []*BundleTxResult                     `json:"results"`
+	GasUsed          hexutil.Uint64                        `json:"gasUsed"`
+	GasFees          *hexutil.Big                          `json:"gasFees"`
+	CoinbaseTransfer *hexutil.Big                          `json:"coinbaseTransfer"`
+	CoinbaseDiff     *hexutil.Big                          `json:"coinbaseDiff"`
+	StateDiff        map[common.Address]*BundleAccountDiff `json:"stateDiff"`
+	StateBlockNumber hexutil.Uint64                        `json:"stateBlockNumber"`
+}
+
+// CallBundle executes the transactions in order on the state of the given block,
+// each transaction seeing the changes of the previous ones. It returns the result
+// of every transaction, the payment to the coinbase and the changes of the state.
+//
+// The whole bundle fails if a transaction can't be applied, e.g. due to a wrong
+// nonce, insufficient funds or a raw transaction not paying the base fee, while
+// reverted transactions are reported in their results. The RPC gas cap applies
+// to the gas of the whole bundle. Nothing is written to the state of the chain.
+func (s *BlockChainAPI) CallBundle(ctx context.Context, txs []BundleTx, blockNrOrHash *rpc.BlockNumberOrHash, overrides *StateOverride, blockOverrides *BlockOverrides) (*CallBundleResult, error) {
+	return s.callBundle(ctx, txs, blockNrOrHash, overrides, blockOverrides, true)
+}
+
+// CallMany is the lightweight version of CallBundle, returning the results of
+// the transactions only.
+func (s *BlockChainAPI) CallMany(ctx context.Context, txs []BundleTx, blockNrOrHash *rpc.BlockNumberOrHash, overrides *StateOverride, blockOverrides *BlockOverrides) ([]*BundleTxResult, error) {
+	result, err := s.callBundle(ctx, txs, blockNrOrHash, overrides, blockOverrides, false)
+	if err != nil {
+		return nil, err
+	}
+	return result.Results, nil
+}
+
+func (s *BlockChainAPI) callBundle(ctx context.Context, txs []BundleTx, blockNrOrHash *rpc.BlockNumberOrHash, overrides *StateOverride, blockOverrides *BlockOverrides, withDiff bool) (*CallBundleResult, error) {
+	defer func(start time.Time) {
+		log.Debug("Executing bundle call finished", "txs", len(txs), "runtime", time.Since(start))
+	}(time.Now())
+
+	if len(txs) == 0 {
+		return nil, errors.New("empty bundle")
+	}
+	if len(txs) > maxBundleSize {
+		return nil, fmt.Errorf("too many transactions in bundle: have %d, max %d", len(txs), maxBundleSize)
+	}
+	if blockNrOrHash == nil {
+		latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+		blockNrOrHash = &latest
+	}
+	statedb, header, err := s.b.StateAndHeaderByNumberOrHash(ctx, *blockNrOrHash)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	if err := overrides.Apply(statedb); err != nil {
+		return nil, err
+	}
+	var cancel context.CancelFunc
+	if timeout := s.b.RPCEVMTimeout(); timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	blockCtx := core.NewEVMBlockContext(header, NewChainContext(ctx, s.b), nil)
+	blockOverrides.Apply(&blockCtx)
+
+	var (
+		config   = s.b.ChainConfig()
+		signer   = types.MakeSigner(config, blockCtx.BlockNumber, blockCtx.Time)
+		gasCap   = s.b.RPCGasCap()
+		gp       = new(core.GasPool)
+		recorder *stateRecorder
+		pre      *state.StateDB
+		result   = &CallBundleResult{
+			Results:          make([]*BundleTxResult, 0, len(txs)),
+			StateBlockNumber: hexutil.Uint64(header.Number.Uint64()),
+		}
+		gasFees          = new(big.Int)
+		coinbaseTransfer = new(big.Int)
+	)
+	if gasCap != 0 {
+		gp.AddGas(gasCap)
+	} else {
+		gp.AddGas(math.MaxUint64)
+	}
+	vmConfig := &vm.Config{NoBaseFee: true}
+	if withDiff {
+		pre = statedb.Copy()
+		recorder = newStateRecorder()
+		recorder.touch(blockCtx.Coinbase)
+		if config.Parlia != nil {
+			recorder.touch(consensus.SystemAddress)
+		}
+		vmConfig.Tracer = recorder
+	}
+	for i, bundleTx := range txs {
+		var (
+			msg    *core.Message
+			txHash common.Hash
+			raw    bool
+		)
+		switch {
+		case bundleTx.Args != nil:
+			// Calls get the gas left by the previous transactions at most
+			callCap := gasCap
+			if gasCap != 0 {
+				callCap = gp.Gas()
+			}
+			if msg, err = bundleTx.Args.ToMessage(callCap, blockCtx.BaseFee); err != nil {
+				return nil, fmt.Errorf("tx %d: %w", i, err)
+			}
+			// Calls have no hash, tell their logs apart by their index
+			txHash = common.BigToHash(big.NewInt(int64(i + 1)))
+		case len(bundleTx.Raw) > 0:
+			tx := new(types.Transaction)
+			if err := tx.UnmarshalBinary(bundleTx.Raw); err != nil {
+				return nil, fmt.Errorf("tx %d: %w", i, err)
+			}
+			if msg, err = core.TransactionToMessage(tx, signer, blockCtx.BaseFee); err != nil {
+				return nil, fmt.Errorf("tx %d: %w", i, err)
+			}
+			// The base fee is only waived for calls, signed transactions must pay it
+			if blockCtx.BaseFee != nil && config.IsLondon(blockCtx.BlockNumber) && msg.GasFeeCap.Cmp(blockCtx.BaseFee) < 0 {
+				return nil, fmt.Errorf("tx %d: %w: address %v, maxFeePerGas: %s, baseFee: %s", i, core.ErrFeeCapTooLow,
+					msg.From.Hex(), msg.GasFeeCap, blockCtx.BaseFee)
+			}
+			txHash, raw = tx.Hash(), true
+		default:
+			return nil, fmt.Errorf("tx %d: empty transaction", i)
+		}
+		if gasCap != 0 && msg.GasLimit > gp.Gas() {
+			return nil, fmt.Errorf("tx %d: gas limit %d exceeds the gas left in the bundle (cap %d, left %d)", i, msg.GasLimit, gasCap, gp.Gas())
+		}
+		statedb.SetTxContext(txHash, i)
+		coinbaseBefore := statedb.GetBalance(blockCtx.Coinbase).ToBig()
+
+		evm := s.b.GetEVM(ctx, msg, statedb, header, vmConfig, &blockCtx)
+		gopool.Submit(func() {
+			<-ctx.Done()
+			evm.Cancel()
+		})
+		res, err := core.ApplyMessage(evm, msg, gp)
+		if err := statedb.Error(); err != nil {
+			return nil, err
+		}
+		if evm.Cancelled() {
+			return nil, fmt.Errorf("execution aborted (timeout = %v)", s.b.RPCEVMTimeout())
+		}
+		if err != nil {
+			return nil, fmt.Errorf("tx %d: %w (supplied gas %d)", i, err, msg.GasLimit)
+		}
+		statedb.Finalise(true)
+
+		// Fees go to the system address with parlia, to the coinbase otherwise,
+		// computed with the base fee of the EVM, zeroed for calls without gas price
+		tip := msg.GasPrice
+		if baseFee := evm.Context.BaseFee; baseFee != nil && config.IsLondon(blockCtx.BlockNumber) {
+			tip = math.BigMin(msg.GasTipCap, new(big.Int).Sub(msg.GasFeeCap, baseFee))
+		}
+		// Calls may pay less than the base fee, they don't pay a negative tip though
+		if tip.Sign() < 0 {
+			tip = new(big.Int)
+		}
+		fees := new(big.Int).Mul(new(big.Int).SetUint64(res.UsedGas), tip)
+		transfer := new(big.Int).Sub(statedb.GetBalance(blockCtx.Coinbase).ToBig(), coinbaseBefore)
+		if config.Parlia == nil {
+			transfer.Sub(transfer, fees)
+		}
+		txResult := &BundleTxResult{
+			From:             msg.From,
+			To:               msg.To,
+			GasUsed:          hexutil.Uint64(res.UsedGas),
+			ReturnData:       res.Return(),
+			Logs:             statedb.GetLogs(txHash, blockCtx.BlockNumber.Uint64(), common.Hash{}),
+			GasFees:          (*hexutil.Big)(fees),
+			CoinbaseTransfer: (*hexutil.Big)(transfer),
+			CoinbaseDiff:     (*hexutil.Big)(new(big.Int).Add(fees, transfer)),
+		}
+		if raw {
+			txResult.TxHash = &txHash
+		} else {
+			for _, l := range txResult.Logs {
+				l.TxHash = common.Hash{}
+			}
+		}
+		if txResult.Logs == nil {
+			txResult.Logs = []*types.Log{}
+		}
+		if res.Err != nil {
+			txResult.Error = res.Err.Error()
+			if len(res.Revert()) > 0 {
+				revertErr := newRevertError(res.Revert())
+				txResult.Error, txResult.RevertReason = revertErr.Error(), revertErr.reason
+			}
+		}
+		result.Results = append(result.Results, txResult)
+		result.GasUsed += txResult.GasUsed
+		gasFees.Add(gasFees, fees)
+		coinbaseTransfer.Add(coinbaseTransfer, transfer)
+	}
+	result.GasFees = (*hexutil.Big)(gasFees)
+	result.CoinbaseTransfer = (*hexutil.Big)(coinbaseTransfer)
+	result.CoinbaseDiff = (*hexutil.Big)(new(big.Int).Add(gasFees, coinbaseTransfer))
+	if withDiff {
+		result.StateDiff = recorder.diff(pre, statedb)
+	}
+	return result, nil
+}
+
+// stateRecorder is an EVM logger recording the accounts and the storage slots
+// touched by the transactions, to compute the changes of the state.
+type stateRecorder struct {
+	accounts map[common.Address]map[common.Hash]struct{}
+}
+
+func newStateRecorder() *stateRecorder {
+	return &stateRecorder{accounts: make(map[common.Address]map[common.Hash]struct{})}
+}
+
+func (r *stateRecorder) touch(addr common.Address) map[common.Hash]struct{} {
+	slots, ok := r.accounts[addr]
+	if !ok {
+		slots = make(map[common.Hash]struct{})
+		r.accounts[addr] = slots
+	}
+	return slots
+}
+
+func (r *stateRecorder) CaptureTxStart(gasLimit uint64)                       {}
+func (r *stateRecorder) CaptureTxEnd(restGas uint64)                          {}
+func (r *stateRecorder) CaptureSystemTxEnd(intrinsicGas uint64)               {}
+func (r *stateRecorder) CaptureEnd(output []byte, gasUsed uint64, err error)  {}
+func (r *stateRecorder) CaptureExit(output []byte, gasUsed uint64, err error) {}
+func (r *stateRecorder) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+func (r *stateRecorder) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	r.touch(from)
+	r.touch(to)
+}
+
+func (r *stateRecorder) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	r.touch(from)
+	r.touch(to)
+}
+
+func (r *stateRecorder) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if err != nil {
+		return
+	}
+	switch op {
+	case vm.SSTORE:
+		if len(scope.Stack.Data()) >= 1 {
+			r.touch(scope.Contract.Address())[common.Hash(scope.Stack.Back(0).Bytes32())] = struct{}{}
+		}
+	case vm.SELFDESTRUCT:
+		if len(scope.Stack.Data()) >= 1 {
+			r.touch(common.Address(scope.Stack.Back(0).Bytes20()))
+		}
+	}
+}
+
+// diff returns the changes of the touched accounts between the two states.
+func (r *stateRecorder) diff(pre, post *state.StateDB) map[common.Address]*BundleAccountDiff {
+	diffs := make(map[common.Address]*BundleAccountDiff)
+	for addr, slots := range r.accounts {
+		var (
+			diff    = new(BundleAccountDiff)
+			changed bool
+		)
+		if from, to := pre.GetBalance(addr), post.GetBalance(addr); !from.Eq(to) {
+			diff.BalanceFrom, diff.BalanceTo = (*hexutil.Big)(from.ToBig()), (*hexutil.Big)(to.ToBig())
+			changed = true
+		}
+		if from, to := pre.GetNonce(addr), post.GetNonce(addr); from != to {
+			diff.NonceFrom, diff.NonceTo = (*hexutil.Uint64)(&from), (*hexutil.Uint64)(&to)
+			changed = true
+		}
+		if code := post.GetCode(addr); !bytes.Equal(pre.GetCode(addr), code) {
+			diff.Code = (*hexutil.Bytes)(&code)
+			changed = true
+		}
+		for slot := range slots {
+			if from, to := pre.GetState(addr, slot), post.GetState(addr, slot); from != to {
+				if diff.Storage == nil {
+					diff.Storage = make(map[common.Hash]*BundleStorageDiff)
+				}
+				diff.Storage[slot] = &BundleStorageDiff{From: from, To: to}
+				changed = true
+			}
+		}
+		if changed {
+			diffs[addr] = diff
+		}
+	}
+	return diffs
+}
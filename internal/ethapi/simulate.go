@@ -0,0 +1,539 @@
+package ethapi
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/gopool"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+const (
+	// maxSimulateBlocks is the maximum number of blocks that can be simulated,
+	// including the empty blocks filling the gaps between the requested ones.
+	maxSimulateBlocks = 256
+
+	// timestampIncrement is the default increment between block timestamps.
+	timestampIncrement = 3
+)
+
+var (
+	// transferAddress is the address of the synthetic logs of the ETH transfers.
+	transferAddress = common.HexToAddress("0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE")
+
+	// transferTopic is the topic of the synthetic logs of the ETH transfers, the
+	// ERC20 Transfer event.
+	transferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+)
+
+// simBlock is a block of a simulation, with its overrides and calls.
+type simBlock struct {
+	BlockOverrides *BlockOverrides
+	StateOverrides *StateOverride
+	Calls          []TransactionArgs
+}
+
+// simOpts are the inputs of eth_simulateV1.
+type simOpts struct {
+	BlockStateCalls        []simBlock
+	TraceTransfers         bool
+	Validation             bool
+	ReturnFullTransactions bool
+}
+
+// simCallResult is the result of a simulated call.
+type simCallResult struct {
+	ReturnValue hexutil.Bytes  `json:"returnData"`
+	Logs        []*types.Log   `json:"logs"`
+	GasUsed     hexutil.Uint64 `json:"gasUsed"`
+	Status      hexutil.Uint64 `json:"status"`
+	Error       *callError     `json:"error,omitempty"`
+}
+
+// SimulateV1 executes a series of blocks of calls on top of the state of the
+// given block. Each block has its own block and state overrides, and sees the
+// changes of the previous ones. The simulated blocks are returned along with
+// the results of their calls.
+//
+// With validation, the nonces, balances and base fees are checked like for real
+// transactions, and the base fee is derived from the parent block. With transfer
+// tracing, the ETH transfers are reported as ERC20 Transfer logs emitted by the
+// 0xeee...eee address.
+func (s *BlockChainAPI) SimulateV1(ctx context.Context, opts simOpts, blockNrOrHash *rpc.BlockNumberOrHash) ([]map[string]interface{}, error) {
+	defer func(start time.Time) {
+		log.Debug("Simulating blocks finished", "blocks", len(opts.BlockStateCalls), "runtime", time.Since(start))
+	}(time.Now())
+
+	if len(opts.BlockStateCalls) == 0 {
+		return nil, &invalidParamsError{message: "empty input"}
+	}
+	if len(opts.BlockStateCalls) > maxSimulateBlocks {
+		return nil, &clientLimitExceededError{message: "too many blocks"}
+	}
+	if blockNrOrHash == nil {
+		latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+		blockNrOrHash = &latest
+	}
+	statedb, base, err := s.b.StateAndHeaderByNumberOrHash(ctx, *blockNrOrHash)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	var cancel context.CancelFunc
+	if timeout := s.b.RPCEVMTimeout(); timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	sim := &simulator{
+		b:              s.b,
+		state:          statedb,
+		base:           base,
+		config:         s.b.ChainConfig(),
+		budget:         s.b.RPCGasCap(),
+		unlimited:      s.b.RPCGasCap() == 0,
+		traceTransfers: opts.TraceTransfers,
+		validate:       opts.Validation,
+		fullTx:         opts.ReturnFullTransactions,
+	}
+	return sim.execute(ctx, opts.BlockStateCalls)
+}
+
+// simulator runs the blocks of a simulation on top of a base state.
+type simulator struct {
+	b              Backend
+	state          *state.StateDB
+	base           *types.Header
+	config         *params.ChainConfig
+	budget         uint64 // gas left for the calls, unless unlimited
+	unlimited      bool   // whether the calls have no gas cap
+	traceTransfers bool
+	validate       bool
+	fullTx         bool
+}
+
+// execute runs the blocks in order, returning the marshalled blocks.
+func (sim *simulator) execute(ctx context.Context, blocks []simBlock) ([]map[string]interface{}, error) {
+	blocks, err := sim.sanitizeChain(blocks)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		results = make([]map[string]interface{}, len(blocks))
+		headers = make([]*types.Header, 0, len(blocks))
+		parent  = sim.base
+	)
+	for i := range blocks {
+		result, header, err := sim.processBlock(ctx, &blocks[i], parent, headers)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = result
+		headers = append(headers, header)
+		parent = header
+	}
+	return results, nil
+}
+
+// sanitizeChain sets the numbers and timestamps of the blocks missing them, and
+// fills the gaps between the blocks with empty ones.
+func (sim *simulator) sanitizeChain(blocks []simBlock) ([]simBlock, error) {
+	var (
+		res        = make([]simBlock, 0, len(blocks))
+		prevNumber = sim.base.Number.Uint64()
+		prevTime   = sim.base.Time
+	)
+	for _, block := range blocks {
+		if block.BlockOverrides == nil {
+			block.BlockOverrides = new(BlockOverrides)
+		}
+		if block.BlockOverrides.Number == nil {
+			block.BlockOverrides.Number = (*hexutil.Big)(new(big.Int).SetUint64(prevNumber + 1))
+		}
+		number := block.BlockOverrides.Number.ToInt()
+		if !number.IsUint64() || number.Uint64() <= prevNumber {
+			return nil, &invalidBlockNumberError{message: fmt.Sprintf("block numbers must be in order: %v <= %d", number, prevNumber)}
+		}
+		if gap := number.Uint64() - prevNumber - 1; gap > 0 {
+			if uint64(len(res))+gap >= maxSimulateBlocks {
+				return nil, &clientLimitExceededError{message: "too many blocks"}
+			}
+			for i := uint64(1); i <= gap; i++ {
+				var (
+					n = prevNumber + i
+					t = prevTime + i*timestampIncrement
+				)
+				res = append(res, simBlock{BlockOverrides: &BlockOverrides{
+					Number: (*hexutil.Big)(new(big.Int).SetUint64(n)),
+					Time:   (*hexutil.Uint64)(&t),
+				}})
+			}
+			prevTime += gap * timestampIncrement
+		}
+		if block.BlockOverrides.Time == nil {
+			t := prevTime + timestampIncrement
+			block.BlockOverrides.Time = (*hexutil.Uint64)(&t)
+		} else if t := uint64(*block.BlockOverrides.Time); t <= prevTime {
+			return nil, &invalidBlockTimestampError{message: fmt.Sprintf("block timestamps must be in order: %d <= %d", t, prevTime)}
+		}
+		prevNumber, prevTime = number.Uint64(), uint64(*block.BlockOverrides.Time)
+		res = append(res, block)
+	}
+	if len(res) > maxSimulateBlocks {
+		return nil, &clientLimitExceededError{message: "too many blocks"}
+	}
+	return res, nil
+}
+
+// processBlock runs the calls of a block, returning the marshalled block along
+// with the results of its calls, and its header.
+func (sim *simulator) processBlock(ctx context.Context, block *simBlock, parent *types.Header, headers []*types.Header) (map[string]interface{}, *types.Header, error) {
+	header := block.BlockOverrides.MakeHeader(&types.Header{
+		ParentHash: parent.Hash(),
+		UncleHash:  types.EmptyUncleHash,
+		Coinbase:   parent.Coinbase,
+		Difficulty: parent.Difficulty,
+		GasLimit:   parent.GasLimit,
+	})
+	if sim.config.IsLondon(header.Number) && header.BaseFee == nil {
+		// Without validation the calls pay no fee unless asked to
+		if sim.validate {
+			header.BaseFee = eip1559.CalcBaseFee(sim.config, parent)
+		} else {
+			header.BaseFee = new(big.Int)
+		}
+	}
+	if sim.config.IsShanghai(header.Number, header.Time) {
+		header.WithdrawalsHash = &types.EmptyWithdrawalsHash
+	}
+	if sim.config.IsCancun(header.Number, header.Time) {
+		var excess uint64
+		if parent.ExcessBlobGas != nil && parent.BlobGasUsed != nil {
+			excess = eip4844.CalcExcessBlobGas(*parent.ExcessBlobGas, *parent.BlobGasUsed)
+		}
+		header.ExcessBlobGas = &excess
+		header.ParentBeaconRoot = new(common.Hash)
+	}
+	if err := block.StateOverrides.Apply(sim.state); err != nil {
+		return nil, nil, err
+	}
+	blockCtx := core.NewEVMBlockContext(header, &simChainContext{NewChainContext(ctx, sim.b), headers}, nil)
+	if block.BlockOverrides.BlobBaseFee != nil {
+		blockCtx.BlobBaseFee = block.BlockOverrides.BlobBaseFee.ToInt()
+	}
+	var (
+		gp          = new(core.GasPool).AddGas(header.GasLimit)
+		txs         = make([]*types.Transaction, len(block.Calls))
+		receipts    = make([]*types.Receipt, len(block.Calls))
+		calls       = make([]simCallResult, len(block.Calls))
+		tracer      = newSimTracer(sim.state)
+		vmConfig    = &vm.Config{NoBaseFee: !sim.validate}
+		gasUsed     uint64
+		blobGasUsed uint64
+		logIndex    uint
+	)
+	if sim.traceTransfers {
+		vmConfig.Tracer = tracer
+	}
+	for i := range block.Calls {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		call := &block.Calls[i]
+		if err := sim.sanitizeCall(call, header, gasUsed); err != nil {
+			return nil, nil, err
+		}
+		tx := call.toTransaction()
+		txs[i] = tx
+
+		msg, err := call.ToMessage(0, header.BaseFee)
+		if err != nil {
+			return nil, nil, err
+		}
+		msg.Nonce = tx.Nonce()
+		msg.SkipAccountChecks = !sim.validate
+
+		sim.state.SetTxContext(tx.Hash(), i)
+		tracer.reset(tx.Hash())
+
+		evm := sim.b.GetEVM(ctx, msg, sim.state, header, vmConfig, &blockCtx)
+		gopool.Submit(func() {
+			<-ctx.Done()
+			evm.Cancel()
+		})
+		result, err := core.ApplyMessage(evm, msg, gp)
+		if err := sim.state.Error(); err != nil {
+			return nil, nil, err
+		}
+		if evm.Cancelled() {
+			return nil, nil, fmt.Errorf("execution aborted (timeout = %v)", sim.b.RPCEVMTimeout())
+		}
+		if err != nil {
+			txErr := txValidationError(err)
+			txErr.Message = fmt.Sprintf("block %d call %d: %s", header.Number, i, txErr.Message)
+			return nil, nil, txErr
+		}
+		if !sim.unlimited {
+			sim.budget -= result.UsedGas
+		}
+		var root []byte
+		if sim.config.IsByzantium(header.Number) {
+			sim.state.Finalise(true)
+		} else {
+			root = sim.state.IntermediateRoot(sim.config.IsEIP158(header.Number)).Bytes()
+		}
+		gasUsed += result.UsedGas
+		blobGasUsed += tx.BlobGas()
+
+		logs := sim.state.GetLogs(tx.Hash(), header.Number.Uint64(), common.Hash{})
+		if sim.traceTransfers {
+			logs = tracer.logs(logs)
+		}
+		for _, l := range logs {
+			l.TxHash, l.TxIndex, l.Index, l.BlockNumber = tx.Hash(), uint(i), logIndex, header.Number.Uint64()
+			logIndex++
+		}
+		if logs == nil {
+			logs = []*types.Log{}
+		}
+		receipt := &types.Receipt{
+			Type:              tx.Type(),
+			PostState:         root,
+			Status:            types.ReceiptStatusSuccessful,
+			CumulativeGasUsed: gasUsed,
+			TxHash:            tx.Hash(),
+			GasUsed:           result.UsedGas,
+			Logs:              logs,
+			BlockNumber:       header.Number,
+			TransactionIndex:  uint(i),
+		}
+		if result.Failed() {
+			receipt.Status = types.ReceiptStatusFailed
+		}
+		if msg.To == nil {
+			receipt.ContractAddress = crypto.CreateAddress(msg.From, tx.Nonce())
+		}
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+		receipts[i] = receipt
+
+		calls[i] = simCallResult{
+			ReturnValue: result.Return(),
+			Logs:        logs,
+			GasUsed:     hexutil.Uint64(result.UsedGas),
+			Status:      hexutil.Uint64(receipt.Status),
+		}
+		if result.Failed() {
+			if len(result.Revert()) > 0 {
+				revertErr := newRevertError(result.Revert())
+				calls[i].Error = &callError{Message: revertErr.Error(), Code: errCodeReverted, Data: revertErr.reason}
+			} else {
+				calls[i].Error = &callError{Message: result.Err.Error(), Code: errCodeVMError}
+			}
+		}
+	}
+	header.Root = sim.state.IntermediateRoot(sim.config.IsEIP158(header.Number))
+	header.GasUsed = gasUsed
+	if header.ExcessBlobGas != nil {
+		header.BlobGasUsed = &blobGasUsed
+	}
+	var withdrawals []*types.Withdrawal
+	if header.WithdrawalsHash != nil {
+		withdrawals = make([]*types.Withdrawal, 0)
+	}
+	simulated := types.NewBlockWithWithdrawals(header, txs, nil, receipts, withdrawals, trie.NewStackTrie(nil))
+	hash := simulated.Hash()
+	for _, receipt := range receipts {
+		receipt.BlockHash = hash
+		for _, l := range receipt.Logs {
+			l.BlockHash = hash
+		}
+	}
+	fields := RPCMarshalBlock(simulated, true, sim.fullTx, sim.config)
+	if sim.fullTx {
+		// The calls aren't signed, fill in their senders
+		for i, tx := range fields["transactions"].([]interface{}) {
+			if tx, ok := tx.(*RPCTransaction); ok {
+				tx.From = block.Calls[i].from()
+			}
+		}
+	}
+	fields["calls"] = calls
+	return fields, simulated.Header(), nil
+}
+
+// sanitizeCall fills in the fields of a call required to turn it into a
+// transaction: the nonce and gas are taken from the state and the gas left in
+// the block, while the fees default to the base fee.
+func (sim *simulator) sanitizeCall(call *TransactionArgs, header *types.Header, gasUsed uint64) error {
+	if call.Nonce == nil {
+		nonce := sim.state.GetNonce(call.from())
+		call.Nonce = (*hexutil.Uint64)(&nonce)
+	}
+	if !sim.unlimited && sim.budget == 0 {
+		return &clientLimitExceededError{message: fmt.Sprintf("block %d: RPC gas cap %d exhausted", header.Number, sim.b.RPCGasCap())}
+	}
+	if call.Gas == nil {
+		gas := header.GasLimit - gasUsed
+		if !sim.unlimited && sim.budget < gas {
+			gas = sim.budget
+		}
+		call.Gas = (*hexutil.Uint64)(&gas)
+	} else if !sim.unlimited && sim.budget < uint64(*call.Gas) {
+		log.Debug("Caller gas above allowance, capping", "requested", uint64(*call.Gas), "cap", sim.budget)
+		gas := sim.budget
+		call.Gas = (*hexutil.Uint64)(&gas)
+	}
+	if gasUsed+uint64(*call.Gas) > header.GasLimit {
+		return &blockGasLimitReachedError{message: fmt.Sprintf("block %d gas limit reached: %d >= %d", header.Number, gasUsed, header.GasLimit)}
+	}
+	if call.ChainID == nil {
+		call.ChainID = (*hexutil.Big)(sim.config.ChainID)
+	}
+	if call.Value == nil {
+		call.Value = new(hexutil.Big)
+	}
+	if call.BlobHashes != nil {
+		if call.To == nil {
+			return &invalidParamsError{message: core.ErrBlobTxCreate.Error()}
+		}
+		if call.BlobFeeCap == nil {
+			call.BlobFeeCap = new(hexutil.Big)
+		}
+	}
+	if call.GasPrice != nil && (call.MaxFeePerGas != nil || call.MaxPriorityFeePerGas != nil) {
+		return &invalidParamsError{message: "both gasPrice and (maxFeePerGas or maxPriorityFeePerGas) specified"}
+	}
+	switch {
+	case call.GasPrice != nil:
+	case header.BaseFee == nil:
+		call.GasPrice = new(hexutil.Big)
+	default:
+		if call.MaxPriorityFeePerGas == nil {
+			call.MaxPriorityFeePerGas = new(hexutil.Big)
+		}
+		if call.MaxFeePerGas == nil {
+			call.MaxFeePerGas = (*hexutil.Big)(new(big.Int).Add(header.BaseFee, call.MaxPriorityFeePerGas.ToInt()))
+		}
+	}
+	return nil
+}
+
+// simChainContext resolves the headers of the simulated blocks, on top of the
+// headers of the chain.
+type simChainContext struct {
+	*ChainContext
+	headers []*types.Header
+}
+
+func (c *simChainContext) GetHeader(hash common.Hash, number uint64) *types.Header {
+	for _, header := range c.headers {
+		if header.Number.Uint64() == number && header.Hash() == hash {
+			return header
+		}
+	}
+	return c.ChainContext.GetHeader(hash, number)
+}
+
+// simTransfer is a traced ETH transfer, along with the number of logs emitted
+// by the transaction before it.
+type simTransfer struct {
+	pos int
+	log *types.Log
+}
+
+// simTracer is an EVM logger recording the ETH transfers of a transaction as
+// synthetic logs. The transfers of reverted calls are discarded.
+type simTracer struct {
+	state  *state.StateDB
+	txHash common.Hash
+	frames [][]simTransfer
+}
+
+func newSimTracer(state *state.StateDB) *simTracer {
+	return &simTracer{state: state}
+}
+
+// reset prepares the tracer for a new transaction.
+func (t *simTracer) reset(txHash common.Hash) {
+	t.txHash, t.frames = txHash, nil
+}
+
+// logs merges the transfers with the logs of the transaction, in order.
+func (t *simTracer) logs(logs []*types.Log) []*types.Log {
+	if len(t.frames) == 0 || len(t.frames[0]) == 0 {
+		return logs
+	}
+	merged := make([]*types.Log, 0, len(logs)+len(t.frames[0]))
+	next := 0
+	for _, transfer := range t.frames[0] {
+		for ; next < transfer.pos && next < len(logs); next++ {
+			merged = append(merged, logs[next])
+		}
+		merged = append(merged, transfer.log)
+	}
+	return append(merged, logs[next:]...)
+}
+
+func (t *simTracer) transfer(from, to common.Address, value *big.Int) {
+	if value == nil || value.Sign() == 0 {
+		return
+	}
+	frame := len(t.frames) - 1
+	t.frames[frame] = append(t.frames[frame], simTransfer{
+		pos: len(t.state.GetLogs(t.txHash, 0, common.Hash{})),
+		log: &types.Log{
+			Address: transferAddress,
+			Topics:  []common.Hash{transferTopic, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+			Data:    common.BigToHash(value).Bytes(),
+		},
+	})
+}
+
+func (t *simTracer) CaptureTxStart(gasLimit uint64)         {}
+func (t *simTracer) CaptureTxEnd(restGas uint64)            {}
+func (t *simTracer) CaptureSystemTxEnd(intrinsicGas uint64) {}
+func (t *simTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+}
+func (t *simTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+func (t *simTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.frames = [][]simTransfer{nil}
+	t.transfer(from, to, value)
+}
+
+func (t *simTracer) CaptureEnd(output []byte, gasUsed uint64, err error) {
+	if err != nil {
+		t.frames[0] = nil
+	}
+}
+
+func (t *simTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	t.frames = append(t.frames, nil)
+	if typ != vm.DELEGATECALL && typ != vm.STATICCALL {
+		t.transfer(from, to, value)
+	}
+}
+
+func (t *simTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	last := len(t.frames) - 1
+	if err == nil {
+		t.frames[last-1] = append(t.frames[last-1], t.frames[last]...)
+	}
+	t.frames = t.frames[:last]
+}
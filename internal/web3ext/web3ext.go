@@ -29,6 +29,10 @@ var Modules = map[string]string{
 	"rpc":      RpcJs,
 	"txpool":   TxpoolJs,
 	"dev":      DevJs,
+	"mev":      MevJs,
+	"monitor":  MonitorJs,
+	"vote":     VoteJs,
+	"trace":    TraceJs,
 }
 
 const CliqueJs = `
@@ -156,6 +160,40 @@ web3._extend({
 			call: 'admin_sleepBlocks',
 			params: 2
 		}),
+		new web3._extend.Method({
+			name: 'addToBlacklist',
+			call: 'admin_addToBlacklist',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'removeFromBlacklist',
+			call: 'admin_removeFromBlacklist',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'reloadBlacklist',
+			call: 'admin_reloadBlacklist'
+		}),
+		new web3._extend.Method({
+			name: 'setTxPoolLane',
+			call: 'admin_setTxPoolLane',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'removeTxPoolLane',
+			call: 'admin_removeTxPoolLane',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'exportTxPool',
+			call: 'admin_exportTxPool',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'importTxPool',
+			call: 'admin_importTxPool',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'startHTTP',
 			call: 'admin_startHTTP',
@@ -194,6 +232,14 @@ web3._extend({
 			name: 'nodeInfo',
 			getter: 'admin_nodeInfo'
 		}),
+		new web3._extend.Property({
+			name: 'blacklist',
+			getter: 'admin_blacklist'
+		}),
+		new web3._extend.Property({
+			name: 'txPoolLanes',
+			getter: 'admin_txPoolLanes'
+		}),
 		new web3._extend.Property({
 			name: 'peers',
 			getter: 'admin_peers'
@@ -852,3 +898,108 @@ web3._extend({
 	],
 });
 `
+
+const MonitorJs = `
+web3._extend({
+	property: 'monitor',
+	methods: [],
+	properties:
+	[
+		new web3._extend.Property({
+			name: 'pendingEvidences',
+			getter: 'monitor_pendingEvidences'
+		}),
+		new web3._extend.Property({
+			name: 'submittedEvidences',
+			getter: 'monitor_submittedEvidences'
+		}),
+	]
+});
+`
+
+const VoteJs = `
+web3._extend({
+	property: 'vote',
+	methods: [
+		new web3._extend.Method({
+			name: 'getVotes',
+			call: 'vote_getVotes',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getLocalVoteHistory',
+			call: 'vote_getLocalVoteHistory',
+			params: 1
+		}),
+	],
+	properties: [
+		new web3._extend.Property({
+			name: 'poolStatus',
+			getter: 'vote_getPoolStatus'
+		}),
+	]
+});
+`
+
+const TraceJs = `
+web3._extend({
+	property: 'trace',
+	methods: [
+		new web3._extend.Method({
+			name: 'block',
+			call: 'trace_block',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'transaction',
+			call: 'trace_transaction',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'filter',
+			call: 'trace_filter',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'replayBlockTransactions',
+			call: 'trace_replayBlockTransactions',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, null]
+		}),
+	]
+});
+`
+
+const MevJs = `
+web3._extend({
+	property: 'mev',
+	methods: [
+		new web3._extend.Method({
+			name: 'hasBuilder',
+			call: 'mev_hasBuilder',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'bestBidGasFee',
+			call: 'mev_bestBidGasFee',
+			params: 1
+		}),
+	],
+	properties: [
+		new web3._extend.Property({
+			name: 'params',
+			getter: 'mev_params'
+		}),
+		new web3._extend.Property({
+			name: 'running',
+			getter: 'mev_running'
+		}),
+		new web3._extend.Property({
+			name: 'builderStats',
+			getter: 'mev_builderStats'
+		}),
+	]
+});
+`
@@ -0,0 +1,233 @@
+package miner
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// maxFailureReasonsPerBuilder caps the distinct failure reasons kept per builder
+	maxFailureReasonsPerBuilder = 32
+
+	// rpcErrorWeight is how many simulation failures a single rpc error counts for in the score
+	rpcErrorWeight = 0.5
+)
+
+var (
+	errBetterBidArrived = errors.New("simulation abort due to better bid arrived")
+	errMinerExit        = errors.New("miner exit")
+)
+
+// builderRecord keeps the in-memory statistics of a single builder.
+type builderRecord struct {
+	bidsReceived   uint64
+	bidsTooLate    uint64
+	simSuccess     uint64
+	simFailure     uint64
+	bidsWon        uint64
+	rpcErrors      uint64
+	failureReasons map[string]uint64
+
+	arrivalLeadSum time.Duration
+	arrivalCount   uint64
+
+	consecutiveFailures uint64
+	suspendedUntil      time.Time
+}
+
+// builderTracker scores registered builders by their bid success rate, simulation failures,
+// bid arrival time and rpc errors, and temporarily suspends misbehaving builders.
+// Failed simulations, late bids and rpc errors all count as consecutive failures,
+// only a successful simulation resets them.
+type builderTracker struct {
+	suspendFailures uint64        // consecutive failures before suspension, 0 disables suspension
+	suspendDuration time.Duration // how long a builder stays suspended
+
+	mu      sync.RWMutex
+	records map[common.Address]*builderRecord
+}
+
+func newBuilderTracker(suspendFailures uint64, suspendDuration time.Duration) *builderTracker {
+	return &builderTracker{
+		suspendFailures: suspendFailures,
+		suspendDuration: suspendDuration,
+		records:         make(map[common.Address]*builderRecord),
+	}
+}
+
+// add starts tracking the builder, once it's registered. The statistics of a
+// builder which is already tracked are kept.
+func (t *builderTracker) add(builder common.Address) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.records[builder]; !ok {
+		t.records[builder] = &builderRecord{failureReasons: make(map[string]uint64)}
+	}
+}
+
+// recordArrival records a bid arrival, lead is the time left before bidBetterBefore.
+func (t *builderTracker) recordArrival(builder common.Address, lead time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	r, ok := t.records[builder]
+	if !ok {
+		return
+	}
+	r.bidsReceived++
+	r.arrivalLeadSum += lead
+	r.arrivalCount++
+	if lead <= 0 {
+		r.bidsTooLate++
+		t.recordFailure(builder, r, fmt.Errorf("bid too late by %v", -lead))
+	}
+}
+
+// recordSimSuccess records a fully simulated bid and whether it became the best bid.
+func (t *builderTracker) recordSimSuccess(builder common.Address, won bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	r, ok := t.records[builder]
+	if !ok {
+		return
+	}
+	r.simSuccess++
+	r.consecutiveFailures = 0
+	if won {
+		r.bidsWon++
+	}
+}
+
+// recordSimFailure records a failed simulation. Aborts which are not caused by
+// the builder, e.g. a better bid arrived, are ignored.
+func (t *builderTracker) recordSimFailure(builder common.Address, err error) {
+	if errors.Is(err, errBetterBidArrived) || errors.Is(err, errMinerExit) {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	r, ok := t.records[builder]
+	if !ok {
+		return
+	}
+	r.simFailure++
+
+	reason := simFailureReason(err)
+	if _, ok := r.failureReasons[reason]; ok || len(r.failureReasons) < maxFailureReasonsPerBuilder {
+		r.failureReasons[reason]++
+	}
+	t.recordFailure(builder, r, err)
+}
+
+// recordRPCError records a failed dial or rpc call towards the builder.
+func (t *builderTracker) recordRPCError(builder common.Address, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	r, ok := t.records[builder]
+	if !ok {
+		return
+	}
+	r.rpcErrors++
+	t.recordFailure(builder, r, err)
+}
+
+// recordFailure counts a failure towards the suspension of the builder, and
+// suspends it once it failed too many times in a row. Caller must hold the lock.
+func (t *builderTracker) recordFailure(builder common.Address, r *builderRecord, err error) {
+	r.consecutiveFailures++
+	if t.suspendFailures > 0 && r.consecutiveFailures >= t.suspendFailures {
+		r.consecutiveFailures = 0
+		r.suspendedUntil = time.Now().Add(t.suspendDuration)
+		log.Warn("BidSimulator: builder suspended", "builder", builder,
+			"failures", t.suspendFailures, "until", r.suspendedUntil, "lastErr", err)
+	}
+}
+
+// suspended returns the time until which the builder is suspended, if it is.
+func (t *builderTracker) suspended(builder common.Address) (time.Time, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	r, ok := t.records[builder]
+	if !ok || !time.Now().Before(r.suspendedUntil) {
+		return time.Time{}, false
+	}
+	return r.suspendedUntil, true
+}
+
+// remove drops the statistics of the builder.
+func (t *builderTracker) remove(builder common.Address) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.records, builder)
+}
+
+// stats returns the statistics of all known builders, sorted by score descending.
+func (t *builderTracker) stats() []*types.BuilderStats {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	now := time.Now()
+	stats := make([]*types.BuilderStats, 0, len(t.records))
+	for builder, r := range t.records {
+		s := &types.BuilderStats{
+			Builder:        builder,
+			BidsReceived:   r.bidsReceived,
+			BidsTooLate:    r.bidsTooLate,
+			SimSuccess:     r.simSuccess,
+			SimFailure:     r.simFailure,
+			BidsWon:        r.bidsWon,
+			RPCErrors:      r.rpcErrors,
+			FailureReasons: make(map[string]uint64, len(r.failureReasons)),
+			Score:          1,
+			Suspended:      now.Before(r.suspendedUntil),
+		}
+		for k, v := range r.failureReasons {
+			s.FailureReasons[k] = v
+		}
+		if s.Suspended {
+			s.SuspendedUntil = r.suspendedUntil
+		}
+		if r.arrivalCount > 0 {
+			s.AvgArrivalLead = r.arrivalLeadSum / time.Duration(r.arrivalCount)
+		}
+		if simulated := r.simSuccess + r.simFailure; simulated > 0 {
+			s.SuccessRate = float64(r.simSuccess) / float64(simulated)
+		}
+		if total := float64(r.simSuccess) + float64(r.simFailure) + rpcErrorWeight*float64(r.rpcErrors) + float64(r.bidsTooLate); total > 0 {
+			s.Score = float64(r.simSuccess) / total
+		}
+		stats = append(stats, s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Score != stats[j].Score {
+			return stats[i].Score > stats[j].Score
+		}
+		return stats[i].Builder.Cmp(stats[j].Builder) < 0
+	})
+	return stats
+}
+
+// simFailureReason reduces a simulation error to a low-cardinality reason,
+// e.g. "invalid tx in bid, nonce too low" becomes "invalid tx in bid".
+func simFailureReason(err error) string {
+	msg := err.Error()
+	if i := strings.IndexAny(msg, ",:"); i > 0 {
+		msg = msg[:i]
+	}
+	return strings.TrimSpace(msg)
+}
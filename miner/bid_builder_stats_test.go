@@ -0,0 +1,120 @@
+package miner
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestBuilderTrackerStats(t *testing.T) {
+	var (
+		tracker = newBuilderTracker(0, time.Minute)
+		good    = common.HexToAddress("0x01")
+		bad     = common.HexToAddress("0x02")
+		unknown = common.HexToAddress("0x03")
+	)
+	tracker.add(good)
+	tracker.add(bad)
+
+	tracker.recordArrival(good, 300*time.Millisecond)
+	tracker.recordArrival(good, 100*time.Millisecond)
+	tracker.recordSimSuccess(good, true)
+	tracker.recordSimSuccess(good, false)
+
+	tracker.recordArrival(bad, -10*time.Millisecond)
+	tracker.recordSimFailure(bad, fmt.Errorf("invalid tx in bid, %v", errors.New("nonce too low")))
+	tracker.recordSimFailure(bad, fmt.Errorf("invalid tx in bid, %v", errors.New("insufficient funds")))
+	tracker.recordSimSuccess(bad, false)
+	tracker.recordRPCError(bad, errors.New("connection refused"))
+
+	// aborts not caused by the builder are not counted
+	tracker.recordSimFailure(good, errBetterBidArrived)
+	tracker.recordSimFailure(good, errMinerExit)
+
+	// builders which are not registered are not tracked
+	tracker.recordArrival(unknown, time.Millisecond)
+	tracker.recordRPCError(unknown, errors.New("connection refused"))
+
+	stats := tracker.stats()
+	if len(stats) != 2 {
+		t.Fatalf("stats length mismatch: have %d, want 2", len(stats))
+	}
+	if stats[0].Builder != good || stats[1].Builder != bad {
+		t.Fatalf("stats not sorted by score: %v, %v", stats[0].Builder, stats[1].Builder)
+	}
+	g := stats[0]
+	if g.BidsReceived != 2 || g.SimSuccess != 2 || g.SimFailure != 0 || g.BidsWon != 1 {
+		t.Fatalf("good builder stats mismatch: %+v", g)
+	}
+	if g.AvgArrivalLead != 200*time.Millisecond {
+		t.Fatalf("arrival lead mismatch: have %v, want %v", g.AvgArrivalLead, 200*time.Millisecond)
+	}
+	if g.SuccessRate != 1 || g.Score != 1 {
+		t.Fatalf("good builder rate mismatch: rate %v score %v", g.SuccessRate, g.Score)
+	}
+	b := stats[1]
+	if b.BidsTooLate != 1 || b.SimFailure != 2 || b.RPCErrors != 1 {
+		t.Fatalf("bad builder stats mismatch: %+v", b)
+	}
+	if b.FailureReasons["invalid tx in bid"] != 2 || len(b.FailureReasons) != 1 {
+		t.Fatalf("failure reasons mismatch: %v", b.FailureReasons)
+	}
+	if b.Score >= g.Score {
+		t.Fatalf("bad builder scored too high: %v", b.Score)
+	}
+}
+
+func TestBuilderTrackerSuspension(t *testing.T) {
+	var (
+		tracker = newBuilderTracker(3, time.Hour)
+		builder = common.HexToAddress("0x01")
+		simErr  = errors.New("reward does not achieve the expectation")
+	)
+	tracker.add(builder)
+	tracker.recordSimFailure(builder, simErr)
+	tracker.recordSimFailure(builder, simErr)
+	tracker.recordSimSuccess(builder, false)
+	tracker.recordSimFailure(builder, simErr)
+	tracker.recordSimFailure(builder, simErr)
+	if _, suspended := tracker.suspended(builder); suspended {
+		t.Fatalf("builder suspended without enough consecutive failures")
+	}
+	tracker.recordSimFailure(builder, simErr)
+	until, suspended := tracker.suspended(builder)
+	if !suspended || time.Until(until) <= 0 {
+		t.Fatalf("builder not suspended after consecutive failures")
+	}
+	if stats := tracker.stats(); !stats[0].Suspended {
+		t.Fatalf("suspension not reported in stats")
+	}
+	tracker.remove(builder)
+	if _, suspended := tracker.suspended(builder); suspended {
+		t.Fatalf("removed builder still suspended")
+	}
+	tracker.recordSimFailure(builder, simErr)
+	if stats := tracker.stats(); len(stats) != 0 {
+		t.Fatalf("removed builder still tracked: %v", stats)
+	}
+}
+
+func TestBuilderTrackerSuspensionOnLatency(t *testing.T) {
+	var (
+		tracker = newBuilderTracker(3, time.Hour)
+		builder = common.HexToAddress("0x01")
+	)
+	tracker.add(builder)
+	tracker.recordArrival(builder, -time.Millisecond)
+	tracker.recordRPCError(builder, errors.New("connection refused"))
+	if _, suspended := tracker.suspended(builder); suspended {
+		t.Fatalf("builder suspended without enough consecutive failures")
+	}
+	// timely bids don't reset the failures, only successful simulations do
+	tracker.recordArrival(builder, time.Millisecond)
+	tracker.recordArrival(builder, -time.Millisecond)
+	if _, suspended := tracker.suspended(builder); !suspended {
+		t.Fatalf("builder not suspended after late bids and rpc errors")
+	}
+}
@@ -0,0 +1,159 @@
+package miner
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// newBundleTestRuntime builds a bid runtime on top of a fresh chain, in which
+// the contract at 0xee always reverts.
+func newBundleTestRuntime(t *testing.T, raw *types.RawBid) (*BidRuntime, *core.BlockChain) {
+	var (
+		reverter = common.Address{0xee}
+		gspec    = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: types.GenesisAlloc{
+				testBankAddress: {Balance: testBankFunds},
+				reverter:        {Code: []byte{byte(vm.PUSH1), 0x0, byte(vm.DUP1), byte(vm.REVERT)}},
+			},
+		}
+	)
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	t.Cleanup(chain.Stop)
+
+	parent := chain.CurrentBlock()
+	statedb, err := chain.StateAt(parent.Root)
+	if err != nil {
+		t.Fatalf("failed to get state: %v", err)
+	}
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     big.NewInt(1),
+		GasLimit:   parent.GasLimit,
+		Time:       parent.Time + 1,
+		Difficulty: big.NewInt(1),
+		BaseFee:    big.NewInt(params.InitialBaseFee),
+	}
+	signer := types.MakeSigner(params.TestChainConfig, header.Number, header.Time)
+	bid, err := (&types.BidArgs{RawBid: raw}).ToBid(common.Address{0x01}, signer)
+	if err != nil {
+		t.Fatalf("invalid bid: %v", err)
+	}
+	bidRuntime, err := newBidRuntime(bid, 0)
+	if err != nil {
+		t.Fatalf("failed to create bid runtime: %v", err)
+	}
+	bidRuntime.env = &environment{
+		signer:   signer,
+		state:    statedb,
+		coinbase: header.Coinbase,
+		header:   header,
+		gasPool:  new(core.GasPool).AddGas(header.GasLimit),
+	}
+	return bidRuntime, chain
+}
+
+func bundleTestTx(t *testing.T, nonce uint64, to common.Address) (*types.Transaction, hexutil.Bytes) {
+	tx := types.MustSignNewTx(testBankKey, types.LatestSigner(params.TestChainConfig), &types.LegacyTx{
+		Nonce:    nonce,
+		To:       &to,
+		Value:    big.NewInt(1000),
+		Gas:      50000,
+		GasPrice: big.NewInt(2 * params.InitialBaseFee),
+	})
+	blob, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to encode tx: %v", err)
+	}
+	return tx, blob
+}
+
+func TestBidBundleRevert(t *testing.T) {
+	reverter := common.Address{0xee}
+	var (
+		// the reverted tx of the first bundle is dropping, only itself is excluded
+		keep, keepBlob = bundleTestTx(t, 0, testUserAddress)
+		drop, dropBlob = bundleTestTx(t, 1, reverter)
+		// the reverted tx of the second bundle excludes the whole bundle
+		undo, undoBlob     = bundleTestTx(t, 1, testUserAddress)
+		revert, revertBlob = bundleTestTx(t, 2, reverter)
+	)
+	bidRuntime, chain := newBundleTestRuntime(t, &types.RawBid{
+		BlockNumber: 1,
+		Txs:         []hexutil.Bytes{keepBlob, dropBlob, undoBlob, revertBlob},
+		GasFee:      big.NewInt(0),
+		Bundles: []types.BidBundle{
+			{Txs: []common.Hash{keep.Hash(), drop.Hash()}, DroppingTxs: []common.Hash{drop.Hash()}},
+			{Txs: []common.Hash{undo.Hash(), revert.Hash()}},
+		},
+	})
+	if err := bidRuntime.commitBidTxs(chain, chain.Config(), 0, 4, nil); err != nil {
+		t.Fatalf("failed to commit bid txs: %v", err)
+	}
+	env := bidRuntime.env
+	if len(env.txs) != 1 || env.txs[0].Hash() != keep.Hash() || env.tcount != 1 || len(env.receipts) != 1 {
+		t.Fatalf("committed txs mismatch: have %d txs, tcount %d", len(env.txs), env.tcount)
+	}
+	if env.header.GasUsed != env.receipts[0].GasUsed {
+		t.Errorf("gas used mismatch: have %d, want %d", env.header.GasUsed, env.receipts[0].GasUsed)
+	}
+	if have := env.gasPool.Gas(); have != env.header.GasLimit-env.header.GasUsed {
+		t.Errorf("gas pool mismatch: have %d, want %d", have, env.header.GasLimit-env.header.GasUsed)
+	}
+	if nonce := env.state.GetNonce(testBankAddress); nonce != 1 {
+		t.Errorf("sender nonce mismatch: have %d, want 1", nonce)
+	}
+	if balance := env.state.GetBalance(testUserAddress); balance.Uint64() != 1000 {
+		t.Errorf("recipient balance mismatch: have %d, want 1000", balance)
+	}
+}
+
+func TestBidBundleUnRevertible(t *testing.T) {
+	var (
+		tx, blob          = bundleTestTx(t, 0, testUserAddress)
+		revert, rBlob     = bundleTestTx(t, 1, common.Address{0xee})
+		bidRuntime, chain = newBundleTestRuntime(t, &types.RawBid{
+			BlockNumber:  1,
+			Txs:          []hexutil.Bytes{blob, rBlob},
+			UnRevertible: []common.Hash{revert.Hash()},
+			GasFee:       big.NewInt(0),
+			Bundles:      []types.BidBundle{{Txs: []common.Hash{tx.Hash(), revert.Hash()}}},
+		})
+	)
+	// a reverted unRevertible tx rejects the whole bid instead of the bundle
+	if err := bidRuntime.commitBidTxs(chain, chain.Config(), 0, 2, nil); err == nil {
+		t.Fatal("bid with a reverted unRevertible tx accepted")
+	}
+}
+
+func TestBidBundleOverlappingPayBidTx(t *testing.T) {
+	var (
+		tx, blob          = bundleTestTx(t, 0, testUserAddress)
+		pay, payBlob      = bundleTestTx(t, 1, testUserAddress)
+		bidRuntime, chain = newBundleTestRuntime(t, &types.RawBid{
+			BlockNumber: 1,
+			Txs:         []hexutil.Bytes{blob, payBlob},
+			GasFee:      big.NewInt(0),
+			Bundles:     []types.BidBundle{{Txs: []common.Hash{tx.Hash(), pay.Hash()}}},
+		})
+	)
+	// without a separate payBidTx the last tx pays the bid, a bundle including it rejects the bid
+	if err := bidRuntime.commitBidTxs(chain, chain.Config(), 0, 1, nil); err == nil {
+		t.Fatal("bid with a bundle overlapping the payBidTx accepted")
+	}
+	if bidRuntime.env.tcount != 0 {
+		t.Fatalf("txs committed from a rejected bid: %d", bidRuntime.env.tcount)
+	}
+}
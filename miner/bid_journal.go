@@ -0,0 +1,378 @@
+package miner
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Kinds of bid journal entries.
+const (
+	BidJournalArrival    = "arrival"    // a bid arrived from a builder
+	BidJournalSimulation = "simulation" // a bid was simulated
+	BidJournalChoice     = "choice"     // the best bid was compared with the local work for sealing
+)
+
+// BidJournalEntry is a single line of the bid journal.
+type BidJournalEntry struct {
+	Kind        string         `json:"kind"`
+	Time        time.Time      `json:"time"`
+	BlockNumber uint64         `json:"blockNumber"`
+	ParentHash  common.Hash    `json:"parentHash"`
+	Builder     common.Address `json:"builder"`
+	BidHash     common.Hash    `json:"bidHash"`
+
+	// arrival
+	Bid         *types.BidArgs `json:"bid,omitempty"`
+	ArrivalLead time.Duration  `json:"arrivalLead,omitempty"` // time left before bidBetterBefore
+
+	// simulation
+	Header                *types.Header `json:"header,omitempty"` // the header the bid was simulated on
+	TxCount               int           `json:"txCount,omitempty"`
+	PackedBlockReward     *big.Int      `json:"packedBlockReward,omitempty"`
+	PackedValidatorReward *big.Int      `json:"packedValidatorReward,omitempty"`
+	Best                  bool          `json:"best,omitempty"` // the bid became the best bid
+	Duration              time.Duration `json:"duration,omitempty"`
+
+	// choice
+	LocalBlockReward *big.Int `json:"localBlockReward,omitempty"`
+	BidChosen        bool     `json:"bidChosen,omitempty"`
+
+	Error string `json:"error,omitempty"` // why the bid was rejected or lost
+}
+
+// bidJournalQueue is the number of entries buffered for the background writer,
+// entries are dropped when the writer falls behind.
+const bidJournalQueue = 4096
+
+// bidJournalWindow is the distance from the local chain head beyond which the
+// entries are dropped, not to create journal files for arbitrary block numbers.
+const bidJournalWindow = 16
+
+// bidJournal is an append-only JSONL journal of bids, rotated by block:
+// the entries of each block are written to <dir>/<blockNumber>.jsonl.
+// Entries are written by a background goroutine so that journaling never
+// blocks bid submission or sealing, and the files of blocks older than the
+// retention before the local chain head are deleted on rotation.
+type bidJournal struct {
+	dir       string
+	retention uint64        // number of recent blocks whose journal is kept, 0 keeps all
+	head      func() uint64 // number of the local chain head
+
+	entries chan *BidJournalEntry
+	closeCh chan struct{}
+	doneCh  chan struct{}
+
+	// Fields below are only accessed by the writer goroutine
+	number uint64
+	file   *os.File
+	writer *bufio.Writer
+}
+
+// newBidJournal creates a bid journal under dir keeping the files of the last
+// retention blocks before head. An empty dir disables journaling.
+func newBidJournal(dir string, retention uint64, head func() uint64) (*bidJournal, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	j := &bidJournal{
+		dir:       dir,
+		retention: retention,
+		head:      head,
+		entries:   make(chan *BidJournalEntry, bidJournalQueue),
+		closeCh:   make(chan struct{}),
+		doneCh:    make(chan struct{}),
+	}
+	go j.loop()
+	return j, nil
+}
+
+// BidJournalPath returns the journal file of the given block.
+func BidJournalPath(dir string, number uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("%d.jsonl", number))
+}
+
+// write queues an entry for the journal file of its block. It's a noop on a nil
+// journal and it never blocks: the entry is dropped if the queue is full, or if
+// its block is too far from the local chain head.
+func (j *bidJournal) write(entry *BidJournalEntry) {
+	if j == nil {
+		return
+	}
+	if head := j.head(); entry.BlockNumber > head+bidJournalWindow || entry.BlockNumber+bidJournalWindow < head {
+		log.Debug("BidJournal: dropping entry out of window", "kind", entry.Kind, "block", entry.BlockNumber, "head", head)
+		return
+	}
+	entry.Time = time.Now()
+
+	select {
+	case j.entries <- entry:
+	default:
+		log.Warn("BidJournal: queue full, dropping entry", "kind", entry.Kind, "block", entry.BlockNumber)
+	}
+}
+
+// loop writes the queued entries until the journal is closed, flushing the
+// buffered writer whenever the queue is drained.
+func (j *bidJournal) loop() {
+	defer close(j.doneCh)
+
+	for {
+		select {
+		case entry := <-j.entries:
+			j.append(entry)
+			if len(j.entries) == 0 {
+				j.flush()
+			}
+		case <-j.closeCh:
+			for {
+				select {
+				case entry := <-j.entries:
+					j.append(entry)
+				default:
+					j.closeFile()
+					return
+				}
+			}
+		}
+	}
+}
+
+// append encodes an entry into the journal file of its block, rotating the
+// file if the block changed.
+func (j *bidJournal) append(entry *BidJournalEntry) {
+	blob, err := json.Marshal(entry)
+	if err != nil {
+		log.Warn("BidJournal: failed to encode entry", "kind", entry.Kind, "err", err)
+		return
+	}
+	if j.file == nil || j.number != entry.BlockNumber {
+		j.closeFile()
+
+		file, err := os.OpenFile(BidJournalPath(j.dir, entry.BlockNumber), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+		if err != nil {
+			log.Warn("BidJournal: failed to open journal", "block", entry.BlockNumber, "err", err)
+			return
+		}
+		j.file, j.writer, j.number = file, bufio.NewWriter(file), entry.BlockNumber
+		j.prune(j.head())
+	}
+	if _, err := j.writer.Write(append(blob, '\n')); err != nil {
+		log.Warn("BidJournal: failed to write entry", "block", entry.BlockNumber, "err", err)
+	}
+}
+
+func (j *bidJournal) flush() {
+	if j.writer == nil {
+		return
+	}
+	if err := j.writer.Flush(); err != nil {
+		log.Warn("BidJournal: failed to flush journal", "block", j.number, "err", err)
+	}
+}
+
+func (j *bidJournal) closeFile() {
+	if j.file == nil {
+		return
+	}
+	j.flush()
+	j.file.Close()
+	j.file, j.writer = nil, nil
+}
+
+// prune deletes the journal files of the blocks which fell out of the
+// retention window ending at head, except the file being written.
+func (j *bidJournal) prune(head uint64) {
+	if j.retention == 0 || head < j.retention {
+		return
+	}
+	files, err := os.ReadDir(j.dir)
+	if err != nil {
+		log.Warn("BidJournal: failed to list journal files", "err", err)
+		return
+	}
+	cutoff := head - j.retention
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || filepath.Ext(name) != ".jsonl" {
+			continue
+		}
+		number, err := strconv.ParseUint(strings.TrimSuffix(name, ".jsonl"), 10, 64)
+		if err != nil || number > cutoff || number == j.number {
+			continue
+		}
+		if err := os.Remove(filepath.Join(j.dir, name)); err != nil {
+			log.Warn("BidJournal: failed to delete journal", "block", number, "err", err)
+		}
+	}
+}
+
+// close writes the queued entries and stops the writer.
+func (j *bidJournal) close() {
+	if j == nil {
+		return
+	}
+	close(j.closeCh)
+	<-j.doneCh
+}
+
+// ReadBidJournal reads all the journaled entries of the given block.
+func ReadBidJournal(dir string, number uint64) ([]*BidJournalEntry, error) {
+	file, err := os.Open(BidJournalPath(dir, number))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var (
+		entries []*BidJournalEntry
+		scanner = bufio.NewScanner(file)
+	)
+	scanner.Buffer(make([]byte, 0, 1024*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		entry := new(BidJournalEntry)
+		if err := json.Unmarshal(scanner.Bytes(), entry); err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// BidReplayResult is the outcome of re-simulating a journaled bid.
+type BidReplayResult struct {
+	Builder  common.Address
+	BidHash  common.Hash
+	Recorded *BidJournalEntry // the last journaled simulation of the bid, nil if it was never simulated
+	Rejected string           // why the bid was rejected on arrival, empty if it was accepted
+
+	TxCount               int
+	GasUsed               uint64
+	PackedBlockReward     *big.Int
+	PackedValidatorReward *big.Int
+	Err                   error
+}
+
+// ReplayBids re-simulates every bid which arrived in the journal entries of a block
+// on top of its parent state. The header recorded by the bid simulation is reused,
+// the canonical header of the block is used for bids which were never simulated.
+// Greedily merged mempool txs are not replayed, only the bid txs and the payBidTx.
+func ReplayBids(chain *core.BlockChain, validatorCommission uint64, entries []*BidJournalEntry) []*BidReplayResult {
+	simulated := make(map[common.Hash]*BidJournalEntry)
+	for _, entry := range entries {
+		if entry.Kind == BidJournalSimulation {
+			simulated[entry.BidHash] = entry
+		}
+	}
+	var (
+		results []*BidReplayResult
+		seen    = make(map[common.Hash]bool)
+	)
+	for _, entry := range entries {
+		if entry.Kind != BidJournalArrival || entry.Bid == nil || seen[entry.BidHash] {
+			continue
+		}
+		seen[entry.BidHash] = true
+
+		result := &BidReplayResult{Builder: entry.Builder, BidHash: entry.BidHash, Recorded: simulated[entry.BidHash], Rejected: entry.Error}
+		results = append(results, result)
+
+		var header *types.Header
+		if result.Recorded != nil && result.Recorded.Header != nil {
+			header = result.Recorded.Header
+		} else if canonical := chain.GetHeaderByNumber(entry.BlockNumber); canonical != nil && canonical.ParentHash == entry.ParentHash {
+			header = canonical
+		} else {
+			result.Err = errors.New("no header to replay the bid on")
+			continue
+		}
+		signer := types.MakeSigner(chain.Config(), header.Number, header.Time)
+		bid, err := entry.Bid.ToBid(entry.Builder, signer)
+		if err != nil {
+			result.Err = fmt.Errorf("invalid bid, %v", err)
+			continue
+		}
+		bidRuntime, err := replayBid(chain, header, bid, validatorCommission)
+		if bidRuntime != nil && bidRuntime.env != nil {
+			result.TxCount = bidRuntime.env.tcount
+			result.GasUsed = bidRuntime.env.header.GasUsed
+			result.PackedBlockReward = bidRuntime.packedBlockReward
+			result.PackedValidatorReward = bidRuntime.packedValidatorReward
+			bidRuntime.env.discard()
+		}
+		result.Err = err
+	}
+	return results
+}
+
+// replayBid executes the txs of the bid on a fresh environment built on header,
+// the same way as bidSimulator.simBid does.
+func replayBid(chain *core.BlockChain, header *types.Header, bid *types.Bid, validatorCommission uint64) (*BidRuntime, error) {
+	if len(bid.Txs) == 0 {
+		return nil, errors.New("empty bid")
+	}
+	parent := chain.GetHeaderByHash(header.ParentHash)
+	if parent == nil {
+		return nil, errors.New("missing parent")
+	}
+	statedb, err := chain.StateAt(parent.Root)
+	if err != nil {
+		return nil, err
+	}
+	header = types.CopyHeader(header)
+	header.GasUsed = 0
+	if header.BlobGasUsed != nil {
+		*header.BlobGasUsed = 0
+	}
+	env := &environment{
+		signer:   types.MakeSigner(chain.Config(), header.Number, header.Time),
+		state:    statedb,
+		coinbase: header.Coinbase,
+		header:   header,
+	}
+	preExecute(chain.Config(), chain, parent, header, statedb)
+
+	bidRuntime, err := newBidRuntime(bid, validatorCommission)
+	if err != nil {
+		return nil, err
+	}
+	bidRuntime.env = env
+
+	env.gasPool = new(core.GasPool).AddGas(header.GasLimit)
+	env.gasPool.SubGas(params.SystemTxsGas)
+	env.gasPool.SubGas(params.PayBidTxGasLimit)
+
+	payBidTx := bid.Txs[len(bid.Txs)-1]
+	if err := bidRuntime.commitBidTxs(chain, chain.Config(), 0, len(bid.Txs)-1, nil); err != nil {
+		return bidRuntime, err
+	}
+	bidRuntime.packReward(validatorCommission)
+	if !bidRuntime.validReward() {
+		return bidRuntime, errors.New("reward does not achieve the expectation")
+	}
+	env.gasPool.AddGas(params.PayBidTxGasLimit)
+	if err := bidRuntime.commitTransaction(chain, chain.Config(), payBidTx, true); err != nil {
+		return bidRuntime, fmt.Errorf("invalid tx in bid, %v", err)
+	}
+	bidRuntime.packReward(validatorCommission)
+	return bidRuntime, nil
+}
@@ -0,0 +1,140 @@
+package miner
+
+import (
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestBidJournalRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	journal, err := newBidJournal(dir, 0, func() uint64 { return 10 })
+	if err != nil {
+		t.Fatalf("failed to create journal: %v", err)
+	}
+	var (
+		builder = common.HexToAddress("0x01")
+		parent  = common.Hash{0x02}
+		args    = &types.BidArgs{
+			RawBid: &types.RawBid{
+				BlockNumber: 10,
+				ParentHash:  parent,
+				Txs:         []hexutil.Bytes{{0x01, 0x02}},
+				GasUsed:     21000,
+				GasFee:      big.NewInt(100),
+				BuilderFee:  big.NewInt(1),
+			},
+			Signature: hexutil.Bytes{0x03},
+		}
+		header = &types.Header{ParentHash: parent, Number: big.NewInt(10), Difficulty: big.NewInt(2), GasLimit: 100}
+	)
+	journal.write(&BidJournalEntry{Kind: BidJournalArrival, BlockNumber: 10, ParentHash: parent, Builder: builder, BidHash: args.RawBid.Hash(), Bid: args})
+	journal.write(&BidJournalEntry{Kind: BidJournalSimulation, BlockNumber: 10, ParentHash: parent, Builder: builder, BidHash: args.RawBid.Hash(),
+		Header: header, PackedBlockReward: big.NewInt(100), Best: true})
+	journal.write(&BidJournalEntry{Kind: BidJournalArrival, BlockNumber: 11, Builder: builder})
+	journal.write(&BidJournalEntry{Kind: BidJournalChoice, BlockNumber: 10, ParentHash: parent, BidHash: args.RawBid.Hash(), BidChosen: true})
+	journal.close()
+
+	entries, err := ReadBidJournal(dir, 10)
+	if err != nil {
+		t.Fatalf("failed to read journal: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("entry count mismatch: have %d, want 3", len(entries))
+	}
+	if entries[0].Kind != BidJournalArrival || entries[0].Bid.RawBid.Hash() != args.RawBid.Hash() {
+		t.Fatalf("arrival entry mismatch: %+v", entries[0])
+	}
+	if entries[1].Header.Hash() != header.Hash() || entries[1].PackedBlockReward.Cmp(big.NewInt(100)) != 0 || !entries[1].Best {
+		t.Fatalf("simulation entry mismatch: %+v", entries[1])
+	}
+	if entries[2].Kind != BidJournalChoice || !entries[2].BidChosen {
+		t.Fatalf("choice entry mismatch: %+v", entries[2])
+	}
+	if _, err := os.Stat(BidJournalPath(dir, 11)); err != nil {
+		t.Fatalf("journal not rotated by block: %v", err)
+	}
+}
+
+func TestBidJournalRejection(t *testing.T) {
+	dir := t.TempDir()
+	journal, err := newBidJournal(dir, 0, func() uint64 { return 10 })
+	if err != nil {
+		t.Fatalf("failed to create journal: %v", err)
+	}
+	var (
+		b    = &bidSimulator{journal: journal}
+		args = &types.BidArgs{RawBid: &types.RawBid{BlockNumber: 10, ParentHash: common.Hash{0x02}}}
+	)
+	b.journalRejection(args, common.HexToAddress("0x01"), types.NewInvalidBidError("too many bids"))
+	b.journalRejection(&types.BidArgs{}, common.HexToAddress("0x01"), types.NewInvalidBidError("missing raw bid"))
+	journal.close()
+
+	entries, err := ReadBidJournal(dir, 10)
+	if err != nil {
+		t.Fatalf("failed to read journal: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Kind != BidJournalArrival || entries[0].BidHash != args.RawBid.Hash() {
+		t.Fatalf("rejection entry mismatch: %+v", entries)
+	}
+	if entries[0].Error != "too many bids" {
+		t.Fatalf("rejection reason mismatch: have %q", entries[0].Error)
+	}
+}
+
+func TestBidJournalDisabled(t *testing.T) {
+	journal, err := newBidJournal("", 0, nil)
+	if err != nil || journal != nil {
+		t.Fatalf("expected disabled journal, have %v, %v", journal, err)
+	}
+	// writing to and closing a disabled journal is a noop
+	journal.write(&BidJournalEntry{Kind: BidJournalArrival})
+	journal.close()
+}
+
+func TestBidJournalRetention(t *testing.T) {
+	dir := t.TempDir()
+	journal, err := newBidJournal(dir, 2, func() uint64 { return 5 })
+	if err != nil {
+		t.Fatalf("failed to create journal: %v", err)
+	}
+	for number := uint64(1); number <= 5; number++ {
+		journal.write(&BidJournalEntry{Kind: BidJournalArrival, BlockNumber: number})
+	}
+	journal.close()
+
+	for number := uint64(1); number <= 5; number++ {
+		_, err := os.Stat(BidJournalPath(dir, number))
+		if kept := number > 3; kept != (err == nil) {
+			t.Errorf("block %d: journal kept %v, want %v", number, err == nil, kept)
+		}
+	}
+}
+
+func TestBidJournalWindow(t *testing.T) {
+	dir := t.TempDir()
+	for _, number := range []uint64{10, 99} {
+		if err := os.WriteFile(BidJournalPath(dir, number), nil, 0644); err != nil {
+			t.Fatalf("failed to create journal file: %v", err)
+		}
+	}
+	journal, err := newBidJournal(dir, 2, func() uint64 { return 100 })
+	if err != nil {
+		t.Fatalf("failed to create journal: %v", err)
+	}
+	// entries far from the head are dropped and never prune the journal
+	journal.write(&BidJournalEntry{Kind: BidJournalArrival, BlockNumber: 1 << 40})
+	journal.write(&BidJournalEntry{Kind: BidJournalArrival, BlockNumber: 50})
+	journal.write(&BidJournalEntry{Kind: BidJournalArrival, BlockNumber: 101})
+	journal.close()
+
+	for number, kept := range map[uint64]bool{10: false, 50: false, 99: true, 101: true, 1 << 40: false} {
+		if _, err := os.Stat(BidJournalPath(dir, number)); kept != (err == nil) {
+			t.Errorf("block %d: journal kept %v, want %v", number, err == nil, kept)
+		}
+	}
+}
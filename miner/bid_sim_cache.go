@@ -0,0 +1,154 @@
+package miner
+
+import (
+	"sync"
+
+	mapset "github.com/deckarep/golang-set/v2"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	// maxSimPrefixPerParent is the max number of simulated prefixes cached per parent block
+	maxSimPrefixPerParent = 8
+)
+
+var (
+	bidSimPrefixHitMeter  = metrics.NewRegisteredMeter("bid/sim/prefix/hit", nil)
+	bidSimPrefixMissMeter = metrics.NewRegisteredMeter("bid/sim/prefix/miss", nil)
+	bidSimPrefixTxMeter   = metrics.NewRegisteredMeter("bid/sim/prefix/tx", nil)
+)
+
+// simPrefix is the environment right after the txs of a simulated bid were committed,
+// before the payBidTx and greedily merged txs.
+type simPrefix struct {
+	blockNumber uint64
+	txHashes    []common.Hash
+	env         *environment
+}
+
+// simPrefixCache keeps post-prefix environments of simulated bids, so that a later bid
+// for the same parent which extends a previous bid's tx list only executes the suffix.
+type simPrefixCache struct {
+	mu       sync.Mutex
+	prefixes map[common.Hash][]*simPrefix // parentHash -> prefixes, oldest first
+}
+
+func newSimPrefixCache() *simPrefixCache {
+	return &simPrefixCache{
+		prefixes: make(map[common.Hash][]*simPrefix),
+	}
+}
+
+// add caches a copy of env as the state after committing txs on top of parentHash.
+func (c *simPrefixCache) add(parentHash common.Hash, blockNumber uint64, txs types.Transactions, env *environment) {
+	if len(txs) == 0 {
+		return
+	}
+	hashes := make([]common.Hash, len(txs))
+	for i, tx := range txs {
+		hashes[i] = tx.Hash()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prefixes := c.prefixes[parentHash]
+	for _, p := range prefixes {
+		if sameHashes(p.txHashes, hashes) {
+			return
+		}
+	}
+	if len(prefixes) >= maxSimPrefixPerParent {
+		prefixes[0].env.discard()
+		prefixes = prefixes[1:]
+	}
+	c.prefixes[parentHash] = append(prefixes, &simPrefix{
+		blockNumber: blockNumber,
+		txHashes:    hashes,
+		env:         env.copy(),
+	})
+}
+
+// lookup returns a copy of the cached environment with the longest tx prefix shared
+// with txs, and the number of txs it covers. header is the freshly prepared header
+// of the bid, a cached environment is only reused if it was built on the same header.
+// A cached prefix is skipped if one of its txs reverted but is unRevertible in the new bid.
+func (c *simPrefixCache) lookup(parentHash common.Hash, header *types.Header, txs types.Transactions, unRevertible mapset.Set[common.Hash]) (*environment, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var best *simPrefix
+	for _, p := range c.prefixes[parentHash] {
+		if len(p.txHashes) > len(txs) || (best != nil && len(p.txHashes) <= len(best.txHashes)) {
+			continue
+		}
+		if !sameEnvHeader(p.env.header, header) || !p.matches(txs, unRevertible) {
+			continue
+		}
+		best = p
+	}
+	if best == nil {
+		bidSimPrefixMissMeter.Mark(1)
+		return nil, 0
+	}
+	bidSimPrefixHitMeter.Mark(1)
+	bidSimPrefixTxMeter.Mark(int64(len(best.txHashes)))
+
+	return best.env.copy(), len(best.txHashes)
+}
+
+// matches returns whether the prefix is a prefix of txs and satisfies the unRevertible set.
+func (p *simPrefix) matches(txs types.Transactions, unRevertible mapset.Set[common.Hash]) bool {
+	for i, hash := range p.txHashes {
+		if txs[i].Hash() != hash {
+			return false
+		}
+		if unRevertible != nil && unRevertible.Contains(hash) && p.env.receipts[i].Status == types.ReceiptStatusFailed {
+			return false
+		}
+	}
+	return true
+}
+
+// clear drops all the prefixes built for blocks no higher than blockNumber.
+func (c *simPrefixCache) clear(blockNumber uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for parentHash, prefixes := range c.prefixes {
+		if len(prefixes) == 0 || prefixes[0].blockNumber > blockNumber {
+			continue
+		}
+		for _, p := range prefixes {
+			p.env.discard()
+		}
+		delete(c.prefixes, parentHash)
+	}
+}
+
+func sameHashes(a, b []common.Hash) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// sameEnvHeader returns whether two prepared headers lead to the same execution context.
+func sameEnvHeader(a, b *types.Header) bool {
+	if a.ParentHash != b.ParentHash || a.Coinbase != b.Coinbase || a.Time != b.Time ||
+		a.GasLimit != b.GasLimit || a.Number.Cmp(b.Number) != 0 || a.Difficulty.Cmp(b.Difficulty) != 0 {
+		return false
+	}
+	if (a.BaseFee == nil) != (b.BaseFee == nil) || (a.BaseFee != nil && a.BaseFee.Cmp(b.BaseFee) != 0) {
+		return false
+	}
+	return true
+}
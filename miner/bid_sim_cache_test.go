@@ -0,0 +1,95 @@
+package miner
+
+import (
+	"math/big"
+	"testing"
+
+	mapset "github.com/deckarep/golang-set/v2"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func newPrefixTestEnv(t *testing.T, header *types.Header, txs types.Transactions, statuses ...uint64) *environment {
+	statedb, err := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	if err != nil {
+		t.Fatalf("failed to create state: %v", err)
+	}
+	env := &environment{state: statedb, header: types.CopyHeader(header), tcount: len(txs)}
+	for i, tx := range txs {
+		env.txs = append(env.txs, tx)
+		env.receipts = append(env.receipts, &types.Receipt{TxHash: tx.Hash(), Status: statuses[i]})
+	}
+	return env
+}
+
+func prefixTestTxs(n int) types.Transactions {
+	txs := make(types.Transactions, n)
+	for i := range txs {
+		txs[i] = types.NewTransaction(uint64(i), common.Address{0x01}, big.NewInt(1), 21000, big.NewInt(1), nil)
+	}
+	return txs
+}
+
+func TestSimPrefixCacheLookup(t *testing.T) {
+	var (
+		cache  = newSimPrefixCache()
+		parent = common.Hash{0x01}
+		header = &types.Header{ParentHash: parent, Number: big.NewInt(10), Difficulty: big.NewInt(2), GasLimit: 100, Time: 1}
+		txs    = prefixTestTxs(5)
+	)
+	ok := types.ReceiptStatusSuccessful
+	cache.add(parent, 10, txs[:2], newPrefixTestEnv(t, header, txs[:2], ok, ok))
+	cache.add(parent, 10, txs[:3], newPrefixTestEnv(t, header, txs[:3], ok, ok, types.ReceiptStatusFailed))
+
+	// the longest matching prefix wins
+	env, n := cache.lookup(parent, header, txs[:4], nil)
+	if env == nil || n != 3 || env.tcount != 3 {
+		t.Fatalf("lookup mismatch: have %d, want 3", n)
+	}
+	// a reverted tx which is unRevertible in the new bid rules out that prefix
+	unRevertible := mapset.NewThreadUnsafeSet[common.Hash](txs[2].Hash())
+	if _, n = cache.lookup(parent, header, txs[:4], unRevertible); n != 2 {
+		t.Fatalf("lookup with unRevertible mismatch: have %d, want 2", n)
+	}
+	// a diverging tx list only shares the common prefix
+	diverged := append(types.Transactions{}, txs[0], txs[4], txs[1])
+	if env, _ = cache.lookup(parent, header, diverged, nil); env != nil {
+		t.Fatalf("unexpected prefix for diverged txs")
+	}
+	// a different header or parent must not reuse the cached state
+	other := types.CopyHeader(header)
+	other.Time++
+	if env, _ = cache.lookup(parent, other, txs, nil); env != nil {
+		t.Fatalf("unexpected prefix for different header")
+	}
+	if env, _ = cache.lookup(common.Hash{0x02}, header, txs, nil); env != nil {
+		t.Fatalf("unexpected prefix for different parent")
+	}
+	// cleared once the block is imported
+	cache.clear(10)
+	if env, _ = cache.lookup(parent, header, txs, nil); env != nil {
+		t.Fatalf("unexpected prefix after clear")
+	}
+}
+
+func TestSimPrefixCacheEviction(t *testing.T) {
+	var (
+		cache  = newSimPrefixCache()
+		parent = common.Hash{0x01}
+		header = &types.Header{ParentHash: parent, Number: big.NewInt(10), Difficulty: big.NewInt(2), GasLimit: 100, Time: 1}
+		txs    = prefixTestTxs(maxSimPrefixPerParent + 1)
+	)
+	statuses := make([]uint64, len(txs))
+	for i := 1; i <= len(txs); i++ {
+		cache.add(parent, 10, txs[:i], newPrefixTestEnv(t, header, txs[:i], statuses[:i]...))
+	}
+	if have := len(cache.prefixes[parent]); have != maxSimPrefixPerParent {
+		t.Fatalf("cache size mismatch: have %d, want %d", have, maxSimPrefixPerParent)
+	}
+	if _, n := cache.lookup(parent, header, txs[:1], nil); n != 0 {
+		t.Fatalf("oldest prefix not evicted")
+	}
+}
@@ -30,6 +30,9 @@ import (
 const (
 	// maxBidPerBuilderPerBlock is the max bid number per builder
 	maxBidPerBuilderPerBlock = 3
+
+	// forwardPrivateTxTimeout is the timeout of forwarding a private transaction to a builder
+	forwardPrivateTxTimeout = time.Second
 )
 
 var (
@@ -100,6 +103,9 @@ type bidSimulator struct {
 	buildersMu sync.RWMutex
 	builders   map[common.Address]*builderclient.Client
 
+	// builderStats tracks the health and latency of builders
+	builderStats *builderTracker
+
 	// channels
 	simBidCh chan *simBidReq
 	newBidCh chan newBidPackage
@@ -112,6 +118,12 @@ type bidSimulator struct {
 
 	simBidMu      sync.RWMutex
 	simulatingBid map[common.Hash]*BidRuntime // prevBlockHash -> bidRuntime, in the process of simulation
+
+	// simPrefixes caches the state after simulated bid txs for prefix reuse
+	simPrefixes *simPrefixCache
+
+	// journal records arrived bids, simulation outcomes and the final choice, nil if disabled
+	journal *bidJournal
 }
 
 func newBidSimulator(
@@ -134,15 +146,25 @@ func newBidSimulator(
 		exitCh:        make(chan struct{}),
 		chainHeadCh:   make(chan core.ChainHeadEvent, chainHeadChanSize),
 		builders:      make(map[common.Address]*builderclient.Client),
+		builderStats:  newBuilderTracker(config.BuilderSuspendFailures, config.BuilderSuspendDuration),
 		simBidCh:      make(chan *simBidReq),
 		newBidCh:      make(chan newBidPackage, 100),
 		pending:       make(map[uint64]map[common.Address]map[common.Hash]struct{}),
 		bestBid:       make(map[common.Hash]*BidRuntime),
 		simulatingBid: make(map[common.Hash]*BidRuntime),
+		simPrefixes:   newSimPrefixCache(),
 	}
 
 	b.chainHeadSub = chain.SubscribeChainHeadEvent(b.chainHeadCh)
 
+	journal, err := newBidJournal(config.BidJournalDir, config.BidJournalRetention, func() uint64 {
+		return chain.CurrentBlock().Number.Uint64()
+	})
+	if err != nil {
+		log.Error("BidSimulator: failed to open bid journal", "dir", config.BidJournalDir, "err", err)
+	}
+	b.journal = journal
+
 	if config.Enabled {
 		b.bidReceiving.Store(true)
 		b.dialSentryAndBuilders()
@@ -188,6 +210,7 @@ func (b *bidSimulator) stop() {
 func (b *bidSimulator) close() {
 	b.running.Store(false)
 	close(b.exitCh)
+	b.journal.close()
 }
 
 func (b *bidSimulator) isRunning() bool {
@@ -228,6 +251,7 @@ func (b *bidSimulator) AddBuilder(builder common.Address, url string) error {
 
 		b.builders[builder] = builderCli
 	}
+	b.builderStats.add(builder)
 
 	return nil
 }
@@ -237,6 +261,7 @@ func (b *bidSimulator) RemoveBuilder(builder common.Address) error {
 	defer b.buildersMu.Unlock()
 
 	delete(b.builders, builder)
+	b.builderStats.remove(builder)
 
 	return nil
 }
@@ -250,6 +275,16 @@ func (b *bidSimulator) ExistBuilder(builder common.Address) bool {
 	return ok
 }
 
+// BuilderSuspended returns the time until which the builder is suspended, if it is.
+func (b *bidSimulator) BuilderSuspended(builder common.Address) (time.Time, bool) {
+	return b.builderStats.suspended(builder)
+}
+
+// BuilderStats returns the health and latency statistics of the builders.
+func (b *bidSimulator) BuilderStats() []*types.BuilderStats {
+	return b.builderStats.stats()
+}
+
 func (b *bidSimulator) SetBestBid(prevBlockHash common.Hash, bid *BidRuntime) {
 	b.bestBidMu.Lock()
 	defer b.bestBidMu.Unlock()
@@ -424,6 +459,8 @@ func (b *bidSimulator) clearLoop() {
 			}
 		}
 		b.simBidMu.Unlock()
+
+		b.simPrefixes.clear(blockNumber)
 	}
 
 	for head := range b.chainHeadCh {
@@ -508,8 +545,10 @@ func (b *bidSimulator) simBid(interruptCh chan int32, bidRuntime *BidRuntime) {
 		bidTxLen = len(bidTxs)
 		payBidTx = bidTxs[bidTxLen-1]
 
-		err     error
-		success bool
+		err       error
+		success   bool
+		simulated bool
+		lostTo    *BidRuntime
 	)
 
 	// ensure simulation exited then start next simulation
@@ -535,9 +574,14 @@ func (b *bidSimulator) simBid(interruptCh chan int32, bidRuntime *BidRuntime) {
 			logCtx = append(logCtx, "err", err)
 			log.Info("BidSimulator: simulation failed", logCtx...)
 
+			b.builderStats.recordSimFailure(builder, err)
 			go b.reportIssue(bidRuntime, err)
+		} else if simulated {
+			b.builderStats.recordSimSuccess(builder, success)
 		}
 
+		b.journalSimulation(bidRuntime, simulated, success, lostTo, err, time.Since(simStart))
+
 		b.RemoveSimulatingBid(parentHash)
 		close(bidRuntime.finished)
 
@@ -587,31 +631,38 @@ func (b *bidSimulator) simBid(interruptCh chan int32, bidRuntime *BidRuntime) {
 		return
 	}
 
+	// reuse the state of a previously simulated bid sharing a tx prefix, only execute the suffix.
+	// Bids with bundles may drop txs, so their committed txs don't line up with the bid txs.
+	var (
+		prefixTxs = bidRuntime.bid.Txs[:bidTxLen-1]
+		reused    int
+		cacheable = len(bidRuntime.bid.Bundles) == 0
+	)
+	if cacheable {
+		if env, n := b.simPrefixes.lookup(parentHash, bidRuntime.env.header, prefixTxs, bidRuntime.bid.UnRevertible); env != nil {
+			bidRuntime.env.discard()
+			bidRuntime.env, reused = env, n
+			log.Debug("BidSimulator: reuse simulated prefix", "builder", builder, "bidHash", bidRuntime.bid.Hash().Hex(), "reused", reused, "tx", bidTxLen)
+		}
+	}
+
 	// commit transactions in bid
-	for _, tx := range bidRuntime.bid.Txs {
+	interrupt := func() error {
 		select {
 		case <-interruptCh:
-			err = errors.New("simulation abort due to better bid arrived")
-			return
-
+			return errBetterBidArrived
 		case <-b.exitCh:
-			err = errors.New("miner exit")
-			return
-
+			return errMinerExit
 		default:
-		}
-
-		if bidRuntime.env.tcount == bidTxLen-1 {
-			break
-		}
-
-		err = bidRuntime.commitTransaction(b.chain, b.chainConfig, tx, bidRuntime.bid.UnRevertible.Contains(tx.Hash()))
-		if err != nil {
-			log.Error("BidSimulator: failed to commit tx", "bidHash", bidRuntime.bid.Hash(), "tx", tx.Hash(), "err", err)
-			err = fmt.Errorf("invalid tx in bid, %v", err)
-			return
+			return nil
 		}
 	}
+	if err = bidRuntime.commitBidTxs(b.chain, b.chainConfig, reused, bidTxLen-1, interrupt); err != nil {
+		return
+	}
+	if cacheable && reused < len(prefixTxs) {
+		b.simPrefixes.add(parentHash, blockNumber, prefixTxs, bidRuntime.env)
+	}
 
 	// check if bid reward is valid
 	{
@@ -665,6 +716,7 @@ func (b *bidSimulator) simBid(interruptCh chan int32, bidRuntime *BidRuntime) {
 		err = fmt.Errorf("invalid tx in bid, %v", err)
 		return
 	}
+	simulated = true
 
 	bestBid := b.GetBestBid(parentHash)
 	if bestBid == nil {
@@ -698,6 +750,8 @@ func (b *bidSimulator) simBid(interruptCh chan int32, bidRuntime *BidRuntime) {
 		return
 	}
 
+	lostTo = bestBid
+
 	// only recommit last best bid when newBidCh is empty
 	if len(b.newBidCh) > 0 {
 		return
@@ -710,6 +764,125 @@ func (b *bidSimulator) simBid(interruptCh chan int32, bidRuntime *BidRuntime) {
 	}
 }
 
+// journalArrival records an arrived bid and the reply to the builder.
+func (b *bidSimulator) journalArrival(args *types.BidArgs, bid *types.Bid, lead time.Duration, err error) {
+	if b.journal == nil {
+		return
+	}
+	entry := &BidJournalEntry{
+		Kind:        BidJournalArrival,
+		BlockNumber: bid.BlockNumber,
+		ParentHash:  bid.ParentHash,
+		Builder:     bid.Builder,
+		BidHash:     bid.Hash(),
+		Bid:         args,
+		ArrivalLead: lead,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	b.journal.write(entry)
+}
+
+// journalRejection records a bid of a registered builder rejected on arrival,
+// before it could be converted and simulated, with the reason of the rejection.
+// Bids with invalid signatures or from unknown builders must not be journaled.
+func (b *bidSimulator) journalRejection(args *types.BidArgs, builder common.Address, err error) {
+	if b.journal == nil || args.RawBid == nil {
+		return
+	}
+	b.journal.write(&BidJournalEntry{
+		Kind:        BidJournalArrival,
+		BlockNumber: args.RawBid.BlockNumber,
+		ParentHash:  args.RawBid.ParentHash,
+		Builder:     builder,
+		BidHash:     args.RawBid.Hash(),
+		Bid:         args,
+		Error:       err.Error(),
+	})
+}
+
+// journalSimulation records the outcome of a bid simulation.
+func (b *bidSimulator) journalSimulation(bidRuntime *BidRuntime, simulated, best bool, lostTo *BidRuntime, err error, duration time.Duration) {
+	if b.journal == nil {
+		return
+	}
+	entry := &BidJournalEntry{
+		Kind:        BidJournalSimulation,
+		BlockNumber: bidRuntime.bid.BlockNumber,
+		ParentHash:  bidRuntime.bid.ParentHash,
+		Builder:     bidRuntime.bid.Builder,
+		BidHash:     bidRuntime.bid.Hash(),
+		Best:        best,
+		Duration:    duration,
+	}
+	if bidRuntime.env != nil {
+		entry.Header = types.CopyHeader(bidRuntime.env.header)
+		entry.TxCount = bidRuntime.env.tcount
+	}
+	if simulated {
+		entry.PackedBlockReward = new(big.Int).Set(bidRuntime.packedBlockReward)
+		entry.PackedValidatorReward = new(big.Int).Set(bidRuntime.packedValidatorReward)
+	}
+	switch {
+	case err != nil:
+		entry.Error = err.Error()
+	case lostTo != nil:
+		entry.Error = fmt.Sprintf("packed block reward %s lower than best bid %s with %s", bidRuntime.packedBlockReward,
+			lostTo.bid.Hash(), lostTo.packedBlockReward)
+	}
+	b.journal.write(entry)
+}
+
+// journalChoice records the comparison between the best bid and the local work when sealing.
+func (b *bidSimulator) journalChoice(header *types.Header, bestBid *BidRuntime, localReward *big.Int, bidChosen bool) {
+	if b.journal == nil {
+		return
+	}
+	entry := &BidJournalEntry{
+		Kind:              BidJournalChoice,
+		BlockNumber:       header.Number.Uint64(),
+		ParentHash:        header.ParentHash,
+		Builder:           bestBid.bid.Builder,
+		BidHash:           bestBid.bid.Hash(),
+		PackedBlockReward: new(big.Int).Set(bestBid.packedBlockReward),
+		LocalBlockReward:  localReward,
+		BidChosen:         bidChosen,
+	}
+	if !bidChosen {
+		entry.Error = "local work is more profitable"
+	}
+	b.journal.write(entry)
+}
+
+// forwardPrivateTx sends a private transaction to every builder asynchronously
+// through their private endpoint, so it isn't propagated further, once per client
+// not to flood the mev-sentry shared by all builders.
+func (b *bidSimulator) forwardPrivateTx(tx *types.Transaction, maxBlockNumber uint64) {
+	if !b.config.ForwardPrivateTxs {
+		return
+	}
+	b.buildersMu.RLock()
+	clients := make(map[*builderclient.Client]common.Address, len(b.builders))
+	for builder, cli := range b.builders {
+		if cli != nil {
+			clients[cli] = builder
+		}
+	}
+	b.buildersMu.RUnlock()
+
+	for cli, builder := range clients {
+		go func(cli *builderclient.Client, builder common.Address) {
+			ctx, cancel := context.WithTimeout(context.Background(), forwardPrivateTxTimeout)
+			defer cancel()
+
+			if err := cli.SendPrivateTransaction(ctx, tx, maxBlockNumber); err != nil {
+				log.Debug("BidSimulator: failed to forward private transaction", "builder", builder, "hash", tx.Hash(), "err", err)
+			}
+		}(cli, builder)
+	}
+}
+
 // reportIssue reports the issue to the mev-sentry
 func (b *bidSimulator) reportIssue(bidRuntime *BidRuntime, err error) {
 	metrics.GetOrRegisterCounter(fmt.Sprintf("bid/err/%v", bidRuntime.bid.Builder), nil).Inc(1)
@@ -724,6 +897,7 @@ func (b *bidSimulator) reportIssue(bidRuntime *BidRuntime, err error) {
 		})
 
 		if err != nil {
+			b.builderStats.recordRPCError(bidRuntime.bid.Builder, err)
 			log.Warn("BidSimulator: failed to report issue", "builder", bidRuntime.bid.Builder, "err", err)
 		}
 	}
@@ -788,6 +962,145 @@ func (r *BidRuntime) packReward(validatorCommission uint64) {
 	r.packedValidatorReward.Sub(r.packedValidatorReward, r.bid.BuilderFee)
 }
 
+// commitBidTxs commits the bid txs in [from, to), the txs of bundles are committed all together or
+// not at all. A bundle crossing to, i.e. overlapping the payBidTx, can't be committed atomically and
+// rejects the bid. interrupt is checked before every tx or bundle, and aborts the commit if it returns
+// an error.
+func (r *BidRuntime) commitBidTxs(chain *core.BlockChain, chainConfig *params.ChainConfig, from, to int, interrupt func() error) error {
+	for i := from; i < to; {
+		if interrupt != nil {
+			if err := interrupt(); err != nil {
+				return err
+			}
+		}
+
+		if bundle := r.bid.BundleAt(i); bundle != nil {
+			if bundle.End > to {
+				return fmt.Errorf("invalid bundle in bid, bundle at tx %d overlaps the payBidTx", bundle.Start)
+			}
+			if err := r.commitBundle(chain, chainConfig, bundle); err != nil {
+				log.Error("BidSimulator: failed to commit bundle", "bidHash", r.bid.Hash(), "start", bundle.Start, "err", err)
+				return fmt.Errorf("invalid bundle in bid, %v", err)
+			}
+			i = bundle.End
+			continue
+		}
+
+		tx := r.bid.Txs[i]
+		if err := r.commitTransaction(chain, chainConfig, tx, r.bid.UnRevertible.Contains(tx.Hash())); err != nil {
+			log.Error("BidSimulator: failed to commit tx", "bidHash", r.bid.Hash(), "tx", tx.Hash(), "err", err)
+			return fmt.Errorf("invalid tx in bid, %v", err)
+		}
+		i++
+	}
+	return nil
+}
+
+// commitBundle commits the txs of a bundle. If a tx of the bundle fails or reverts, the state is
+// reverted to the start of the bundle and the bundle is excluded, unless the tx is a dropping tx,
+// then only the tx itself is reverted. It returns an error only if an unRevertible tx failed.
+func (r *BidRuntime) commitBundle(chain *core.BlockChain, chainConfig *params.ChainConfig, bundle *types.BundleSpan) error {
+	start := r.checkpoint()
+
+	for _, tx := range r.bid.Txs[bundle.Start:bundle.End] {
+		var (
+			hash     = tx.Hash()
+			dropping = bundle.Dropping.Contains(hash)
+			txStart  *envCheckpoint
+		)
+		// a reverted tx is already finalised in the state, only a checkpoint taken before can undo it
+		if dropping {
+			txStart = r.checkpoint()
+		}
+
+		err := r.commitTransaction(chain, chainConfig, tx, r.bid.UnRevertible.Contains(hash))
+		if err == nil && r.env.receipts[len(r.env.receipts)-1].Status == types.ReceiptStatusFailed {
+			err = errors.New("transaction reverted")
+		}
+		if err == nil {
+			if dropping {
+				r.discard(txStart)
+			}
+			continue
+		}
+		if r.bid.UnRevertible.Contains(hash) {
+			if dropping {
+				r.discard(txStart)
+			}
+			r.discard(start)
+			return err
+		}
+		if dropping {
+			log.Debug("BidSimulator: drop tx in bundle", "bidHash", r.bid.Hash(), "tx", hash, "err", err)
+			r.revert(txStart)
+			continue
+		}
+		log.Debug("BidSimulator: drop bundle", "bidHash", r.bid.Hash(), "start", bundle.Start, "tx", hash, "err", err)
+		r.revert(start)
+		return nil
+	}
+	r.discard(start)
+	return nil
+}
+
+// envCheckpoint is a point of the bid environment which applied txs can be reverted to.
+// The state is tracked by a multi tx snapshot of the StateDB, checkpoints must be either
+// reverted or discarded, the last taken first.
+type envCheckpoint struct {
+	gas         uint64
+	gasUsed     uint64
+	blobGasUsed uint64
+	tcount      int
+	txs         int
+	receipts    int
+	sidecars    int
+	blobs       int
+}
+
+// checkpoint takes a checkpoint of the environment. StateDB snapshots can't be used here,
+// since the journal is cleared once a tx is finalised.
+func (r *BidRuntime) checkpoint() *envCheckpoint {
+	env := r.env
+	env.state.MultiTxSnapshot()
+	cp := &envCheckpoint{
+		gas:      env.gasPool.Gas(),
+		gasUsed:  env.header.GasUsed,
+		tcount:   env.tcount,
+		txs:      len(env.txs),
+		receipts: len(env.receipts),
+		sidecars: len(env.sidecars),
+		blobs:    env.blobs,
+	}
+	if env.header.BlobGasUsed != nil {
+		cp.blobGasUsed = *env.header.BlobGasUsed
+	}
+	return cp
+}
+
+// revert restores the environment to the checkpoint.
+func (r *BidRuntime) revert(cp *envCheckpoint) {
+	env := r.env
+	env.state.RevertMultiTxSnapshot()
+	env.gasPool.SetGas(cp.gas)
+	env.header.GasUsed = cp.gasUsed
+	if env.header.BlobGasUsed != nil {
+		*env.header.BlobGasUsed = cp.blobGasUsed
+	}
+	env.tcount = cp.tcount
+	env.txs = env.txs[:cp.txs]
+	env.receipts = env.receipts[:cp.receipts]
+	env.sidecars = env.sidecars[:cp.sidecars]
+	env.blobs = cp.blobs
+}
+
+// discard drops the checkpoint, keeping the txs applied since.
+func (r *BidRuntime) discard(*envCheckpoint) {
+	r.env.state.DiscardMultiTxSnapshot()
+}
+
+// commitTransaction applies the tx on the environment. The state and gas are reverted
+// if the tx fails. An unRevertible tx which reverted is left applied: it rejects the
+// whole bid, only bundles need to undo reverted txs and they take their own checkpoints.
 func (r *BidRuntime) commitTransaction(chain *core.BlockChain, chainConfig *params.ChainConfig, tx *types.Transaction, unRevertible bool) error {
 	var (
 		env = r.env
@@ -811,11 +1124,18 @@ func (r *BidRuntime) commitTransaction(chain *core.BlockChain, chainConfig *para
 		}
 	}
 
+	var (
+		snap = env.state.Snapshot()
+		gp   = env.gasPool.Gas()
+	)
 	receipt, err := core.ApplyTransaction(chainConfig, chain, &env.coinbase, env.gasPool, env.state, env.header, tx,
 		&env.header.GasUsed, *chain.GetVMConfig(), core.NewReceiptBloomGenerator())
 	if err != nil {
+		env.state.RevertToSnapshot(snap)
+		env.gasPool.SetGas(gp)
 		return err
 	} else if unRevertible && receipt.Status == types.ReceiptStatusFailed {
+		// the whole bid is rejected, so the environment is discarded without reverting
 		return errors.New("no revertible transaction failed")
 	}
 
@@ -3,6 +3,7 @@ package builderclient
 import (
 	"context"
 
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rpc"
 )
@@ -31,3 +32,20 @@ func newClient(c *rpc.Client) *Client {
 func (ec *Client) ReportIssue(ctx context.Context, args *types.BidIssue) error {
 	return ec.c.CallContext(ctx, nil, "mev_reportIssue", args)
 }
+
+// privateTransactionArgs are the arguments of eth_sendPrivateTransaction.
+type privateTransactionArgs struct {
+	Tx             hexutil.Bytes  `json:"tx"`
+	MaxBlockNumber hexutil.Uint64 `json:"maxBlockNumber"`
+}
+
+// SendPrivateTransaction sends a signed transaction to the builder, which must not
+// propagate it, includable until the given block number
+func (ec *Client) SendPrivateTransaction(ctx context.Context, tx *types.Transaction, maxBlockNumber uint64) error {
+	data, err := tx.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	args := &privateTransactionArgs{Tx: data, MaxBlockNumber: hexutil.Uint64(maxBlockNumber)}
+	return ec.c.CallContext(ctx, nil, "eth_sendPrivateTransaction", args)
+}
@@ -56,6 +56,7 @@ type Config struct {
 
 	NewPayloadTimeout      time.Duration // The maximum time allowance for creating a new payload
 	DisableVoteAttestation bool          // Whether to skip assembling vote attestation
+	SkipBlacklisted        bool          // Whether to leave the transactions touching the txpool blacklist out
 
 	Mev MevConfig // Mev configuration
 }
@@ -25,6 +25,14 @@ type MevConfig struct {
 	Builders              []BuilderConfig // The list of builders
 	ValidatorCommission   uint64          // 100 means the validator claims 1% from block reward
 	BidSimulationLeftOver time.Duration
+
+	BuilderSuspendFailures uint64        // Consecutive failed simulations, late bids or rpc errors before a builder is suspended, 0 disables suspension
+	BuilderSuspendDuration time.Duration // How long a builder stays suspended
+
+	BidJournalDir       string // Directory of the bid journal under the datadir, empty disables journaling
+	BidJournalRetention uint64 // Number of recent blocks whose bid journal is kept, 0 keeps all
+
+	ForwardPrivateTxs bool // Whether to forward the privately submitted transactions to the builders
 }
 
 var DefaultMevConfig = MevConfig{
@@ -33,6 +41,11 @@ var DefaultMevConfig = MevConfig{
 	Builders:              nil,
 	ValidatorCommission:   100,
 	BidSimulationLeftOver: 50 * time.Millisecond,
+
+	BuilderSuspendFailures: 10,
+	BuilderSuspendDuration: time.Minute,
+
+	BidJournalRetention: 1200,
 }
 
 // MevRunning return true if mev is running.
@@ -65,6 +78,12 @@ func (miner *Miner) HasBuilder(builder common.Address) bool {
 	return miner.bidSimulator.ExistBuilder(builder)
 }
 
+// ForwardPrivateTx sends a privately submitted transaction to the builders, if
+// enabled, so they may include it in their bids until the given block number.
+func (miner *Miner) ForwardPrivateTx(tx *types.Transaction, maxBlockNumber uint64) {
+	miner.bidSimulator.forwardPrivateTx(tx, maxBlockNumber)
+}
+
 func (miner *Miner) SendBid(ctx context.Context, bidArgs *types.BidArgs) (common.Hash, error) {
 	builder, err := bidArgs.EcrecoverSender()
 	if err != nil {
@@ -75,26 +94,39 @@ func (miner *Miner) SendBid(ctx context.Context, bidArgs *types.BidArgs) (common
 		return common.Hash{}, types.NewInvalidBidError("builder is not registered")
 	}
 
+	if until, suspended := miner.bidSimulator.BuilderSuspended(builder); suspended {
+		err = types.NewInvalidBidError(fmt.Sprintf("builder is suspended until %s", until))
+		miner.bidSimulator.journalRejection(bidArgs, builder, err)
+		return common.Hash{}, err
+	}
+
 	err = miner.bidSimulator.CheckPending(bidArgs.RawBid.BlockNumber, builder, bidArgs.RawBid.Hash())
 	if err != nil {
+		miner.bidSimulator.journalRejection(bidArgs, builder, err)
 		return common.Hash{}, err
 	}
 
 	signer := types.MakeSigner(miner.worker.chainConfig, big.NewInt(int64(bidArgs.RawBid.BlockNumber)), uint64(time.Now().Unix()))
 	bid, err := bidArgs.ToBid(builder, signer)
 	if err != nil {
-		return common.Hash{}, types.NewInvalidBidError(fmt.Sprintf("fail to convert bidArgs to bid, %v", err))
+		err = types.NewInvalidBidError(fmt.Sprintf("fail to convert bidArgs to bid, %v", err))
+		miner.bidSimulator.journalRejection(bidArgs, builder, err)
+		return common.Hash{}, err
 	}
 
 	bidBetterBefore := miner.bidSimulator.bidBetterBefore(bidArgs.RawBid.ParentHash)
 	timeout := time.Until(bidBetterBefore)
+	miner.bidSimulator.builderStats.recordArrival(builder, timeout)
 
 	if timeout <= 0 {
-		return common.Hash{}, fmt.Errorf("too late, expected befor %s, appeared %s later", bidBetterBefore,
+		err = fmt.Errorf("too late, expected befor %s, appeared %s later", bidBetterBefore,
 			common.PrettyDuration(timeout))
+		miner.bidSimulator.journalArrival(bidArgs, bid, timeout, err)
+		return common.Hash{}, err
 	}
 
 	err = miner.bidSimulator.sendBid(ctx, bid)
+	miner.bidSimulator.journalArrival(bidArgs, bid, timeout, err)
 
 	if err != nil {
 		return common.Hash{}, err
@@ -103,6 +135,11 @@ func (miner *Miner) SendBid(ctx context.Context, bidArgs *types.BidArgs) (common
 	return bid.Hash(), nil
 }
 
+// BuilderStats returns the health and latency statistics of the builders.
+func (miner *Miner) BuilderStats() []*types.BuilderStats {
+	return miner.bidSimulator.BuilderStats()
+}
+
 func (miner *Miner) BestPackedBlockReward(parentHash common.Hash) *big.Int {
 	bidRuntime := miner.bidSimulator.GetBestBid(parentHash)
 	if bidRuntime == nil {
@@ -175,6 +175,7 @@ type getWorkReq struct {
 type bidFetcher interface {
 	GetBestBid(parentHash common.Hash) *BidRuntime
 	GetSimulatingBid(prevBlockHash common.Hash) *BidRuntime
+	journalChoice(header *types.Header, bestBid *BidRuntime, localReward *big.Int, bidChosen bool)
 }
 
 // worker is the main object which takes care of submitting new work to consensus engine
@@ -906,6 +907,14 @@ LOOP:
 			txs.Pop()
 			continue
 		}
+		// The blacklist is a local policy, only applied to the blocks built by ourselves
+		if w.config.SkipBlacklisted {
+			if err := w.eth.TxPool().Blacklist().Check(from, tx.To()); err != nil {
+				log.Trace("Ignoring blacklisted transaction", "hash", ltx.Hash, "sender", from)
+				txs.Pop()
+				continue
+			}
+		}
 		// Start executing the transaction
 		env.state.SetTxContext(tx.Hash(), env.tcount)
 
@@ -1043,17 +1052,22 @@ func (w *worker) prepareWork(genParams *generateParams) (*environment, error) {
 		return nil, err
 	}
 
-	if !w.chainConfig.IsFeynman(header.Number, header.Time) {
+	preExecute(w.chainConfig, w.chain, parent, header, env.state)
+	return env, nil
+}
+
+// preExecute applies the state changes which take place before the first tx of the block.
+func preExecute(chainConfig *params.ChainConfig, chain *core.BlockChain, parent, header *types.Header, statedb *state.StateDB) {
+	if !chainConfig.IsFeynman(header.Number, header.Time) {
 		// Handle upgrade build-in system contract code
-		systemcontracts.UpgradeBuildInSystemContract(w.chainConfig, header.Number, parent.Time, header.Time, env.state)
+		systemcontracts.UpgradeBuildInSystemContract(chainConfig, header.Number, parent.Time, header.Time, statedb)
 	}
 
 	if header.ParentBeaconRoot != nil {
-		context := core.NewEVMBlockContext(header, w.chain, nil)
-		vmenv := vm.NewEVM(context, vm.TxContext{}, env.state, w.chainConfig, vm.Config{})
-		core.ProcessBeaconBlockRoot(*header.ParentBeaconRoot, vmenv, env.state)
+		context := core.NewEVMBlockContext(header, chain, nil)
+		vmenv := vm.NewEVM(context, vm.TxContext{}, statedb, chainConfig, vm.Config{})
+		core.ProcessBeaconBlockRoot(*header.ParentBeaconRoot, vmenv, statedb)
 	}
-	return env, nil
 }
 
 // fillTransactions retrieves the pending transactions from the txpool and fills them
@@ -1357,6 +1371,7 @@ LOOP:
 				"bidBlockReward", bestBid.packedBlockReward.String())
 		}
 
+		bidChosen := false
 		if bestBid != nil && bestReward.CmpBig(bestBid.packedBlockReward) < 0 {
 			// localValidatorReward is the reward for the validator self by the local block.
 			localValidatorReward := new(uint256.Int).Mul(bestReward, uint256.NewInt(w.config.Mev.ValidatorCommission))
@@ -1370,6 +1385,7 @@ LOOP:
 			if localValidatorReward.CmpBig(bestBid.packedValidatorReward) < 0 {
 				bestWork = bestBid.env
 				from = bestBid.bid.Builder
+				bidChosen = true
 
 				log.Info("[BUILDER BLOCK]",
 					"block", bestWork.header.Number.Uint64(),
@@ -1380,6 +1396,9 @@ LOOP:
 				)
 			}
 		}
+		if bestBid != nil {
+			w.bidFetcher.journalChoice(bestWork.header, bestBid, bestReward.ToBig(), bidChosen)
+		}
 	}
 
 	metrics.GetOrRegisterCounter(fmt.Sprintf("block/from/%v", from), nil).Inc(1)
@@ -19,6 +19,7 @@ package node
 import (
 	"crypto/ecdsa"
 	"fmt"
+	"math/big"
 	"net"
 	"os"
 	"path/filepath"
@@ -215,6 +216,19 @@ type Config struct {
 	// EnableMaliciousVoteMonitor is a flag that whether to enable the malicious vote checker
 	EnableMaliciousVoteMonitor bool `toml:",omitempty"`
 
+	// EvidenceReporter is the unlocked account which signs and submits the slashing evidences
+	// found by the monitors to the SlashIndicator contract, no submission if empty
+	EvidenceReporter common.Address `toml:",omitempty"`
+
+	// EvidenceGasLimit is the gas limit of the evidence submission txs, 0 for the default
+	EvidenceGasLimit uint64 `toml:",omitempty"`
+
+	// EvidenceGasPrice is the gas price of the evidence submission txs, nil for the txpool gas tip
+	EvidenceGasPrice *big.Int `toml:",omitempty"`
+
+	// EvidenceDryRun signs the evidence submission txs without sending them
+	EvidenceDryRun bool `toml:",omitempty"`
+
 	// BLSPasswordFile is the file that contains BLS wallet password.
 	BLSPasswordFile string `toml:",omitempty"`
 
@@ -223,6 +237,14 @@ type Config struct {
 	// current directory.
 	BLSWalletDir string `toml:",omitempty"`
 
+	// BLSRemoteSigner is the URL of a Web3Signer compatible remote signer, which
+	// holds the BLS key instead of the local wallet.
+	BLSRemoteSigner string `toml:",omitempty"`
+
+	// BLSRemoteSignerKey is the BLS public key to vote with, required if the remote
+	// signer holds more than one key.
+	BLSRemoteSignerKey string `toml:",omitempty"`
+
 	// VoteJournalDir is the directory to store votes in the fast finality feature.
 	VoteJournalDir string `toml:",omitempty"`
 
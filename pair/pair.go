@@ -34,6 +34,9 @@ var From = common.HexToAddress("0xcdecF7Ab7c6654139F65c6C1C7Ecbad653F0dfB0")
 
 var To = common.HexToAddress("0x84F7f6016e5ED7819f717994225D4f60c7Af5359")
 
+// GasBudget 单个区块内套利交易可使用的gas预算，为0时使用最新区块的gasLimit
+var GasBudget uint64 = 0
+
 func init() {
 	// 初始化triange到内存
 	triangleStart := time.Now()
@@ -0,0 +1,321 @@
+package pairtypes
+
+import (
+	"math/big"
+	"sort"
+)
+
+// ExactSelectionLimit 候选机会数量不超过该值时使用分支定界精确求解最大权独立集，超过时使用启发式算法
+const ExactSelectionLimit = 20
+
+// maxImproveRounds 启发式局部交换优化的最大轮数
+const maxImproveRounds = 8
+
+// Candidate 参与选择的一个套利机会，Pairs 为该机会涉及的 pair 地址，共享任意 pair 的两个机会互相冲突
+type Candidate struct {
+	Pairs  []string
+	Profit *big.Int
+	Gas    uint64
+}
+
+// TriangleCandidate 根据三角路径构造候选机会
+func TriangleCandidate(triangle Triangle, profit *big.Int, gas uint64) Candidate {
+	return Candidate{
+		Pairs:  []string{triangle.Pair0, triangle.Pair1, triangle.Pair2},
+		Profit: profit,
+		Gas:    gas,
+	}
+}
+
+// Selection 选择结果，Selected 与 Dropped 均为传入候选列表中的下标，按利润降序排列
+type Selection struct {
+	Selected []int
+	Dropped  []int
+	Profit   *big.Int // 选中机会的利润总和
+	Forgone  *big.Int // 未选中机会的利润总和，即为避免冲突和满足gas预算而放弃的利润
+	Gas      uint64   // 选中机会的gas总和
+	Exact    bool     // 是否为精确解
+}
+
+// conflictGraph pair 冲突图，两个候选机会共享任意 pair 时存在一条边
+type conflictGraph struct {
+	candidates []Candidate
+	adj        [][]int
+	conflicts  []map[int]struct{}
+}
+
+func newConflictGraph(candidates []Candidate) *conflictGraph {
+	g := &conflictGraph{
+		candidates: candidates,
+		adj:        make([][]int, len(candidates)),
+		conflicts:  make([]map[int]struct{}, len(candidates)),
+	}
+	for i := range candidates {
+		g.conflicts[i] = make(map[int]struct{})
+	}
+	// 通过 pair -> 候选下标 索引建边，避免两两比较
+	owners := make(map[string][]int)
+	for i, c := range candidates {
+		seen := make(map[string]struct{}, len(c.Pairs))
+		for _, p := range c.Pairs {
+			if _, ok := seen[p]; ok {
+				continue
+			}
+			seen[p] = struct{}{}
+			for _, j := range owners[p] {
+				if _, ok := g.conflicts[i][j]; ok {
+					continue
+				}
+				g.conflicts[i][j] = struct{}{}
+				g.conflicts[j][i] = struct{}{}
+				g.adj[i] = append(g.adj[i], j)
+				g.adj[j] = append(g.adj[j], i)
+			}
+			owners[p] = append(owners[p], i)
+		}
+	}
+	return g
+}
+
+func (g *conflictGraph) conflict(i, j int) bool {
+	_, ok := g.conflicts[i][j]
+	return ok
+}
+
+// fits 判断在已用gas基础上再加入 gas 是否仍在预算内，预算为0表示不限制
+func fits(used, gas, budget uint64) bool {
+	if budget == 0 {
+		return true
+	}
+	return used+gas >= used && used+gas <= budget
+}
+
+// SelectOpportunities 在 pair 冲突图上求解满足 gas 预算的最大权独立集：
+// 候选数量不超过 ExactSelectionLimit 时精确求解，否则使用贪心加局部交换的启发式算法。
+// gasBudget 为0表示不限制gas。利润非正或单个gas已超出预算的候选直接放弃。
+func SelectOpportunities(candidates []Candidate, gasBudget uint64) *Selection {
+	order := make([]int, len(candidates))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return profitOf(candidates[order[a]]).Cmp(profitOf(candidates[order[b]])) > 0
+	})
+	// 过滤掉不可能被选中的候选，保持利润降序
+	var usable []int
+	for _, i := range order {
+		c := candidates[i]
+		if profitOf(c).Sign() <= 0 || !fits(0, c.Gas, gasBudget) {
+			continue
+		}
+		usable = append(usable, i)
+	}
+	sub := make([]Candidate, len(usable))
+	for i, idx := range usable {
+		sub[i] = candidates[idx]
+	}
+	g := newConflictGraph(sub)
+
+	var (
+		chosen []int
+		exact  = len(sub) <= ExactSelectionLimit
+	)
+	if exact {
+		chosen = g.exact(gasBudget)
+	} else {
+		chosen = g.heuristic(gasBudget)
+	}
+
+	selection := &Selection{Profit: new(big.Int), Forgone: new(big.Int), Exact: exact}
+	picked := make(map[int]struct{}, len(chosen))
+	for _, i := range chosen {
+		picked[usable[i]] = struct{}{}
+	}
+	for _, i := range order {
+		c := candidates[i]
+		if _, ok := picked[i]; ok {
+			selection.Selected = append(selection.Selected, i)
+			selection.Profit.Add(selection.Profit, c.Profit)
+			selection.Gas += c.Gas
+			continue
+		}
+		selection.Dropped = append(selection.Dropped, i)
+		if profitOf(c).Sign() > 0 {
+			selection.Forgone.Add(selection.Forgone, c.Profit)
+		}
+	}
+	return selection
+}
+
+func profitOf(c Candidate) *big.Int {
+	if c.Profit == nil {
+		return new(big.Int)
+	}
+	return c.Profit
+}
+
+// exact 分支定界求精确解，候选已按利润降序排列，剩余利润之和作为上界剪枝
+func (g *conflictGraph) exact(budget uint64) []int {
+	n := len(g.candidates)
+	suffix := make([]*big.Int, n+1)
+	suffix[n] = new(big.Int)
+	for i := n - 1; i >= 0; i-- {
+		suffix[i] = new(big.Int).Add(suffix[i+1], g.candidates[i].Profit)
+	}
+	var (
+		best       []int
+		bestProfit = new(big.Int)
+		current    []int
+		bound      = new(big.Int)
+	)
+	var search func(i int, gas uint64, profit *big.Int)
+	search = func(i int, gas uint64, profit *big.Int) {
+		if profit.Cmp(bestProfit) > 0 {
+			bestProfit.Set(profit)
+			best = append(best[:0], current...)
+		}
+		if i == n {
+			return
+		}
+		if bound.Add(profit, suffix[i]).Cmp(bestProfit) <= 0 {
+			return
+		}
+		// 优先尝试选中当前候选
+		c := g.candidates[i]
+		if fits(gas, c.Gas, budget) {
+			free := true
+			for _, j := range current {
+				if g.conflict(i, j) {
+					free = false
+					break
+				}
+			}
+			if free {
+				current = append(current, i)
+				search(i+1, gas+c.Gas, new(big.Int).Add(profit, c.Profit))
+				current = current[:len(current)-1]
+			}
+		}
+		search(i+1, gas, profit)
+	}
+	search(0, 0, new(big.Int))
+	return best
+}
+
+// heuristic 分别按利润和按利润/(度+1)排序贪心求解，取较优者后做局部交换优化
+func (g *conflictGraph) heuristic(budget uint64) []int {
+	n := len(g.candidates)
+	byProfit := make([]int, n)
+	for i := range byProfit {
+		byProfit[i] = i
+	}
+	byRatio := make([]int, n)
+	copy(byRatio, byProfit)
+	sort.SliceStable(byRatio, func(a, b int) bool {
+		i, j := byRatio[a], byRatio[b]
+		// profit_i/(deg_i+1) > profit_j/(deg_j+1) 等价于 profit_i*(deg_j+1) > profit_j*(deg_i+1)
+		l := new(big.Int).Mul(g.candidates[i].Profit, big.NewInt(int64(len(g.adj[j])+1)))
+		r := new(big.Int).Mul(g.candidates[j].Profit, big.NewInt(int64(len(g.adj[i])+1)))
+		return l.Cmp(r) > 0
+	})
+
+	best, bestProfit := g.greedy(byProfit, budget)
+	if set, profit := g.greedy(byRatio, budget); profit.Cmp(bestProfit) > 0 {
+		best = set
+	}
+	g.improve(best, byProfit, budget)
+
+	var chosen []int
+	for i := 0; i < n; i++ {
+		if best[i] {
+			chosen = append(chosen, i)
+		}
+	}
+	return chosen
+}
+
+// greedy 按给定顺序依次加入不冲突且不超预算的候选
+func (g *conflictGraph) greedy(order []int, budget uint64) ([]bool, *big.Int) {
+	var (
+		set    = make([]bool, len(g.candidates))
+		gas    uint64
+		profit = new(big.Int)
+	)
+	for _, i := range order {
+		if g.addable(set, i, gas, budget) {
+			set[i] = true
+			gas += g.candidates[i].Gas
+			profit.Add(profit, g.candidates[i].Profit)
+		}
+	}
+	return set, profit
+}
+
+func (g *conflictGraph) addable(set []bool, i int, gas, budget uint64) bool {
+	if set[i] || !fits(gas, g.candidates[i].Gas, budget) {
+		return false
+	}
+	for _, j := range g.adj[i] {
+		if set[j] {
+			return false
+		}
+	}
+	return true
+}
+
+// improve 局部交换：若某个未选中候选的利润大于与其冲突的已选候选利润之和，
+// 且交换后不超出预算，则用它替换这些冲突候选，随后再尝试补充空闲候选
+func (g *conflictGraph) improve(set []bool, order []int, budget uint64) {
+	gasOf := func() uint64 {
+		var gas uint64
+		for i, ok := range set {
+			if ok {
+				gas += g.candidates[i].Gas
+			}
+		}
+		return gas
+	}
+	for round := 0; round < maxImproveRounds; round++ {
+		changed := false
+		for _, i := range order {
+			if set[i] {
+				continue
+			}
+			var (
+				lost     = new(big.Int)
+				freedGas uint64
+				victims  []int
+			)
+			for _, j := range g.adj[i] {
+				if set[j] {
+					victims = append(victims, j)
+					lost.Add(lost, g.candidates[j].Profit)
+					freedGas += g.candidates[j].Gas
+				}
+			}
+			if len(victims) == 0 || g.candidates[i].Profit.Cmp(lost) <= 0 {
+				continue
+			}
+			if !fits(gasOf()-freedGas, g.candidates[i].Gas, budget) {
+				continue
+			}
+			for _, j := range victims {
+				set[j] = false
+			}
+			set[i] = true
+			changed = true
+		}
+		// 交换后释放出的 pair 与 gas 可能容纳新的候选
+		gas := gasOf()
+		for _, i := range order {
+			if g.addable(set, i, gas, budget) {
+				set[i] = true
+				gas += g.candidates[i].Gas
+				changed = true
+			}
+		}
+		if !changed {
+			return
+		}
+	}
+}
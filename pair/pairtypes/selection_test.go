@@ -0,0 +1,154 @@
+package pairtypes
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func candidate(profit int64, gas uint64, pairs ...string) Candidate {
+	return Candidate{Pairs: pairs, Profit: big.NewInt(profit), Gas: gas}
+}
+
+func TestSelectPrefersTwoMidProfitOverOneLarge(t *testing.T) {
+	candidates := []Candidate{
+		candidate(100, 1, "a", "b", "c"),
+		candidate(60, 1, "a", "d", "e"),
+		candidate(70, 1, "b", "f", "g"),
+	}
+	sel := SelectOpportunities(candidates, 0)
+	if !sel.Exact {
+		t.Fatalf("expected exact selection")
+	}
+	if sel.Profit.Int64() != 130 {
+		t.Fatalf("profit mismatch: have %v, want 130", sel.Profit)
+	}
+	if sel.Forgone.Int64() != 100 {
+		t.Fatalf("forgone mismatch: have %v, want 100", sel.Forgone)
+	}
+	if len(sel.Selected) != 2 || sel.Selected[0] != 2 || sel.Selected[1] != 1 {
+		t.Fatalf("selected mismatch: have %v, want [2 1]", sel.Selected)
+	}
+	if len(sel.Dropped) != 1 || sel.Dropped[0] != 0 {
+		t.Fatalf("dropped mismatch: have %v, want [0]", sel.Dropped)
+	}
+}
+
+func TestSelectRespectsGasBudget(t *testing.T) {
+	candidates := []Candidate{
+		candidate(100, 600, "a"),
+		candidate(60, 500, "b"),
+		candidate(50, 500, "c"),
+		candidate(10, 2000, "d"),
+	}
+	sel := SelectOpportunities(candidates, 1000)
+	if sel.Profit.Int64() != 110 || sel.Gas != 1000 {
+		t.Fatalf("selection mismatch: profit %v gas %d, want 110 1000", sel.Profit, sel.Gas)
+	}
+	if sel.Forgone.Int64() != 110 {
+		t.Fatalf("forgone mismatch: have %v, want 110", sel.Forgone)
+	}
+}
+
+func TestSelectSkipsUnprofitable(t *testing.T) {
+	candidates := []Candidate{
+		candidate(0, 1, "a"),
+		candidate(-5, 1, "b"),
+		{Pairs: []string{"c"}, Gas: 1},
+	}
+	sel := SelectOpportunities(candidates, 0)
+	if len(sel.Selected) != 0 || len(sel.Dropped) != 3 || sel.Forgone.Sign() != 0 {
+		t.Fatalf("unexpected selection: %+v", sel)
+	}
+}
+
+// bruteForce enumerates every subset to find the optimal independent set.
+func bruteForce(candidates []Candidate, budget uint64) int64 {
+	var best int64
+	for mask := 0; mask < 1<<len(candidates); mask++ {
+		var (
+			used   = make(map[string]bool)
+			profit int64
+			gas    uint64
+			ok     = true
+		)
+		for i, c := range candidates {
+			if mask&(1<<i) == 0 {
+				continue
+			}
+			for _, p := range c.Pairs {
+				if used[p] {
+					ok = false
+				}
+				used[p] = true
+			}
+			profit += c.Profit.Int64()
+			gas += c.Gas
+		}
+		if ok && (budget == 0 || gas <= budget) && profit > best {
+			best = profit
+		}
+	}
+	return best
+}
+
+func randomCandidates(r *rand.Rand, n, pairs int) []Candidate {
+	candidates := make([]Candidate, n)
+	for i := range candidates {
+		perm := r.Perm(pairs)
+		candidates[i] = candidate(r.Int63n(1000)+1, uint64(r.Intn(100)+1),
+			fmt.Sprint(perm[0]), fmt.Sprint(perm[1]), fmt.Sprint(perm[2]))
+	}
+	return candidates
+}
+
+func TestSelectExactMatchesBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for round := 0; round < 50; round++ {
+		candidates := randomCandidates(r, 12, 15)
+		budget := uint64(r.Intn(400))
+		sel := SelectOpportunities(candidates, budget)
+		if want := bruteForce(candidates, budget); sel.Profit.Int64() != want {
+			t.Fatalf("round %d: profit mismatch: have %v, want %d", round, sel.Profit, want)
+		}
+	}
+}
+
+func TestSelectHeuristicIsValid(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	candidates := randomCandidates(r, 200, 150)
+	budget := uint64(3000)
+	sel := SelectOpportunities(candidates, budget)
+	if sel.Exact {
+		t.Fatalf("expected heuristic selection for %d candidates", len(candidates))
+	}
+	var (
+		used   = make(map[string]bool)
+		gas    uint64
+		profit = new(big.Int)
+		total  = new(big.Int)
+	)
+	for _, i := range sel.Selected {
+		for _, p := range candidates[i].Pairs {
+			if used[p] {
+				t.Fatalf("pair %s selected twice", p)
+			}
+			used[p] = true
+		}
+		gas += candidates[i].Gas
+		profit.Add(profit, candidates[i].Profit)
+	}
+	if gas > budget || gas != sel.Gas {
+		t.Fatalf("gas mismatch: have %d, reported %d, budget %d", gas, sel.Gas, budget)
+	}
+	if profit.Cmp(sel.Profit) != 0 {
+		t.Fatalf("profit mismatch: have %v, reported %v", profit, sel.Profit)
+	}
+	for _, c := range candidates {
+		total.Add(total, c.Profit)
+	}
+	if new(big.Int).Add(sel.Profit, sel.Forgone).Cmp(total) != 0 {
+		t.Fatalf("selected and forgone profit do not add up to the total")
+	}
+}